@@ -2,16 +2,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"MyBlog/internal/cache"
 	"MyBlog/internal/config"
 	"MyBlog/internal/database"
 	"MyBlog/internal/handler"
+	"MyBlog/internal/middleware"
 	"MyBlog/internal/repository"
 	"MyBlog/internal/router"
+	"MyBlog/internal/scheduler"
 	"MyBlog/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -33,39 +38,191 @@ func main() {
 		log.Fatal("数据库初始化失败:", err)
 	}
 
-	// 运行数据库迁移（仅在生产环境）
+	// 表结构由GORM自动迁移创建和补齐（所有运行模式）
+	if err := database.AutoMigrateWithFix(db); err != nil {
+		log.Fatal("GORM自动迁移失败:", err)
+	}
+
+	// 生产环境再运行golang-migrate，执行模型标签无法表达的结构变更
 	if cfg.Server.Mode != "debug" {
 		if err := database.RunMigrations(cfg); err != nil {
 			log.Fatal("数据库迁移失败:", err)
 		}
 	} else {
-		log.Println("开发模式：跳过golang-migrate迁移，使用GORM AutoMigrate")
-		// 开发模式使用GORM自动迁移
-		if err := database.AutoMigrateWithFix(db); err != nil {
-			log.Fatal("GORM自动迁移失败:", err)
-		}
+		log.Println("开发模式：跳过golang-migrate迁移")
 	}
 
 	// 初始化依赖注入
 	userRepo := repository.NewUserRepository(db)
 	articleRepo := repository.NewArticleRepository(db)
+	collaboratorRepo := repository.NewCollaboratorRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	draftRepo := repository.NewDraftRepository(db)
+	editLockRepo := repository.NewEditLockRepository(db)
+	previewRepo := repository.NewPreviewTokenRepository(db)
+	redirectRepo := repository.NewRedirectRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	oauthStateRepo := repository.NewOAuthStateRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	operationLogRepo := repository.NewOperationLogRepository(db)
+	searchLogRepo := repository.NewSearchLogRepository(db)
+	contentStatsRepo := repository.NewContentStatsRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
 	jwtService := service.NewJWTService(cfg)
-	rbacService := service.NewRBACService()
-	userSvc := service.NewUserService(userRepo, jwtService)
-	articleSvc := service.NewArticleService(articleRepo, userRepo, rbacService)
+	rbacService := service.NewRBACService(roleRepo)
+	if err := rbacService.SeedBuiltinRoles(); err != nil {
+		log.Fatal("内置角色初始化失败:", err)
+	}
+	cacheSvc := cache.NewMemoryCacheService()
+	// 本地搜索索引只在当前进程内更新，多个实例各自维护会相互不一致，只允许一个实例使用
+	var searchLock *database.InstanceLock
+	if cfg.Article.Search.Backend == service.SearchBackendEmbedded {
+		searchLock, err = database.AcquireInstanceLock(db, "myblog:search:"+cfg.Database.DBName)
+		if err != nil {
+			log.Fatal("embedded 搜索后端只支持单实例部署，请停止其他实例或改用 mysql 后端:", err)
+		}
+	}
+	searchBackend, err := service.NewSearchBackend(cfg, articleRepo)
+	if err != nil {
+		log.Fatal("搜索后端初始化失败:", err)
+	}
+	userSvc := service.NewUserService(userRepo, jwtService, rbacService)
+	articleSvc := service.NewArticleService(articleRepo, collaboratorRepo, revisionRepo, draftRepo, editLockRepo, userRepo, contentStatsRepo, rbacService, searchBackend, cacheSvc, cfg)
+	oauthSvc := service.NewOAuthService(cfg, userRepo, identityRepo, oauthStateRepo, jwtService)
+	tokenSvc := service.NewPersonalTokenService(tokenRepo, userRepo, rbacService)
+	auditSvc := service.NewAuditService(operationLogRepo)
+	previewSvc := service.NewPreviewService(previewRepo, articleRepo, articleSvc, auditSvc, cfg)
+	redirectSvc := service.NewRedirectService(redirectRepo)
+	searchSvc := service.NewSearchService(searchLogRepo, articleRepo, cfg)
+	statsSvc := service.NewStatsService(statsRepo, cacheSvc, cfg)
 	userHandler := handler.NewUserHandler(userSvc)
-	articleHandler := handler.NewArticleHandler(articleSvc)
+	articleHandler := handler.NewArticleHandler(articleSvc, previewSvc, searchSvc)
+	oauthHandler := handler.NewOAuthHandler(oauthSvc)
+	tokenHandler := handler.NewPersonalTokenHandler(tokenSvc)
+	roleHandler := handler.NewRoleHandler(rbacService)
+	auditHandler := handler.NewAuditHandler(auditSvc)
+	redirectHandler := handler.NewRedirectHandler(redirectSvc)
+	searchHandler := handler.NewSearchHandler(searchSvc)
+	statsHandler := handler.NewStatsHandler(statsSvc)
+
+	// 认证中间件接受个人访问令牌
+	middleware.SetPersonalTokenService(tokenSvc)
+
+	// 后台重新渲染渲染版本落后的文章（回填HTML、目录、字数和阅读时间）
+	go func() {
+		result, err := articleSvc.RerenderArticles(false)
+		if err != nil {
+			log.Printf("文章重新渲染失败: %v", err)
+			return
+		}
+		if result.Rendered > 0 || result.Failed > 0 {
+			log.Printf("文章重新渲染完成: 渲染 %d 篇，失败 %d 篇", result.Rendered, result.Failed)
+		}
+	}()
+
+	// 本地搜索索引为空时（首次启用或索引格式升级）在后台重建
+	if searchBackend.NeedsRebuild() {
+		go func() {
+			result, err := articleSvc.ReindexArticles()
+			if err != nil {
+				log.Printf("搜索索引重建失败: %v", err)
+				return
+			}
+			log.Printf("搜索索引重建完成: 索引 %d 篇，耗时 %dms", result.Indexed, result.DurationMs)
+		}()
+	}
+
+	// 定时发布与到期下线
+	jobs := scheduler.New()
+	if cfg.Article.Schedule.Enabled {
+		interval := time.Duration(cfg.Article.Schedule.IntervalSeconds) * time.Second
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		jobs.Every("publish_scheduled_articles", interval, func(ctx context.Context) error {
+			result, err := articleSvc.PublishDueArticles()
+			if err != nil {
+				return err
+			}
+			if result.Published > 0 || result.Failed > 0 {
+				log.Printf("定时发布: 发布 %d 篇，失败 %d 篇", result.Published, result.Failed)
+			}
+			return nil
+		})
+		jobs.Every("archive_expired_articles", interval, func(ctx context.Context) error {
+			archived, err := articleSvc.ArchiveExpiredArticles()
+			if err != nil {
+				return err
+			}
+			if archived > 0 {
+				log.Printf("到期下线: 归档 %d 篇", archived)
+			}
+			return nil
+		})
+	}
+	// 清理进程内缓存中的过期项
+	jobs.Every("cleanup_expired_cache", time.Hour, func(ctx context.Context) error {
+		return cacheSvc.CleanupExpired()
+	})
+
+	// 定时重新计算相关文章
+	relatedInterval := time.Duration(cfg.Article.Related.IntervalMinutes) * time.Minute
+	if relatedInterval <= 0 {
+		relatedInterval = time.Hour
+	}
+	jobs.Every("rebuild_related_articles", relatedInterval, func(ctx context.Context) error {
+		result, err := articleSvc.RebuildRelated()
+		if err != nil {
+			return err
+		}
+		log.Printf("相关文章计算完成: 文章 %d 篇，耗时 %dms", result.Articles, result.DurationMs)
+		return nil
+	})
+
+	// 定时将浏览和点赞汇总为日统计，供趋势文章使用
+	rollupInterval := time.Duration(cfg.Article.Trending.RollupIntervalMinutes) * time.Minute
+	if rollupInterval <= 0 {
+		rollupInterval = 15 * time.Minute
+	}
+	jobs.Every("rollup_content_stats", rollupInterval, func(ctx context.Context) error {
+		_, err := articleSvc.RollupEngagement()
+		return err
+	})
+
+	// 清理超过保留天数的搜索记录
+	if cfg.Article.Search.LogRetentionDays > 0 {
+		jobs.Every("cleanup_search_logs", 24*time.Hour, func(ctx context.Context) error {
+			deleted, err := searchSvc.CleanupLogs()
+			if err != nil {
+				return err
+			}
+			if deleted > 0 {
+				log.Printf("搜索记录清理: 删除 %d 条", deleted)
+			}
+			return nil
+		})
+	}
+	jobs.Start()
 
 	// 创建路由管理器
 	routerManager := router.NewRouter()
 
 	// 设置依赖
 	deps := &router.Dependencies{
-		UserHandler:    userHandler,
-		ArticleHandler: articleHandler,
-		JWTService:     jwtService,
-		UserRepository: userRepo,
-		RBACService:    rbacService,
+		UserHandler:          userHandler,
+		ArticleHandler:       articleHandler,
+		OAuthHandler:         oauthHandler,
+		PersonalTokenHandler: tokenHandler,
+		RoleHandler:          roleHandler,
+		AuditHandler:         auditHandler,
+		RedirectHandler:      redirectHandler,
+		SearchHandler:        searchHandler,
+		StatsHandler:         statsHandler,
+		JWTService:           jwtService,
+		UserRepository:       userRepo,
+		RBACService:          rbacService,
+		AuditService:         auditSvc,
 	}
 
 	// 注册路由
@@ -95,6 +252,19 @@ func main() {
 
 	log.Println("正在关闭服务器...")
 
+	// 停止定时任务
+	jobs.Stop()
+
+	// 关闭搜索后端（写入索引快照）
+	if err := searchBackend.Close(); err != nil {
+		log.Printf("关闭搜索后端失败: %v", err)
+	}
+	if searchLock != nil {
+		if err := searchLock.Release(); err != nil {
+			log.Printf("%v", err)
+		}
+	}
+
 	// 关闭数据库连接
 	if err := database.Close(); err != nil {
 		log.Printf("关闭数据库连接失败: %v", err)
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryItem 内存缓存项，值以JSON保存，读取时反序列化为副本，调用方修改结果不会影响缓存
+type memoryItem struct {
+	value     []byte
+	expiresAt time.Time // 零值表示永不过期
+}
+
+// expired 缓存项是否已过期
+func (i *memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// memoryCacheService 进程内缓存服务实现，单实例部署或无需共享的缓存使用
+type memoryCacheService struct {
+	mu    sync.RWMutex
+	items map[string]*memoryItem
+}
+
+// NewMemoryCacheService 创建进程内缓存服务实例
+func NewMemoryCacheService() CacheService {
+	return &memoryCacheService{
+		items: make(map[string]*memoryItem),
+	}
+}
+
+// Set 设置缓存项
+func (c *memoryCacheService) Set(key string, value interface{}, expiration time.Duration) error {
+	item, err := newMemoryItem(value, expiration)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.items[key] = item
+	c.mu.Unlock()
+	return nil
+}
+
+// Get 获取缓存项
+func (c *memoryCacheService) Get(key string, dest interface{}) error {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+
+	if !ok || item.expired(time.Now()) {
+		return fmt.Errorf("缓存键不存在或已过期: %s", key)
+	}
+	return json.Unmarshal(item.value, dest)
+}
+
+// Delete 删除缓存项
+func (c *memoryCacheService) Delete(key string) error {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+	return nil
+}
+
+// Exists 检查缓存项是否存在
+func (c *memoryCacheService) Exists(key string) (bool, error) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+
+	return ok && !item.expired(time.Now()), nil
+}
+
+// Clear 清空所有缓存
+func (c *memoryCacheService) Clear() error {
+	c.mu.Lock()
+	c.items = make(map[string]*memoryItem)
+	c.mu.Unlock()
+	return nil
+}
+
+// SetMany 批量设置缓存项
+func (c *memoryCacheService) SetMany(items map[string]interface{}, expiration time.Duration) error {
+	encoded := make(map[string]*memoryItem, len(items))
+	for key, value := range items {
+		item, err := newMemoryItem(value, expiration)
+		if err != nil {
+			return err
+		}
+		encoded[key] = item
+	}
+
+	c.mu.Lock()
+	for key, item := range encoded {
+		c.items[key] = item
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// GetMany 批量获取缓存项，不存在或已过期的键不出现在结果中
+func (c *memoryCacheService) GetMany(keys []string) (map[string]interface{}, error) {
+	now := time.Now()
+	result := make(map[string]interface{})
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, key := range keys {
+		item, ok := c.items[key]
+		if !ok || item.expired(now) {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(item.value, &value); err != nil {
+			continue
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// DeleteMany 批量删除缓存项
+func (c *memoryCacheService) DeleteMany(keys []string) error {
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// CleanupExpired 清理过期的缓存项，过期项读取时已视为不存在，定期清理用于释放内存
+func (c *memoryCacheService) CleanupExpired() error {
+	now := time.Now()
+
+	c.mu.Lock()
+	for key, item := range c.items {
+		if item.expired(now) {
+			delete(c.items, key)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// newMemoryItem 序列化缓存值，expiration 不大于 0 时永不过期（与 MongoDB 实现一致）
+func newMemoryItem(value interface{}, expiration time.Duration) (*memoryItem, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("序列化缓存值失败: %w", err)
+	}
+	item := &memoryItem{value: data}
+	if expiration > 0 {
+		item.expiresAt = time.Now().Add(expiration)
+	}
+	return item, nil
+}
@@ -261,14 +261,25 @@ const (
 	CacheKeyArticleList = "article:list:%s" // 文章列表
 	CacheKeyHotArticles = "article:hot"     // 热门文章
 
+	// 文章归档统计缓存，键中带版本号，发布状态变化时更新版本号使旧缓存整体失效
+	CacheKeyArchiveVersion  = "article:archive:version"        // 归档缓存版本号
+	CacheKeyArchiveMonths   = "article:archive:%d:months"      // 按年月的文章数
+	CacheKeyArchiveMonthIDs = "article:archive:%d:month:%s"    // 某月的文章ID（YYYY-MM）
+	CacheKeyArchiveCalendar = "article:archive:%d:calendar:%d" // 某年每天的文章数
+
+	// 相关文章缓存，由后台任务定时重建
+	CacheKeyRelatedArticles = "article:related:%d" // 某篇文章的相关文章ID（按相关度降序）
+
 	// 系统配置缓存
 	CacheKeySettings = "settings"      // 系统设置
 	CacheKeyCategory = "category:tree" // 分类树
 	CacheKeyTags     = "tags:all"      // 所有标签
 
 	// 统计数据缓存
-	CacheKeyStats     = "stats:site" // 站点统计
-	CacheKeyViewCount = "view:%d"    // 浏览量计数
+	CacheKeyStats           = "stats:site"         // 站点统计
+	CacheKeyStatsTimeSeries = "stats:series:%s:%s" // 每日趋势（起止日期）
+	CacheKeyStatsTop        = "stats:top:%s:%s:%d" // 排行（起止日期、数量）
+	CacheKeyViewCount       = "view:%d"            // 浏览量计数
 )
 
 // GetCacheKey 生成缓存键
@@ -16,6 +16,9 @@ type Config struct {
 	API      APIConfig      `mapstructure:"api"`
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Security SecurityConfig `mapstructure:"security"`
+	OAuth    OAuthConfig    `mapstructure:"oauth"`
+	Article  ArticleConfig  `mapstructure:"article"`
+	Stats    StatsConfig    `mapstructure:"stats"`
 }
 
 // ServerConfig 服务器配置
@@ -104,6 +107,122 @@ type AdminSecurityConfig struct {
 	IPWhitelist     []string `mapstructure:"ip_whitelist"`
 }
 
+// OAuthConfig 第三方登录配置
+type OAuthConfig struct {
+	StateExpire int                            `mapstructure:"state_expire"` // 授权状态有效期（分钟）
+	Providers   map[string]OAuthProviderConfig `mapstructure:"providers"`    // 身份提供方，键为提供方名称
+}
+
+// OAuthProviderConfig 身份提供方配置
+type OAuthProviderConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	DisplayName  string   `mapstructure:"display_name"`
+	Type         string   `mapstructure:"type"`   // oidc, github
+	Issuer       string   `mapstructure:"issuer"` // OIDC颁发者地址，未配置端点时通过发现文档获取
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	AuthURL      string   `mapstructure:"auth_url"`
+	TokenURL     string   `mapstructure:"token_url"`
+	UserInfoURL  string   `mapstructure:"userinfo_url"`
+	EmailsURL    string   `mapstructure:"emails_url"` // GitHub邮箱列表接口
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	AutoRegister bool     `mapstructure:"auto_register"` // 未绑定时是否自动创建账号
+}
+
+// StatsConfig 后台统计面板配置
+type StatsConfig struct {
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"` // 统计结果缓存有效期（秒）
+	MaxRangeDays    int `mapstructure:"max_range_days"`    // 可查询的最大日期范围（天）
+}
+
+// ArticleConfig 文章配置
+type ArticleConfig struct {
+	Revision RevisionConfig `mapstructure:"revision"`
+	Schedule ScheduleConfig `mapstructure:"schedule"`
+	EditLock EditLockConfig `mapstructure:"edit_lock"`
+	Preview  PreviewConfig  `mapstructure:"preview"`
+	Unlock   UnlockConfig   `mapstructure:"unlock"`
+	Slug     SlugConfig     `mapstructure:"slug"`
+	Search   SearchConfig   `mapstructure:"search"`
+	Archive  ArchiveConfig  `mapstructure:"archive"`
+	Related  RelatedConfig  `mapstructure:"related"`
+	Trending TrendingConfig `mapstructure:"trending"`
+}
+
+// SearchConfig 文章全文搜索配置
+type SearchConfig struct {
+	Backend           string  `mapstructure:"backend"`             // 搜索后端：mysql（FULLTEXT 索引）或 embedded（本地倒排索引）
+	IndexDir          string  `mapstructure:"index_dir"`           // embedded 后端的索引目录
+	TitleWeight       float64 `mapstructure:"title_weight"`        // 标题命中的相关度权重
+	SummaryWeight     float64 `mapstructure:"summary_weight"`      // 摘要命中的相关度权重
+	ContentWeight     float64 `mapstructure:"content_weight"`      // 正文命中的相关度权重
+	SnippetLength     int     `mapstructure:"snippet_length"`      // 高亮片段长度（字符数）
+	HotWindowHours    int     `mapstructure:"hot_window_hours"`    // 热门搜索默认统计最近多少小时
+	SuggestWindowDays int     `mapstructure:"suggest_window_days"` // 搜索建议使用最近多少天的搜索记录
+	MinSearchers      int     `mapstructure:"min_searchers"`       // 搜索建议和热门搜索只展示搜索人数不少于该值的关键词
+	LogRetentionDays  int     `mapstructure:"log_retention_days"`  // 搜索记录保留天数，0 表示不清理
+}
+
+// ArchiveConfig 文章归档统计配置
+type ArchiveConfig struct {
+	CacheTTLSeconds int `mapstructure:"cache_ttl_seconds"` // 归档统计缓存有效期（秒），本实例内发布状态变化时立即失效，也是多实例间的最大延迟
+}
+
+// RelatedConfig 相关文章计算配置
+type RelatedConfig struct {
+	IntervalMinutes int     `mapstructure:"interval_minutes"` // 后台重新计算相关文章的间隔（分钟）
+	MaxPerArticle   int     `mapstructure:"max_per_article"`  // 每篇文章缓存的相关文章数
+	TagWeight       float64 `mapstructure:"tag_weight"`       // 共同标签（按标签稀有度加权）的权重
+	CategoryWeight  float64 `mapstructure:"category_weight"`  // 共同分类的权重
+	TextWeight      float64 `mapstructure:"text_weight"`      // 标题和摘要文本相似度的权重
+}
+
+// TrendingConfig 趋势文章配置
+type TrendingConfig struct {
+	RollupIntervalMinutes int                `mapstructure:"rollup_interval_minutes"` // 将浏览和点赞汇总为日统计的间隔（分钟）
+	StatsRetentionDays    int                `mapstructure:"stats_retention_days"`    // 日统计保留天数，0 表示不清理
+	LikeWeight            float64            `mapstructure:"like_weight"`             // 一次点赞相当于多少次浏览
+	DefaultWindow         string             `mapstructure:"default_window"`          // 未指定时使用的统计窗口
+	Windows               map[string]float64 `mapstructure:"windows"`                 // 可用的统计窗口（如 24h、7d）及其热度半衰期（小时）
+}
+
+// SlugConfig 文章Slug生成配置
+type SlugConfig struct {
+	MaxLength int `mapstructure:"max_length"` // 自动生成的Slug最大长度（字符数）
+}
+
+// RevisionConfig 文章修订历史配置
+type RevisionConfig struct {
+	KeepAllDays int `mapstructure:"keep_all_days"` // 该天数内保留全部修订，更早的每天只保留最后一个；0 表示不清理
+}
+
+// EditLockConfig 文章编辑锁配置
+type EditLockConfig struct {
+	TTLSeconds int `mapstructure:"ttl_seconds"` // 锁有效期（秒），客户端需在过期前发送心跳续期
+}
+
+// PreviewConfig 文章预览链接配置
+type PreviewConfig struct {
+	Secret        string `mapstructure:"secret"`          // 预览令牌签名密钥，为空时使用 JWT 访问令牌密钥
+	DefaultHours  int    `mapstructure:"default_hours"`   // 默认有效期（小时）
+	MaxHours      int    `mapstructure:"max_hours"`       // 最长有效期（小时）
+	MaxPerArticle int    `mapstructure:"max_per_article"` // 每篇文章最多同时有效的预览链接数
+}
+
+// UnlockConfig 密码保护文章解锁配置
+type UnlockConfig struct {
+	Secret     string `mapstructure:"secret"`      // 解锁令牌签名密钥，为空时使用 JWT 访问令牌密钥
+	TTLMinutes int    `mapstructure:"ttl_minutes"` // 解锁有效期（分钟）
+}
+
+// ScheduleConfig 定时发布配置
+type ScheduleConfig struct {
+	Enabled         bool `mapstructure:"enabled"`          // 是否在本实例运行定时发布任务
+	IntervalSeconds int  `mapstructure:"interval_seconds"` // 扫描间隔（秒）
+	BatchSize       int  `mapstructure:"batch_size"`       // 每次扫描处理的最大文章数
+}
+
 var (
 	config *Config
 	once   sync.Once
@@ -219,6 +338,42 @@ func setDefaults() {
 	viper.SetDefault("security.admin_security.enabled", true)
 	viper.SetDefault("security.admin_security.max_requests", 30)
 	viper.SetDefault("security.admin_security.user_max_requests", 50)
+
+	viper.SetDefault("oauth.state_expire", 10)
+
+	viper.SetDefault("article.revision.keep_all_days", 30)
+	viper.SetDefault("article.schedule.enabled", true)
+	viper.SetDefault("article.schedule.interval_seconds", 30)
+	viper.SetDefault("article.schedule.batch_size", 100)
+	viper.SetDefault("article.edit_lock.ttl_seconds", 90)
+	viper.SetDefault("article.preview.default_hours", 72)
+	viper.SetDefault("article.preview.max_hours", 720)
+	viper.SetDefault("article.preview.max_per_article", 20)
+	viper.SetDefault("article.unlock.ttl_minutes", 120)
+	viper.SetDefault("article.slug.max_length", 80)
+	viper.SetDefault("article.search.backend", "mysql")
+	viper.SetDefault("article.search.index_dir", "data/search")
+	viper.SetDefault("article.search.title_weight", 5)
+	viper.SetDefault("article.search.summary_weight", 3)
+	viper.SetDefault("article.search.content_weight", 1)
+	viper.SetDefault("article.search.snippet_length", 120)
+	viper.SetDefault("article.search.hot_window_hours", 24)
+	viper.SetDefault("article.search.suggest_window_days", 30)
+	viper.SetDefault("article.search.min_searchers", 3)
+	viper.SetDefault("article.search.log_retention_days", 180)
+	viper.SetDefault("article.archive.cache_ttl_seconds", 60)
+	viper.SetDefault("article.related.interval_minutes", 60)
+	viper.SetDefault("article.related.max_per_article", 20)
+	viper.SetDefault("article.related.tag_weight", 0.5)
+	viper.SetDefault("article.related.category_weight", 0.2)
+	viper.SetDefault("article.related.text_weight", 0.3)
+	viper.SetDefault("article.trending.rollup_interval_minutes", 15)
+	viper.SetDefault("article.trending.stats_retention_days", 400)
+	viper.SetDefault("article.trending.like_weight", 5)
+	viper.SetDefault("article.trending.default_window", "7d")
+	viper.SetDefault("article.trending.windows", map[string]float64{"24h": 6, "7d": 48, "30d": 240})
+	viper.SetDefault("stats.cache_ttl_seconds", 300)
+	viper.SetDefault("stats.max_range_days", 366)
 }
 
 // validateConfig 验证配置的有效性
@@ -255,5 +410,20 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("JWT刷新令牌过期时间必须大于0")
 	}
 
+	for name, provider := range cfg.OAuth.Providers {
+		if !provider.Enabled {
+			continue
+		}
+		if provider.ClientID == "" {
+			return fmt.Errorf("第三方登录 %s 缺少 client_id", name)
+		}
+		if provider.RedirectURL == "" {
+			return fmt.Errorf("第三方登录 %s 缺少 redirect_url", name)
+		}
+		if provider.Type != "github" && provider.Issuer == "" && (provider.AuthURL == "" || provider.TokenURL == "" || provider.UserInfoURL == "") {
+			return fmt.Errorf("第三方登录 %s 需要配置 issuer 或完整的端点地址", name)
+		}
+	}
+
 	return nil
 }
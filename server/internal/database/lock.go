@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// lockKeepAliveInterval 持锁连接的保活间隔，避免空闲连接被 wait_timeout 断开后锁被释放
+const lockKeepAliveInterval = 5 * time.Minute
+
+// InstanceLock 基于 MySQL 命名锁（GET_LOCK）的实例锁
+// 锁与数据库会话绑定，持有期间独占一个连接，进程退出或连接断开时由 MySQL 自动释放
+type InstanceLock struct {
+	name string
+	conn *sql.Conn
+	stop chan struct{}
+}
+
+// AcquireInstanceLock 立即尝试获取实例锁，已被其他实例持有时返回错误
+func AcquireInstanceLock(db *gorm.DB, name string) (*InstanceLock, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+	conn, err := sqlDB.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, 0)", name).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("获取实例锁失败: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, errors.New("实例锁已被其他实例持有")
+	}
+
+	lock := &InstanceLock{name: name, conn: conn, stop: make(chan struct{})}
+	go lock.keepAlive()
+	return lock, nil
+}
+
+// Release 释放实例锁并归还连接
+func (l *InstanceLock) Release() error {
+	close(l.stop)
+	defer l.conn.Close()
+	if _, err := l.conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", l.name); err != nil {
+		return fmt.Errorf("释放实例锁失败: %w", err)
+	}
+	return nil
+}
+
+// keepAlive 定期检查持锁连接
+func (l *InstanceLock) keepAlive() {
+	ticker := time.NewTicker(lockKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.conn.PingContext(context.Background()); err != nil {
+				log.Printf("实例锁 %s 的数据库连接已断开，锁可能已被释放: %v", l.name, err)
+			}
+		}
+	}
+}
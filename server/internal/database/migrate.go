@@ -2,6 +2,7 @@ package database
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -110,6 +111,11 @@ func RunMigrations(cfg *config.Config) error {
 	// 运行迁移
 	log.Println("开始运行数据库迁移...")
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		// 迁移目录中还没有迁移文件
+		if errors.Is(err, os.ErrNotExist) {
+			log.Println("没有需要运行的迁移文件")
+			return nil
+		}
 		return fmt.Errorf("运行迁移失败: %w", err)
 	}
 
@@ -289,9 +295,10 @@ func ForceMigrationVersion(cfg *config.Config, version int) error {
 	return nil
 }
 
-// AutoMigrateWithFix 保留原有的GORM自动迁移功能作为备用方案
+// AutoMigrateWithFix 使用GORM自动迁移创建和补齐表结构
+// 所有运行模式都会执行；migrations 目录只保存模型标签无法表达的变更，在自动迁移之后运行
 func AutoMigrateWithFix(db *gorm.DB) error {
-	log.Println("开始GORM自动迁移（仅用于开发环境）...")
+	log.Println("开始GORM自动迁移...")
 
 	// 使用新的模型结构进行迁移
 	if err := model.AutoMigrate(db); err != nil {
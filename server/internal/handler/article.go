@@ -1,8 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
 	"MyBlog/internal/service"
 	"MyBlog/pkg/response"
 
@@ -14,6 +21,7 @@ type ArticleHandlerInterface interface {
 	CreateArticle(c *gin.Context)
 	GetArticle(c *gin.Context)
 	GetArticleBySlug(c *gin.Context)
+	UnlockArticle(c *gin.Context)
 	UpdateArticle(c *gin.Context)
 	DeleteArticle(c *gin.Context)
 	GetArticleList(c *gin.Context)
@@ -24,6 +32,10 @@ type ArticleHandlerInterface interface {
 	GetPopularArticles(c *gin.Context)
 	GetRecentArticles(c *gin.Context)
 	GetRelatedArticles(c *gin.Context)
+	GetTrendingArticles(c *gin.Context)
+	GetArchives(c *gin.Context)
+	GetArchiveMonth(c *gin.Context)
+	GetArchiveCalendar(c *gin.Context)
 	ViewArticle(c *gin.Context)
 	LikeArticle(c *gin.Context)
 	UnlikeArticle(c *gin.Context)
@@ -33,20 +45,59 @@ type ArticleHandlerInterface interface {
 	UnpublishArticle(c *gin.Context)
 	ArchiveArticle(c *gin.Context)
 	SetArticlePrivate(c *gin.Context)
+	InviteCollaborator(c *gin.Context)
+	RemoveCollaborator(c *gin.Context)
+	GetCollaborators(c *gin.Context)
+	GetSharedArticles(c *gin.Context)
+	ListRevisions(c *gin.Context)
+	DiffRevisions(c *gin.Context)
+	RestoreRevision(c *gin.Context)
+	SaveDraft(c *gin.Context)
+	GetDraft(c *gin.Context)
+	DiscardDraft(c *gin.Context)
+	AcquireEditLock(c *gin.Context)
+	HeartbeatEditLock(c *gin.Context)
+	ReleaseEditLock(c *gin.Context)
+	GetEditLock(c *gin.Context)
+	CreatePreview(c *gin.Context)
+	ListPreviews(c *gin.Context)
+	RevokePreview(c *gin.Context)
+	RerenderArticles(c *gin.Context)
+	ReindexArticles(c *gin.Context)
 }
 
 // ArticleHandler 文章处理器实现
 type ArticleHandler struct {
 	articleService service.ArticleServiceInterface
+	previewService service.PreviewService
+	searchService  service.SearchService
 }
 
 // NewArticleHandler 创建文章处理器实例
-func NewArticleHandler(articleService service.ArticleServiceInterface) ArticleHandlerInterface {
+func NewArticleHandler(articleService service.ArticleServiceInterface, previewService service.PreviewService, searchService service.SearchService) ArticleHandlerInterface {
 	return &ArticleHandler{
 		articleService: articleService,
+		previewService: previewService,
+		searchService:  searchService,
 	}
 }
 
+// articles 获取本次请求使用的文章服务，个人访问令牌请求按令牌授权范围校验文章权限
+func (h *ArticleHandler) articles(c *gin.Context) service.ArticleServiceInterface {
+	if scopes, ok := middleware.GetTokenScopes(c); ok {
+		return h.articleService.WithTokenScopes(scopes)
+	}
+	return h.articleService
+}
+
+// previews 获取本次请求使用的预览链接服务，个人访问令牌请求按令牌授权范围校验文章权限
+func (h *ArticleHandler) previews(c *gin.Context) service.PreviewService {
+	if scopes, ok := middleware.GetTokenScopes(c); ok {
+		return h.previewService.WithTokenScopes(scopes)
+	}
+	return h.previewService
+}
+
 // CreateArticle 创建文章
 func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	// 获取当前用户ID
@@ -64,7 +115,7 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	}
 
 	// 创建文章
-	article, err := h.articleService.CreateArticle(&req, userID.(uint))
+	article, err := h.articles(c).CreateArticle(&req, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -77,7 +128,9 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 func (h *ArticleHandler) GetArticle(c *gin.Context) {
 	// 绑定请求参数
 	type GetArticleRequest struct {
-		ID uint `json:"id" binding:"required"`
+		ID           uint   `json:"id" binding:"required"`
+		PreviewToken string `json:"previewToken"` // 预览令牌，也可通过 X-Preview-Token 请求头传递
+		UnlockToken  string `json:"unlockToken"`  // 密码保护文章的解锁令牌，也可通过 X-Unlock-Token 请求头或 Cookie 传递
 	}
 
 	var req GetArticleRequest
@@ -93,13 +146,27 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 		userID = &uidUint
 	}
 
+	// 通过预览链接访问未发布的文章
+	if token := previewToken(c, req.PreviewToken); token != "" {
+		article, err := h.previews(c).GetArticleByPreview(token, previewAccess(c, userID))
+		if err != nil || article.ID != req.ID {
+			response.Error(c, http.StatusNotFound, "预览链接无效或已过期")
+			return
+		}
+		setPreviewHeaders(c)
+		response.Success(c, article)
+		return
+	}
+
 	// 获取文章
-	article, err := h.articleService.GetArticle(req.ID, userID)
+	article, err := h.articles(c).GetArticle(req.ID, userID, unlockToken(c, req.UnlockToken, req.ID))
 	if err != nil {
 		response.Error(c, http.StatusNotFound, err.Error())
 		return
 	}
 
+	setVisibilityHeaders(c, article)
+	setArticleETag(c, article.Version)
 	response.Success(c, article)
 }
 
@@ -107,7 +174,9 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
 	// 绑定请求参数
 	type GetArticleBySlugRequest struct {
-		Slug string `json:"slug" binding:"required"`
+		Slug         string `json:"slug" binding:"required"`
+		PreviewToken string `json:"previewToken"` // 预览令牌，也可通过 X-Preview-Token 请求头传递
+		UnlockToken  string `json:"unlockToken"`  // 密码保护文章的解锁令牌，也可通过 X-Unlock-Token 请求头或 Cookie 传递
 	}
 
 	var req GetArticleBySlugRequest
@@ -123,16 +192,69 @@ func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
 		userID = &uidUint
 	}
 
+	// 通过预览链接访问未发布的文章
+	if token := previewToken(c, req.PreviewToken); token != "" {
+		article, err := h.previews(c).GetArticleByPreview(token, previewAccess(c, userID))
+		if err != nil || article.Slug != req.Slug {
+			response.Error(c, http.StatusNotFound, "预览链接无效或已过期")
+			return
+		}
+		setPreviewHeaders(c)
+		response.Success(c, article)
+		return
+	}
+
 	// 获取文章
-	article, err := h.articleService.GetArticleBySlug(req.Slug, userID)
+	article, err := h.articles(c).GetArticleBySlug(req.Slug, userID, req.UnlockToken)
 	if err != nil {
 		response.Error(c, http.StatusNotFound, err.Error())
 		return
 	}
 
+	// 请求体中没有解锁令牌时，按文章ID读取请求头或 Cookie 中的令牌
+	if article.Locked && article.Visibility == model.VisibilityPassword {
+		if token := unlockToken(c, req.UnlockToken, article.ID); token != req.UnlockToken {
+			if unlocked, err := h.articles(c).GetArticleBySlug(req.Slug, userID, token); err == nil {
+				article = unlocked
+			}
+		}
+	}
+
+	setVisibilityHeaders(c, article)
+	setArticleETag(c, article.Version)
 	response.Success(c, article)
 }
 
+// UnlockArticle 输入密码解锁密码保护文章
+func (h *ArticleHandler) UnlockArticle(c *gin.Context) {
+	// 绑定请求参数
+	type UnlockArticleRequest struct {
+		ID       uint   `json:"id" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	var req UnlockArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 校验密码
+	result, err := h.articles(c).UnlockArticle(req.ID, req.Password)
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	// 同时写入 Cookie，浏览器后续获取详情时自动携带
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(unlockCookieName(result.ArticleID), result.UnlockToken, maxAge, "/", "", c.Request.TLS != nil, true)
+	c.Header("Cache-Control", "private, no-store")
+
+	response.Success(c, result)
+}
+
 // UpdateArticle 更新文章
 func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 	// 获取当前用户ID
@@ -154,13 +276,33 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 		return
 	}
 
+	// 版本号优先取 If-Match 请求头
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseArticleETag(ifMatch)
+		if !ok {
+			response.Error(c, http.StatusBadRequest, "If-Match 格式错误")
+			return
+		}
+		req.Version = version
+	}
+
+	// 记录变更前快照用于审计
+	operatorID := userID.(uint)
+	if before, err := h.articles(c).GetArticle(req.ID, &operatorID, ""); err == nil {
+		middleware.SetAuditBefore(c, before)
+	}
+
 	// 更新文章
-	article, err := h.articleService.UpdateArticle(req.ID, &req.UpdateArticleRequest, userID.(uint))
+	article, err := h.articles(c).UpdateArticle(req.ID, &req.UpdateArticleRequest, userID.(uint))
 	if err != nil {
+		if respondConflict(c, err) {
+			return
+		}
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	setArticleETag(c, article.Version)
 	response.Success(c, article)
 }
 
@@ -184,8 +326,14 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 		return
 	}
 
+	// 记录删除前快照用于审计
+	operatorID := userID.(uint)
+	if before, err := h.articles(c).GetArticle(req.ID, &operatorID, ""); err == nil {
+		middleware.SetAuditBefore(c, before)
+	}
+
 	// 删除文章
-	err := h.articleService.DeleteArticle(req.ID, userID.(uint))
+	err := h.articles(c).DeleteArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -219,9 +367,9 @@ func (h *ArticleHandler) GetArticleList(c *gin.Context) {
 	}
 
 	// 获取文章列表
-	result, err := h.articleService.GetArticleList(&req, userID)
+	result, err := h.articles(c).GetArticleList(&req, userID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -250,10 +398,17 @@ func (h *ArticleHandler) GetArticlesByAuthor(c *gin.Context) {
 		req.PageSize = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取文章列表
-	result, err := h.articleService.GetArticlesByAuthor(req.AuthorID, &req.GetArticleListRequest)
+	result, err := h.articles(c).GetArticlesByAuthor(req.AuthorID, &req.GetArticleListRequest, userID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -282,10 +437,17 @@ func (h *ArticleHandler) GetArticlesByCategory(c *gin.Context) {
 		req.PageSize = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取文章列表
-	result, err := h.articleService.GetArticlesByCategory(req.CategoryID, &req.GetArticleListRequest)
+	result, err := h.articles(c).GetArticlesByCategory(req.CategoryID, &req.GetArticleListRequest, userID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -314,10 +476,17 @@ func (h *ArticleHandler) GetArticlesByTag(c *gin.Context) {
 		req.PageSize = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取文章列表
-	result, err := h.articleService.GetArticlesByTag(req.TagID, &req.GetArticleListRequest)
+	result, err := h.articles(c).GetArticlesByTag(req.TagID, &req.GetArticleListRequest, userID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 
@@ -346,13 +515,29 @@ func (h *ArticleHandler) SearchArticles(c *gin.Context) {
 		req.PageSize = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 搜索文章
-	result, err := h.articleService.SearchArticles(req.Keyword, &req.GetArticleListRequest)
+	result, err := h.articles(c).SearchArticles(req.Keyword, &req.GetArticleListRequest, userID)
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 
+	// 记录搜索，用于搜索建议、热门搜索和无结果报告
+	h.searchService.LogSearch(&service.SearchLogEntry{
+		Keyword:   req.Keyword,
+		Results:   result.Total,
+		UserID:    userID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+
 	response.Success(c, result)
 }
 
@@ -373,8 +558,15 @@ func (h *ArticleHandler) GetPopularArticles(c *gin.Context) {
 		req.Limit = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取热门文章
-	articles, err := h.articleService.GetPopularArticles(req.Limit)
+	articles, err := h.articles(c).GetPopularArticles(req.Limit, userID)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -400,8 +592,15 @@ func (h *ArticleHandler) GetRecentArticles(c *gin.Context) {
 		req.Limit = 10
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取最新文章
-	articles, err := h.articleService.GetRecentArticles(req.Limit)
+	articles, err := h.articles(c).GetRecentArticles(req.Limit, userID)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -415,7 +614,7 @@ func (h *ArticleHandler) GetRelatedArticles(c *gin.Context) {
 	// 绑定请求参数
 	type RelatedRequest struct {
 		ID    uint `json:"id" binding:"required"`
-		Limit int  `json:"limit"`
+		Limit int  `json:"limit" binding:"omitempty,min=1,max=20"`
 	}
 
 	var req RelatedRequest
@@ -429,8 +628,15 @@ func (h *ArticleHandler) GetRelatedArticles(c *gin.Context) {
 		req.Limit = 5
 	}
 
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
 	// 获取相关文章
-	articles, err := h.articleService.GetRelatedArticles(req.ID, req.Limit)
+	articles, err := h.articles(c).GetRelatedArticles(req.ID, req.Limit, userID)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -439,11 +645,88 @@ func (h *ArticleHandler) GetRelatedArticles(c *gin.Context) {
 	response.Success(c, gin.H{"articles": articles})
 }
 
+// GetArchives 获取按年月归档的文章数
+func (h *ArticleHandler) GetArchives(c *gin.Context) {
+	summary, err := h.articles(c).GetArchives()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, summary)
+}
+
+// GetArchiveMonth 获取某月发布的文章列表
+func (h *ArticleHandler) GetArchiveMonth(c *gin.Context) {
+	var req service.ArchiveMonthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
+	result, err := h.articles(c).GetArchiveMonth(&req, userID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// GetArchiveCalendar 获取某年每天的发布数
+func (h *ArticleHandler) GetArchiveCalendar(c *gin.Context) {
+	var req service.ArchiveCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	calendar, err := h.articles(c).GetArchiveCalendar(&req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, calendar)
+}
+
+// GetTrendingArticles 获取趋势文章
+func (h *ArticleHandler) GetTrendingArticles(c *gin.Context) {
+	var req service.TrendingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
+	result, err := h.articles(c).GetTrendingArticles(&req, userID)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // ViewArticle 记录文章浏览
 func (h *ArticleHandler) ViewArticle(c *gin.Context) {
 	// 绑定请求参数
 	type ViewArticleRequest struct {
-		ID uint `json:"id" binding:"required"`
+		ID       uint   `json:"id" binding:"required"`
+		Referrer string `json:"referrer" binding:"max=500"` // 来源页面（前端的 document.referrer），用于来源站点统计
 	}
 
 	var req ViewArticleRequest
@@ -464,7 +747,7 @@ func (h *ArticleHandler) ViewArticle(c *gin.Context) {
 	ipAddress := c.ClientIP()
 
 	// 记录浏览
-	err := h.articleService.ViewArticle(req.ID, userID, visitorID, ipAddress)
+	err := h.articles(c).ViewArticle(req.ID, userID, visitorID, ipAddress, req.Referrer)
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -494,7 +777,7 @@ func (h *ArticleHandler) LikeArticle(c *gin.Context) {
 	}
 
 	// 点赞文章
-	err := h.articleService.LikeArticle(req.ID, userID.(uint))
+	err := h.articles(c).LikeArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -524,7 +807,7 @@ func (h *ArticleHandler) UnlikeArticle(c *gin.Context) {
 	}
 
 	// 取消点赞文章
-	err := h.articleService.UnlikeArticle(req.ID, userID.(uint))
+	err := h.articles(c).UnlikeArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -554,7 +837,7 @@ func (h *ArticleHandler) BookmarkArticle(c *gin.Context) {
 	}
 
 	// 收藏文章
-	err := h.articleService.BookmarkArticle(req.ID, userID.(uint))
+	err := h.articles(c).BookmarkArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -584,7 +867,7 @@ func (h *ArticleHandler) UnbookmarkArticle(c *gin.Context) {
 	}
 
 	// 取消收藏文章
-	err := h.articleService.UnbookmarkArticle(req.ID, userID.(uint))
+	err := h.articles(c).UnbookmarkArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -614,7 +897,7 @@ func (h *ArticleHandler) PublishArticle(c *gin.Context) {
 	}
 
 	// 发布文章
-	err := h.articleService.PublishArticle(req.ID, userID.(uint))
+	err := h.articles(c).PublishArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -644,7 +927,7 @@ func (h *ArticleHandler) UnpublishArticle(c *gin.Context) {
 	}
 
 	// 取消发布文章
-	err := h.articleService.UnpublishArticle(req.ID, userID.(uint))
+	err := h.articles(c).UnpublishArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -674,7 +957,7 @@ func (h *ArticleHandler) ArchiveArticle(c *gin.Context) {
 	}
 
 	// 归档文章
-	err := h.articleService.ArchiveArticle(req.ID, userID.(uint))
+	err := h.articles(c).ArchiveArticle(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -704,7 +987,7 @@ func (h *ArticleHandler) SetArticlePrivate(c *gin.Context) {
 	}
 
 	// 设置文章为私有
-	err := h.articleService.SetArticlePrivate(req.ID, userID.(uint))
+	err := h.articles(c).SetArticlePrivate(req.ID, userID.(uint))
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, err.Error())
 		return
@@ -712,3 +995,624 @@ func (h *ArticleHandler) SetArticlePrivate(c *gin.Context) {
 
 	response.Success(c, gin.H{"message": "文章设置为私有成功"})
 }
+
+// InviteCollaborator 邀请文章协作者
+func (h *ArticleHandler) InviteCollaborator(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type InviteCollaboratorRequestWithID struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+		service.InviteCollaboratorRequest
+	}
+
+	var req InviteCollaboratorRequestWithID
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 邀请协作者
+	collaborator, err := h.articles(c).InviteCollaborator(req.ArticleID, &req.InviteCollaboratorRequest, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, collaborator)
+}
+
+// RemoveCollaborator 移除文章协作者
+func (h *ArticleHandler) RemoveCollaborator(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type RemoveCollaboratorRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+		UserID    uint `json:"userId" binding:"required"`
+	}
+
+	var req RemoveCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 移除协作者
+	err := h.articles(c).RemoveCollaborator(req.ArticleID, req.UserID, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"message": "协作者已移除"})
+}
+
+// GetCollaborators 获取文章协作者列表
+func (h *ArticleHandler) GetCollaborators(c *gin.Context) {
+	// 绑定请求参数
+	type GetCollaboratorsRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+	}
+
+	var req GetCollaboratorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取当前用户ID（可选）
+	var userID *uint
+	if uid, exists := c.Get("userID"); exists {
+		uidUint := uid.(uint)
+		userID = &uidUint
+	}
+
+	// 获取协作者列表
+	collaborators, err := h.articles(c).GetCollaborators(req.ArticleID, userID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.Success(c, collaborators)
+}
+
+// GetSharedArticles 获取共享给当前用户协作的文章
+func (h *ArticleHandler) GetSharedArticles(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.GetArticleListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	// 获取共享文章列表
+	result, err := h.articles(c).GetSharedArticles(userID.(uint), &req)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RerenderArticles 使用当前渲染器重新渲染文章（管理员）
+func (h *ArticleHandler) RerenderArticles(c *gin.Context) {
+	// 绑定请求参数
+	type RerenderArticlesRequest struct {
+		Force bool `json:"force"` // 是否忽略渲染版本全部重新渲染
+	}
+
+	var req RerenderArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 重新渲染
+	result, err := h.articles(c).RerenderArticles(req.Force)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ReindexArticles 重建文章搜索索引（管理员）
+func (h *ArticleHandler) ReindexArticles(c *gin.Context) {
+	result, err := h.articles(c).ReindexArticles()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ListRevisions 获取文章修订列表
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type ListRevisionsRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+	}
+
+	var req ListRevisionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取修订列表
+	revisions, err := h.articles(c).ListRevisions(req.ArticleID, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"revisions": revisions})
+}
+
+// DiffRevisions 比较文章的两个修订
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.DiffRevisionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 比较修订
+	result, err := h.articles(c).DiffRevisions(&req, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RestoreRevision 恢复文章到指定修订
+func (h *ArticleHandler) RestoreRevision(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type RestoreRevisionRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+		Number    uint `json:"number" binding:"required"`
+	}
+
+	var req RestoreRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 记录恢复前快照用于审计
+	operatorID := userID.(uint)
+	if before, err := h.articles(c).GetArticle(req.ArticleID, &operatorID, ""); err == nil {
+		middleware.SetAuditBefore(c, before)
+	}
+
+	// 恢复修订
+	article, err := h.articles(c).RestoreRevision(req.ArticleID, req.Number, operatorID)
+	if err != nil {
+		if respondConflict(c, err) {
+			return
+		}
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	setArticleETag(c, article.Version)
+	response.Success(c, article)
+}
+
+// SaveDraft 自动保存草稿
+func (h *ArticleHandler) SaveDraft(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.SaveDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 保存草稿
+	draft, err := h.articles(c).SaveDraft(&req, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(c, draft)
+}
+
+// GetDraft 获取自动保存的草稿
+func (h *ArticleHandler) GetDraft(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type GetDraftRequest struct {
+		ArticleID uint `json:"articleId"` // 0 表示新文章
+	}
+
+	var req GetDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取草稿
+	draft, err := h.articles(c).GetDraft(req.ArticleID, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	response.Success(c, draft)
+}
+
+// DiscardDraft 丢弃自动保存的草稿
+func (h *ArticleHandler) DiscardDraft(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type DiscardDraftRequest struct {
+		ArticleID uint `json:"articleId"` // 0 表示新文章
+	}
+
+	var req DiscardDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 丢弃草稿
+	if err := h.articles(c).DiscardDraft(req.ArticleID, userID.(uint)); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AcquireEditLock 获取文章编辑锁
+func (h *ArticleHandler) AcquireEditLock(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.EditLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取编辑锁
+	result, err := h.articles(c).AcquireEditLock(&req, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// HeartbeatEditLock 编辑锁心跳续期
+func (h *ArticleHandler) HeartbeatEditLock(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.EditLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 续期
+	result, err := h.articles(c).HeartbeatEditLock(&req, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ReleaseEditLock 释放文章编辑锁
+func (h *ArticleHandler) ReleaseEditLock(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.EditLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 释放编辑锁
+	if err := h.articles(c).ReleaseEditLock(&req, userID.(uint)); err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetEditLock 查询文章当前的编辑锁
+func (h *ArticleHandler) GetEditLock(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type GetEditLockRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+	}
+
+	var req GetEditLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 查询编辑锁
+	result, err := h.articles(c).GetEditLock(req.ArticleID, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CreatePreview 创建文章预览链接
+func (h *ArticleHandler) CreatePreview(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	var req service.CreatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 创建预览链接
+	preview, err := h.previews(c).CreatePreview(&req, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	middleware.SetAuditResourceID(c, req.ArticleID)
+	response.Success(c, preview)
+}
+
+// ListPreviews 获取文章的预览链接列表
+func (h *ArticleHandler) ListPreviews(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type ListPreviewsRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+	}
+
+	var req ListPreviewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 获取预览链接列表
+	previews, err := h.previews(c).ListPreviews(req.ArticleID, userID.(uint))
+	if err != nil {
+		response.Error(c, http.StatusForbidden, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"previews": previews})
+}
+
+// RevokePreview 撤销文章预览链接
+func (h *ArticleHandler) RevokePreview(c *gin.Context) {
+	// 获取当前用户ID
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Error(c, http.StatusUnauthorized, "未登录")
+		return
+	}
+
+	// 绑定请求参数
+	type RevokePreviewRequest struct {
+		ArticleID uint `json:"articleId" binding:"required"`
+		ID        uint `json:"id" binding:"required"`
+	}
+
+	var req RevokePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "参数错误: "+err.Error())
+		return
+	}
+
+	// 撤销预览链接
+	if err := h.previews(c).RevokePreview(req.ArticleID, req.ID, userID.(uint)); err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	middleware.SetAuditResourceID(c, req.ArticleID)
+	response.Success(c, nil)
+}
+
+// previewToken 获取请求中的预览令牌，请求体优先
+func previewToken(c *gin.Context, fromBody string) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	return c.GetHeader("X-Preview-Token")
+}
+
+// unlockToken 获取请求中的解锁令牌，依次取请求体、X-Unlock-Token 请求头和文章对应的 Cookie
+func unlockToken(c *gin.Context, fromBody string, articleID uint) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	if token := c.GetHeader("X-Unlock-Token"); token != "" {
+		return token
+	}
+	token, _ := c.Cookie(unlockCookieName(articleID))
+	return token
+}
+
+// unlockCookieName 解锁 Cookie 名称，每篇文章单独一个
+func unlockCookieName(articleID uint) string {
+	return fmt.Sprintf("article_unlock_%d", articleID)
+}
+
+// setVisibilityHeaders 受保护文章的响应因访问者而异，禁止共享缓存
+func setVisibilityHeaders(c *gin.Context, article *model.Article) {
+	if article.Visibility.IsProtected() {
+		c.Header("Cache-Control", "private, no-store")
+	}
+}
+
+// previewAccess 收集预览访问的请求信息用于审计
+func previewAccess(c *gin.Context, userID *uint) *service.PreviewAccess {
+	return &service.PreviewAccess{
+		UserID:    userID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Path:      c.Request.URL.Path,
+	}
+}
+
+// setPreviewHeaders 预览内容不缓存、不被搜索引擎收录
+func setPreviewHeaders(c *gin.Context) {
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+}
+
+// setArticleETag 以文章版本号设置 ETag 响应头
+func setArticleETag(c *gin.Context, version uint) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseArticleETag 解析 If-Match 中的文章版本号，支持 "3"、W/"3" 和 3
+func parseArticleETag(value string) (uint, bool) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// respondListError 文章列表查询失败时返回错误响应，筛选条件或分页游标无效时返回 400
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidListFilter) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, err.Error())
+}
+
+// respondConflict 版本冲突时返回冲突响应（携带当前版本号），返回是否已处理
+func respondConflict(c *gin.Context, err error) bool {
+	var conflict *service.ArticleConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	setArticleETag(c, conflict.CurrentVersion)
+	response.Conflict(c, conflict.Error(), conflict)
+	return true
+}
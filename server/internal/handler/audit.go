@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 审计日志处理器
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+// NewAuditHandler 创建审计日志处理器实例
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListLogs 查询审计日志 POST /api/admin/logs/list
+func (h *AuditHandler) ListLogs(c *gin.Context) {
+	var req service.AuditLogQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.auditService.ListLogs(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ExportLogs 导出审计日志CSV POST /api/admin/logs/export
+func (h *AuditHandler) ExportLogs(c *gin.Context) {
+	var req service.AuditLogQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	// 先写入缓冲区，导出失败时仍可返回统一的JSON错误
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF") // UTF-8 BOM，保证 Excel 正确识别中文
+	if _, err := h.auditService.ExportCSV(&req, &buf); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
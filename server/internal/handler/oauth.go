@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie 保存授权 state 的 Cookie，回调时校验发起授权和完成授权的是同一浏览器
+const (
+	oauthStateCookie     = "oauth_state"
+	oauthStateCookiePath = "/api/auth/oauth"
+)
+
+// OAuthHandler 第三方登录处理器
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+// NewOAuthHandler 创建第三方登录处理器实例
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// ListProviders 获取可用的第三方登录方式 POST /api/auth/oauth/providers
+func (h *OAuthHandler) ListProviders(c *gin.Context) {
+	response.Success(c, h.oauthService.ListProviders())
+}
+
+// Authorize 生成第三方授权地址 POST /api/auth/oauth/authorize
+func (h *OAuthHandler) Authorize(c *gin.Context) {
+	type AuthorizeRequest struct {
+		Provider string `json:"provider" binding:"required"`
+	}
+
+	var req AuthorizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.oauthService.BuildAuthorizationURL(req.Provider, 0)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	setOAuthStateCookie(c, result)
+	response.Success(c, result)
+}
+
+// Callback 处理第三方授权回调 POST /api/auth/oauth/callback
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	type CallbackRequest struct {
+		Provider string `json:"provider" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+		State    string `json:"state" binding:"required"`
+	}
+
+	var req CallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	// state 只能使用一次，无论成功与否都清除 Cookie
+	browserState, _ := c.Cookie(oauthStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, "", -1, oauthStateCookiePath, "", c.Request.TLS != nil, true)
+
+	result, err := h.oauthService.HandleCallback(req.Provider, req.Code, req.State, browserState)
+	if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	data := gin.H{
+		"user":         result.User.ToResponse(),
+		"accessToken":  result.AccessToken,
+		"refreshToken": result.RefreshToken,
+		"expiresIn":    result.ExpiresIn,
+		"provider":     result.Provider,
+		"linked":       result.Linked,
+		"created":      result.Created,
+	}
+
+	response.SuccessWithMessage(c, "登录成功", data)
+}
+
+// ListIdentities 获取当前用户绑定的第三方身份 POST /api/users/identities/list
+func (h *OAuthHandler) ListIdentities(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	identities, err := h.oauthService.ListIdentities(userID.(uint))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, identities)
+}
+
+// LinkIdentity 为当前用户生成绑定第三方身份的授权地址 POST /api/users/identities/link
+func (h *OAuthHandler) LinkIdentity(c *gin.Context) {
+	type LinkIdentityRequest struct {
+		Provider string `json:"provider" binding:"required"`
+	}
+
+	var req LinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	result, err := h.oauthService.BuildAuthorizationURL(req.Provider, userID.(uint))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	setOAuthStateCookie(c, result)
+	response.Success(c, result)
+}
+
+// UnlinkIdentity 解除当前用户的第三方身份绑定 POST /api/users/identities/unlink
+func (h *OAuthHandler) UnlinkIdentity(c *gin.Context) {
+	type UnlinkIdentityRequest struct {
+		Provider string `json:"provider" binding:"required"`
+	}
+
+	var req UnlinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.oauthService.UnlinkIdentity(userID.(uint), req.Provider); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "解除绑定成功", nil)
+}
+
+// setOAuthStateCookie 将授权 state 写入仅回调接口可读的 HttpOnly Cookie
+func setOAuthStateCookie(c *gin.Context, result *service.OAuthAuthorizeResult) {
+	maxAge := int(time.Until(result.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, result.State, maxAge, oauthStateCookiePath, "", c.Request.TLS != nil, true)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalTokenHandler 个人访问令牌处理器
+type PersonalTokenHandler struct {
+	tokenService service.PersonalTokenService
+}
+
+// NewPersonalTokenHandler 创建个人访问令牌处理器实例
+func NewPersonalTokenHandler(tokenService service.PersonalTokenService) *PersonalTokenHandler {
+	return &PersonalTokenHandler{
+		tokenService: tokenService,
+	}
+}
+
+// CreateToken 创建个人访问令牌 POST /api/users/tokens/create
+func (h *PersonalTokenHandler) CreateToken(c *gin.Context) {
+	var req service.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	token, err := h.tokenService.CreateToken(userID.(uint), &req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "令牌创建成功，请妥善保存，令牌明文只显示一次", token)
+}
+
+// ListTokens 获取当前用户的令牌列表 POST /api/users/tokens/list
+func (h *PersonalTokenHandler) ListTokens(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	tokens, err := h.tokenService.ListTokens(userID.(uint))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// RevokeToken 撤销个人访问令牌 POST /api/users/tokens/revoke
+func (h *PersonalTokenHandler) RevokeToken(c *gin.Context) {
+	type RevokeTokenRequest struct {
+		ID uint `json:"id" binding:"required,min=1"`
+	}
+
+	var req RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "用户未认证")
+		return
+	}
+
+	if err := h.tokenService.RevokeToken(userID.(uint), req.ID); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "令牌已撤销", nil)
+}
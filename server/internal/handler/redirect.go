@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"net/http"
+
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedirectHandler 跳转规则处理器
+type RedirectHandler struct {
+	redirectService service.RedirectService
+}
+
+// NewRedirectHandler 创建跳转规则处理器实例
+func NewRedirectHandler(redirectService service.RedirectService) *RedirectHandler {
+	return &RedirectHandler{
+		redirectService: redirectService,
+	}
+}
+
+// ListRedirects 获取跳转规则列表 POST /api/admin/redirects/list
+func (h *RedirectHandler) ListRedirects(c *gin.Context) {
+	var req service.RedirectListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.redirectService.ListRedirects(&req)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// CreateRedirect 创建跳转规则 POST /api/admin/redirects/create
+func (h *RedirectHandler) CreateRedirect(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, "未登录")
+		return
+	}
+
+	var req service.SaveRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	redirect, err := h.redirectService.CreateRedirect(&req, userID.(uint))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "跳转规则创建成功", redirect)
+}
+
+// UpdateRedirect 更新跳转规则 POST /api/admin/redirects/update
+func (h *RedirectHandler) UpdateRedirect(c *gin.Context) {
+	var req service.SaveRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	// 记录变更前快照用于审计
+	if before, err := h.redirectService.GetRedirect(req.ID); err == nil {
+		middleware.SetAuditBefore(c, before)
+	}
+
+	redirect, err := h.redirectService.UpdateRedirect(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "跳转规则更新成功", redirect)
+}
+
+// DeleteRedirect 删除跳转规则 POST /api/admin/redirects/delete
+func (h *RedirectHandler) DeleteRedirect(c *gin.Context) {
+	type DeleteRedirectRequest struct {
+		ID uint `json:"id" binding:"required"`
+	}
+
+	var req DeleteRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	// 记录删除前快照用于审计
+	if before, err := h.redirectService.GetRedirect(req.ID); err == nil {
+		middleware.SetAuditBefore(c, before)
+	}
+
+	if err := h.redirectService.DeleteRedirect(req.ID); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "跳转规则删除成功", nil)
+}
+
+// ResolveRedirect 查询路径的跳转目标 POST /api/redirects/resolve
+// 供前端路由和服务端渲染在页面不存在时调用，没有规则时 data 为 null
+func (h *RedirectHandler) ResolveRedirect(c *gin.Context) {
+	type ResolveRedirectRequest struct {
+		Path string `json:"path" binding:"required,max=500"`
+	}
+
+	var req ResolveRedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	redirect, err := h.redirectService.Resolve(req.Path)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	if redirect == nil {
+		response.Success(c, nil)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"toPath":     redirect.ToPath,
+		"statusCode": redirect.StatusCode,
+	})
+}
+
+// ServeRedirect 未匹配路由的 GET/HEAD 请求按跳转规则直接返回 301/302
+// 没有规则时返回 HTTP 404，避免不存在的页面被当作正常页面收录
+func (h *RedirectHandler) ServeRedirect(c *gin.Context) {
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		if redirect, err := h.redirectService.Resolve(c.Request.URL.Path); err == nil && redirect != nil {
+			c.Redirect(redirect.StatusCode, redirect.ToPath)
+			return
+		}
+	}
+	c.JSON(http.StatusNotFound, response.Response{
+		Code:    response.CodeNotFound,
+		Message: "资源不存在",
+	})
+}
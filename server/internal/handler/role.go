@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler 角色管理处理器
+type RoleHandler struct {
+	rbacService service.RBACService
+}
+
+// NewRoleHandler 创建角色管理处理器实例
+func NewRoleHandler(rbacService service.RBACService) *RoleHandler {
+	return &RoleHandler{
+		rbacService: rbacService,
+	}
+}
+
+// ListRoles 获取角色列表 POST /api/admin/roles/list
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.rbacService.ListRoles()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, roles)
+}
+
+// ListPermissions 获取系统全部权限 POST /api/admin/roles/permissions
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	response.Success(c, service.AllPermissions())
+}
+
+// CreateRole 创建自定义角色 POST /api/admin/roles/create
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req service.SaveRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	role, err := h.rbacService.CreateRole(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "角色创建成功", role)
+}
+
+// UpdateRole 更新角色 POST /api/admin/roles/update
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	var req service.SaveRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	role, err := h.rbacService.UpdateRole(&req)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "角色更新成功", role)
+}
+
+// DeleteRole 删除自定义角色 POST /api/admin/roles/delete
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	type DeleteRoleRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	var req DeleteRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	if err := h.rbacService.DeleteRole(req.Name); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "角色删除成功", nil)
+}
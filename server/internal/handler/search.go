@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchHandler 搜索建议与统计处理器
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler 创建搜索建议与统计处理器实例
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Suggest 搜索建议 POST /api/search/suggest
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	var req service.SearchSuggestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.searchService.Suggest(&req)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// HotSearches 热门搜索 POST /api/search/hot
+func (h *SearchHandler) HotSearches(c *gin.Context) {
+	var req service.HotSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.searchService.HotSearches(&req)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ZeroResultReport 无结果搜索报告 POST /api/admin/search/zero-results
+func (h *SearchHandler) ZeroResultReport(c *gin.Context) {
+	var req service.ZeroResultReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.searchService.ZeroResultReport(&req)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler 后台统计处理器
+type StatsHandler struct {
+	statsService service.StatsService
+}
+
+// NewStatsHandler 创建后台统计处理器实例
+func NewStatsHandler(statsService service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// Overview 站点总量 POST /api/admin/stats/overview
+func (h *StatsHandler) Overview(c *gin.Context) {
+	result, err := h.statsService.Overview()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// TimeSeries 每日趋势 POST /api/admin/stats/timeseries
+func (h *StatsHandler) TimeSeries(c *gin.Context) {
+	var req service.StatsRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.statsService.TimeSeries(&req)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// Top 排行 POST /api/admin/stats/top
+func (h *StatsHandler) Top(c *gin.Context) {
+	var req service.StatsTopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.statsService.Top(&req)
+	if err != nil {
+		respondStatsError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// respondStatsError 日期范围无效返回400，其他错误返回500
+func respondStatsError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidStatsRange) {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.InternalError(c, err.Error())
+}
@@ -2,6 +2,7 @@
 package handler
 
 import (
+	"MyBlog/internal/middleware"
 	"MyBlog/internal/repository"
 	"MyBlog/internal/service"
 	"MyBlog/pkg/response"
@@ -70,6 +71,7 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		response.Forbidden(c, "权限不足，无法管理该角色的用户")
 		return
 	}
+	middleware.SetAuditBefore(c, targetUser.ToResponse())
 
 	// 如果要修改角色，需要验证角色转换
 	if req.Role != "" && req.Role != targetUser.Role {
@@ -198,6 +200,7 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		response.Forbidden(c, "权限不足，无法删除该角色的用户")
 		return
 	}
+	middleware.SetAuditBefore(c, targetUser.ToResponse())
 
 	if err := h.userService.DeleteUser(req.ID); err != nil {
 		response.InternalError(c, err.Error())
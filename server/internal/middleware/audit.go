@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+
+	"MyBlog/internal/service"
+	"MyBlog/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 审计相关的 Context 键
+const (
+	auditBeforeKey   = "auditBefore"
+	auditAfterKey    = "auditAfter"
+	auditResourceKey = "auditResourceID"
+)
+
+// 审计时读取的请求体上限，超出部分不记录
+const auditMaxRequestBody = 64 << 10
+
+// auditResponseWriter 在写回客户端的同时缓存响应体，用于判断业务结果
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write 写入响应并缓存
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写入字符串响应并缓存
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// SetAuditBefore 设置资源变更前的快照，用于生成审计差异
+func SetAuditBefore(c *gin.Context, snapshot interface{}) {
+	c.Set(auditBeforeKey, snapshot)
+}
+
+// SetAuditAfter 设置资源变更后的快照，未设置时使用响应中的 data
+func SetAuditAfter(c *gin.Context, snapshot interface{}) {
+	c.Set(auditAfterKey, snapshot)
+}
+
+// SetAuditResourceID 显式指定审计的资源ID，未设置时从请求或响应中推断
+func SetAuditResourceID(c *gin.Context, id uint) {
+	c.Set(auditResourceKey, id)
+}
+
+// Audit 审计中间件，记录成功的变更操作
+// 需放在认证中间件之后，以便获取操作用户；业务失败（响应码非成功）不记录
+func Audit(auditService service.AuditService, action, resourceType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auditService == nil {
+			c.Next()
+			return
+		}
+
+		// 读取请求体后重新放回，供处理器正常绑定
+		var requestBody []byte
+		if c.Request.Body != nil {
+			requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, auditMaxRequestBody))
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(requestBody), c.Request.Body))
+		}
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		var resp struct {
+			Code int             `json:"code"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(writer.body.Bytes(), &resp); err != nil || resp.Code != response.CodeSuccess {
+			return
+		}
+
+		var request map[string]interface{}
+		_ = json.Unmarshal(requestBody, &request)
+		var data map[string]interface{}
+		_ = json.Unmarshal(resp.Data, &data)
+
+		entry := &service.AuditEntry{
+			Action:       action,
+			ResourceType: resourceType,
+			ResourceID:   auditResourceID(c, request, data),
+			IPAddress:    c.ClientIP(),
+			UserAgent:    c.Request.UserAgent(),
+			Extra: map[string]interface{}{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+			},
+		}
+		if userID, exists := c.Get("userID"); exists {
+			if id, ok := userID.(uint); ok {
+				entry.UserID = &id
+			}
+		}
+		if requestID, exists := c.Get("RequestID"); exists {
+			entry.Extra["requestId"] = requestID
+		}
+
+		before, hasBefore := c.Get(auditBeforeKey)
+		after, hasAfter := c.Get(auditAfterKey)
+		if !hasAfter && data != nil {
+			if _, isMessage := data["message"]; !isMessage || len(data) > 1 {
+				after, hasAfter = data, true
+			}
+		}
+		if hasBefore {
+			entry.Before = before
+		}
+		if hasAfter {
+			entry.After = after
+		}
+		// 没有任何快照时记录请求参数，便于追溯操作内容
+		if !hasBefore && !hasAfter && request != nil {
+			entry.Extra["request"] = request
+		}
+
+		if err := auditService.Record(entry); err != nil {
+			log.Printf("写入审计日志失败: action=%s, err=%v", action, err)
+		}
+	}
+}
+
+// auditResourceID 推断资源ID：显式设置 > 请求中的 articleId/id > 响应中的 id
+func auditResourceID(c *gin.Context, request, data map[string]interface{}) *uint {
+	if value, exists := c.Get(auditResourceKey); exists {
+		if id, ok := value.(uint); ok && id > 0 {
+			return &id
+		}
+	}
+	for _, source := range []map[string]interface{}{request, data} {
+		for _, key := range []string{"articleId", "id"} {
+			if number, ok := source[key].(float64); ok && number > 0 {
+				id := uint(number)
+				return &id
+			}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"errors"
+
 	"MyBlog/internal/repository"
 	"MyBlog/internal/service"
 	"MyBlog/pkg/response"
@@ -9,6 +11,34 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// personalTokenService 个人访问令牌服务，由 SetPersonalTokenService 注入
+var personalTokenService service.PersonalTokenService
+
+// SetPersonalTokenService 设置个人访问令牌服务，未设置时不接受个人访问令牌
+func SetPersonalTokenService(s service.PersonalTokenService) {
+	personalTokenService = s
+}
+
+// authenticatePersonalToken 校验个人访问令牌并返回所属用户和授权范围
+func authenticatePersonalToken(c *gin.Context, token string) (*repository.User, []service.Permission, error) {
+	if personalTokenService == nil {
+		return nil, nil, errors.New("未启用个人访问令牌")
+	}
+	return personalTokenService.Authenticate(token, c.ClientIP())
+}
+
+// DenyPersonalToken 禁止使用个人访问令牌访问的中间件（如令牌管理、账号绑定等敏感操作）
+func DenyPersonalToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("tokenScopes"); exists {
+			response.Forbidden(c, "个人访问令牌不能访问该资源，请使用账号登录")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // Auth 认证中间件
 func Auth(jwtService service.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -25,6 +55,20 @@ func Auth(jwtService service.JWTService) gin.HandlerFunc {
 			token = token[7:]
 		}
 
+		// 个人访问令牌
+		if service.IsPersonalToken(token) {
+			user, scopes, err := authenticatePersonalToken(c, token)
+			if err != nil {
+				response.Unauthorized(c, err.Error())
+				c.Abort()
+				return
+			}
+			c.Set("userID", user.ID)
+			c.Set("tokenScopes", scopes)
+			c.Next()
+			return
+		}
+
 		// 验证访问令牌
 		claims, err := jwtService.ValidateAccessToken(token)
 		if err != nil {
@@ -52,6 +96,17 @@ func OptionalAuth(jwtService service.JWTService) gin.HandlerFunc {
 				token = token[7:]
 			}
 
+			// 个人访问令牌
+			if service.IsPersonalToken(token) {
+				if user, scopes, err := authenticatePersonalToken(c, token); err == nil {
+					c.Set("userID", user.ID)
+					c.Set("tokenScopes", scopes)
+					c.Set("authenticated", true)
+				}
+				c.Next()
+				return
+			}
+
 			// 验证访问令牌
 			if claims, err := jwtService.ValidateAccessToken(token); err == nil {
 				c.Set("userID", claims.UserID)
@@ -11,7 +11,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, If-Match, X-Preview-Token, X-Unlock-Token")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
 		// 处理预检请求
@@ -36,6 +37,9 @@ func CORSWithConfig(config CORSConfig) gin.HandlerFunc {
 
 		c.Header("Access-Control-Allow-Methods", joinStrings(config.AllowedMethods, ", "))
 		c.Header("Access-Control-Allow-Headers", joinStrings(config.AllowedHeaders, ", "))
+		if len(config.ExposedHeaders) > 0 {
+			c.Header("Access-Control-Expose-Headers", joinStrings(config.ExposedHeaders, ", "))
+		}
 
 		if config.AllowCredentials {
 			c.Header("Access-Control-Allow-Credentials", "true")
@@ -57,6 +61,7 @@ type CORSConfig struct {
 	AllowedOrigins   []string
 	AllowedMethods   []string
 	AllowedHeaders   []string
+	ExposedHeaders   []string
 	AllowCredentials bool
 }
 
@@ -66,7 +71,8 @@ func DefaultCORSConfig() CORSConfig {
 		AllowAllOrigins:  false,
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:8080"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With", "If-Match", "X-Preview-Token", "X-Unlock-Token"},
+		ExposedHeaders:   []string{"ETag"},
 		AllowCredentials: true,
 	}
 }
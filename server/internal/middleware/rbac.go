@@ -15,15 +15,15 @@ import (
 func RequirePermission(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, permissions ...service.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
-		// 检查权限
+		// 检查权限（个人访问令牌取授权范围与角色权限的交集）
 		hasPermission := false
 		for _, permission := range permissions {
-			if rbacService.HasPermission(user.Role, permission) {
+			if rbacService.HasPermission(user.Role, permission) && tokenHasScope(c, permission) {
 				hasPermission = true
 				break
 			}
@@ -45,13 +45,13 @@ func RequirePermission(jwtService service.JWTService, userRepo repository.UserRe
 func RequireAllPermissions(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, permissions ...service.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
 		// 检查是否拥有所有权限
-		if !rbacService.HasAllPermissions(user.Role, permissions...) {
+		if !rbacService.HasAllPermissions(user.Role, permissions...) || !tokenHasAllScopes(c, permissions...) {
 			response.Forbidden(c, "权限不足，缺少必要权限")
 			c.Abort()
 			return
@@ -67,11 +67,16 @@ func RequireAllPermissions(jwtService service.JWTService, userRepo repository.Us
 func RequireRoleLevel(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, minRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
+		// 基于角色的校验不受令牌授权范围约束，不接受个人访问令牌
+		if rejectPersonalToken(c) {
+			return
+		}
+
 		// 检查角色级别
 		if !rbacService.IsRoleHigherThan(user.Role, minRole) && user.Role != minRole {
 			response.Forbidden(c, "权限不足，需要更高角色权限")
@@ -86,14 +91,19 @@ func RequireRoleLevel(jwtService service.JWTService, userRepo repository.UserRep
 }
 
 // RequireSuperAdmin 要求超级管理员权限的中间件
-func RequireSuperAdmin(jwtService service.JWTService, userRepo repository.UserRepository) gin.HandlerFunc {
+func RequireSuperAdmin(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
+		// 基于角色的校验不受令牌授权范围约束，不接受个人访问令牌
+		if rejectPersonalToken(c) {
+			return
+		}
+
 		// 检查是否为超级管理员
 		if user.Role != string(service.RoleSuperAdmin) {
 			response.Forbidden(c, "权限不足，需要超级管理员权限")
@@ -108,14 +118,19 @@ func RequireSuperAdmin(jwtService service.JWTService, userRepo repository.UserRe
 }
 
 // RequireAdminOrAbove 要求管理员或更高权限的中间件
-func RequireAdminOrAbove(jwtService service.JWTService, userRepo repository.UserRepository) gin.HandlerFunc {
+func RequireAdminOrAbove(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
+		// 基于角色的校验不受令牌授权范围约束，不接受个人访问令牌
+		if rejectPersonalToken(c) {
+			return
+		}
+
 		// 检查是否为管理员或超级管理员
 		if !service.IsAdminRole(user.Role) {
 			response.Forbidden(c, "权限不足，需要管理员权限")
@@ -130,14 +145,19 @@ func RequireAdminOrAbove(jwtService service.JWTService, userRepo repository.User
 }
 
 // RequireEditorOrAbove 要求编辑者或更高权限的中间件
-func RequireEditorOrAbove(jwtService service.JWTService, userRepo repository.UserRepository) gin.HandlerFunc {
+func RequireEditorOrAbove(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
+		// 基于角色的校验不受令牌授权范围约束，不接受个人访问令牌
+		if rejectPersonalToken(c) {
+			return
+		}
+
 		// 检查是否为编辑者或更高权限
 		if !service.IsEditorOrAbove(user.Role) {
 			response.Forbidden(c, "权限不足，需要编辑者或更高权限")
@@ -153,10 +173,10 @@ func RequireEditorOrAbove(jwtService service.JWTService, userRepo repository.Use
 
 // RequireOwnershipOrAdmin 要求资源所有权或管理员权限的中间件
 // 用于检查用户是否可以操作特定资源（自己的资源或管理员权限）
-func RequireOwnershipOrAdmin(jwtService service.JWTService, userRepo repository.UserRepository, getResourceOwnerID func(*gin.Context) (uint, error)) gin.HandlerFunc {
+func RequireOwnershipOrAdmin(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, getResourceOwnerID func(*gin.Context) (uint, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
@@ -192,11 +212,16 @@ func RequireOwnershipOrAdmin(jwtService service.JWTService, userRepo repository.
 func CanManageUserRole(jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, getTargetRole func(*gin.Context) (string, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 验证JWT令牌
-		user, err := validateUserFromToken(c, jwtService, userRepo)
+		user, err := validateUserFromToken(c, jwtService, userRepo, rbacService)
 		if err != nil {
 			return // 错误已在validateUserFromToken中处理
 		}
 
+		// 基于角色的校验不受令牌授权范围约束，不接受个人访问令牌
+		if rejectPersonalToken(c) {
+			return
+		}
+
 		// 获取目标用户角色
 		targetRole, err := getTargetRole(c)
 		if err != nil {
@@ -219,7 +244,7 @@ func CanManageUserRole(jwtService service.JWTService, userRepo repository.UserRe
 }
 
 // validateUserFromToken 从JWT令牌验证用户身份
-func validateUserFromToken(c *gin.Context, jwtService service.JWTService, userRepo repository.UserRepository) (*repository.User, error) {
+func validateUserFromToken(c *gin.Context, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) (*repository.User, error) {
 	token := c.GetHeader("Authorization")
 
 	if token == "" {
@@ -233,20 +258,33 @@ func validateUserFromToken(c *gin.Context, jwtService service.JWTService, userRe
 		token = token[7:]
 	}
 
-	// 验证访问令牌
-	claims, err := jwtService.ValidateAccessToken(token)
-	if err != nil {
-		response.Unauthorized(c, "无效的认证令牌")
-		c.Abort()
-		return nil, err
-	}
+	var user *repository.User
+	if service.IsPersonalToken(token) {
+		// 个人访问令牌
+		tokenUser, scopes, err := authenticatePersonalToken(c, token)
+		if err != nil {
+			response.Unauthorized(c, err.Error())
+			c.Abort()
+			return nil, err
+		}
+		c.Set("tokenScopes", scopes)
+		user = tokenUser
+	} else {
+		// 验证访问令牌
+		claims, err := jwtService.ValidateAccessToken(token)
+		if err != nil {
+			response.Unauthorized(c, "无效的认证令牌")
+			c.Abort()
+			return nil, err
+		}
 
-	// 从数据库查询用户信息
-	user, err := userRepo.GetByID(claims.UserID)
-	if err != nil {
-		response.Unauthorized(c, "用户不存在")
-		c.Abort()
-		return nil, err
+		// 从数据库查询用户信息
+		user, err = userRepo.GetByID(claims.UserID)
+		if err != nil {
+			response.Unauthorized(c, "用户不存在")
+			c.Abort()
+			return nil, err
+		}
 	}
 
 	// 验证用户状态
@@ -257,7 +295,6 @@ func validateUserFromToken(c *gin.Context, jwtService service.JWTService, userRe
 	}
 
 	// 验证角色有效性
-	rbacService := service.NewRBACService()
 	if !rbacService.IsValidRole(user.Role) {
 		response.Forbidden(c, "用户角色无效")
 		c.Abort()
@@ -267,6 +304,40 @@ func validateUserFromToken(c *gin.Context, jwtService service.JWTService, userRe
 	return user, nil
 }
 
+// tokenHasScope 检查个人访问令牌是否包含指定权限，非令牌认证时始终返回 true
+func tokenHasScope(c *gin.Context, permission service.Permission) bool {
+	scopes, ok := GetTokenScopes(c)
+	if !ok {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenHasAllScopes 检查个人访问令牌是否包含全部指定权限
+func tokenHasAllScopes(c *gin.Context, permissions ...service.Permission) bool {
+	for _, permission := range permissions {
+		if !tokenHasScope(c, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// rejectPersonalToken 当前请求使用个人访问令牌时返回403并中止
+func rejectPersonalToken(c *gin.Context) bool {
+	if _, ok := GetTokenScopes(c); ok {
+		response.Forbidden(c, "个人访问令牌不能访问该资源")
+		c.Abort()
+		return true
+	}
+	return false
+}
+
 // setUserContext 设置用户上下文信息
 func setUserContext(c *gin.Context, user *repository.User) {
 	c.Set("userID", user.ID)
@@ -306,3 +377,13 @@ func GetCurrentUserRole(c *gin.Context) (string, bool) {
 	}
 	return "", false
 }
+
+// GetTokenScopes 从上下文获取个人访问令牌的授权范围，非令牌认证时返回 false
+func GetTokenScopes(c *gin.Context) ([]service.Permission, bool) {
+	if scopes, exists := c.Get("tokenScopes"); exists {
+		if s, ok := scopes.([]service.Permission); ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -58,31 +59,37 @@ func (Tag) TableName() string {
 
 // Article 文章模型
 type Article struct {
-	ID             uint           `json:"id" gorm:"primaryKey;comment:文章ID"`
-	Title          string         `json:"title" gorm:"not null;size:200;comment:文章标题"`
-	Slug           string         `json:"slug" gorm:"uniqueIndex;not null;size:200;comment:URL友好标识"`
-	Summary        string         `json:"summary" gorm:"type:text;comment:文章摘要"`
-	Content        string         `json:"content" gorm:"type:longtext;not null;comment:文章内容（Markdown格式）"`
-	ContentHTML    string         `json:"contentHtml" gorm:"type:longtext;comment:文章内容（HTML格式，缓存用）"`
-	CoverImage     string         `json:"coverImage" gorm:"size:500;comment:封面图片URL"`
-	AuthorID       uint           `json:"authorId" gorm:"not null;index;comment:作者ID"`
-	CategoryID     *uint          `json:"categoryId" gorm:"index;comment:主分类ID"`
-	Status         ArticleStatus  `json:"status" gorm:"default:draft;index;comment:文章状态"`
-	IsFeatured     bool           `json:"isFeatured" gorm:"default:false;index;comment:是否精选文章"`
-	IsTop          bool           `json:"isTop" gorm:"default:false;index;comment:是否置顶"`
-	CommentEnabled bool           `json:"commentEnabled" gorm:"default:true;comment:是否允许评论"`
-	ViewCount      uint           `json:"viewCount" gorm:"default:0;index;comment:浏览量"`
-	LikeCount      uint           `json:"likeCount" gorm:"default:0;comment:点赞数"`
-	CommentCount   uint           `json:"commentCount" gorm:"default:0;comment:评论数"`
-	WordCount      uint           `json:"wordCount" gorm:"default:0;comment:字数统计"`
-	ReadingTime    uint           `json:"readingTime" gorm:"default:0;comment:预计阅读时间（分钟）"`
-	SEOTitle       string         `json:"seoTitle" gorm:"size:100;comment:SEO标题"`
-	SEODescription string         `json:"seoDescription" gorm:"size:255;comment:SEO描述"`
-	SEOKeywords    string         `json:"seoKeywords" gorm:"size:200;comment:SEO关键词"`
-	PublishedAt    *time.Time     `json:"publishedAt" gorm:"type:datetime(3);index;comment:发布时间"`
-	CreatedAt      time.Time      `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
-	UpdatedAt      time.Time      `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
-	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index;comment:软删除时间"`
+	ID             uint            `json:"id" gorm:"primaryKey;comment:文章ID"`
+	Title          string          `json:"title" gorm:"not null;size:200;comment:文章标题"`
+	Slug           string          `json:"slug" gorm:"uniqueIndex;not null;size:200;comment:URL友好标识"`
+	Summary        string          `json:"summary" gorm:"type:text;comment:文章摘要"`
+	Content        string          `json:"content" gorm:"type:longtext;not null;comment:文章内容（Markdown格式）"`
+	ContentHTML    string          `json:"contentHtml" gorm:"type:longtext;comment:文章内容（HTML格式，缓存用）"`
+	TOC            json.RawMessage `json:"toc,omitempty" gorm:"type:json;comment:文章目录（标题树，渲染时生成）"`
+	RenderVersion  uint            `json:"-" gorm:"default:0;index;comment:渲染器版本（低于当前版本需重新渲染）"`
+	CoverImage     string          `json:"coverImage" gorm:"size:500;comment:封面图片URL"`
+	AuthorID       uint            `json:"authorId" gorm:"not null;index;comment:作者ID"`
+	CategoryID     *uint           `json:"categoryId" gorm:"index;comment:主分类ID"`
+	Status         ArticleStatus   `json:"status" gorm:"default:draft;index;comment:文章状态"`
+	Visibility     Visibility      `json:"visibility" gorm:"size:20;default:public;index;comment:可见范围 public/password/members"`
+	PasswordHash   string          `json:"-" gorm:"size:255;comment:访问密码（bcrypt加密，仅 password 可见范围使用）"`
+	IsFeatured     bool            `json:"isFeatured" gorm:"default:false;index;comment:是否精选文章"`
+	IsTop          bool            `json:"isTop" gorm:"default:false;index;comment:是否置顶"`
+	CommentEnabled bool            `json:"commentEnabled" gorm:"default:true;comment:是否允许评论"`
+	ViewCount      uint            `json:"viewCount" gorm:"default:0;index;comment:浏览量"`
+	LikeCount      uint            `json:"likeCount" gorm:"default:0;comment:点赞数"`
+	CommentCount   uint            `json:"commentCount" gorm:"default:0;comment:评论数"`
+	WordCount      uint            `json:"wordCount" gorm:"default:0;comment:字数统计"`
+	ReadingTime    uint            `json:"readingTime" gorm:"default:0;comment:预计阅读时间（分钟）"`
+	SEOTitle       string          `json:"seoTitle" gorm:"size:100;comment:SEO标题"`
+	SEODescription string          `json:"seoDescription" gorm:"size:255;comment:SEO描述"`
+	SEOKeywords    string          `json:"seoKeywords" gorm:"size:200;comment:SEO关键词"`
+	Version        uint            `json:"version" gorm:"not null;default:1;comment:版本号（乐观锁，每次修改递增）"`
+	PublishedAt    *time.Time      `json:"publishedAt" gorm:"type:datetime(3);index;comment:发布时间（定时发布时为计划发布时间）"`
+	ExpiresAt      *time.Time      `json:"expiresAt" gorm:"type:datetime(3);index;comment:下线时间（到期后自动归档）"`
+	CreatedAt      time.Time       `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+	UpdatedAt      time.Time       `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+	DeletedAt      gorm.DeletedAt  `json:"-" gorm:"index;comment:软删除时间"`
 
 	// 关联关系
 	Author     User              `json:"author" gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"`
@@ -93,6 +100,25 @@ type Article struct {
 	Views      []ArticleView     `json:"-" gorm:"foreignKey:ArticleID"`
 	Likes      []ArticleLike     `json:"-" gorm:"foreignKey:ArticleID"`
 	Bookmarks  []ArticleBookmark `json:"-" gorm:"foreignKey:ArticleID"`
+
+	// 协作者（仅详情接口填充）
+	Collaborators []ArticleCollaborator `json:"collaborators,omitempty" gorm:"foreignKey:ArticleID"`
+
+	// 正文是否因可见范围被隐藏（不入库，由服务层按访问者设置）
+	Locked bool `json:"locked,omitempty" gorm:"-"`
+
+	// 通过历史Slug访问时为请求中的旧Slug，客户端应跳转到当前 slug（不入库）
+	MovedFrom string `json:"movedFrom,omitempty" gorm:"-"`
+
+	// 搜索结果中的高亮片段（仅搜索接口填充，不入库）
+	Highlight *ArticleHighlight `json:"highlight,omitempty" gorm:"-"`
+}
+
+// ArticleHighlight 搜索命中的高亮片段，均为转义后的HTML，命中词以 <mark> 包裹
+type ArticleHighlight struct {
+	Title   string `json:"title,omitempty"`   // 高亮后的完整标题
+	Summary string `json:"summary,omitempty"` // 摘要片段
+	Content string `json:"content,omitempty"` // 正文片段（纯文本，不含代码块）
 }
 
 // TableName 指定表名
@@ -117,16 +143,150 @@ func (ArticleTag) TableName() string {
 	return "article_tags"
 }
 
+// ArticleCollaborator 文章协作者模型
+type ArticleCollaborator struct {
+	ID        uint             `json:"id" gorm:"primaryKey;comment:协作记录ID"`
+	ArticleID uint             `json:"articleId" gorm:"not null;uniqueIndex:idx_article_collaborator;comment:文章ID"`
+	UserID    uint             `json:"userId" gorm:"not null;uniqueIndex:idx_article_collaborator;index;comment:协作者用户ID"`
+	Role      CollaboratorRole `json:"role" gorm:"not null;size:20;comment:协作权限 viewer/editor/publisher"`
+	InvitedBy uint             `json:"invitedBy" gorm:"not null;comment:邀请人ID"`
+	CreatedAt time.Time        `json:"createdAt" gorm:"type:datetime(3);comment:邀请时间"`
+	UpdatedAt time.Time        `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+
+	// 关联关系
+	Article Article `json:"-" gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE"`
+	User    User    `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ArticleCollaborator) TableName() string {
+	return "article_collaborators"
+}
+
+// ArticleRevision 文章修订历史模型（每次保存生成一个快照）
+type ArticleRevision struct {
+	ID        uint      `json:"id" gorm:"primaryKey;comment:修订ID"`
+	ArticleID uint      `json:"articleId" gorm:"not null;uniqueIndex:idx_article_revision;comment:文章ID"`
+	Number    uint      `json:"number" gorm:"not null;uniqueIndex:idx_article_revision;comment:修订号（文章内递增）"`
+	Title     string    `json:"title" gorm:"not null;size:200;comment:文章标题"`
+	Summary   string    `json:"summary" gorm:"type:text;comment:文章摘要"`
+	Content   string    `json:"content,omitempty" gorm:"type:longtext;not null;comment:文章内容（Markdown格式）"`
+	EditorID  uint      `json:"editorId" gorm:"not null;index;comment:保存人ID"`
+	Note      string    `json:"note" gorm:"size:255;comment:修订说明"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:datetime(3);index;comment:保存时间"`
+
+	// 关联关系
+	Article Article `json:"-" gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE"`
+	Editor  User    `json:"editor" gorm:"foreignKey:EditorID"`
+}
+
+// TableName 指定表名
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
+
+// ArticleDraft 文章自动保存草稿（每个用户每篇文章一份，与正式文章分开存储）
+// ArticleID 为 0 表示尚未创建的新文章
+type ArticleDraft struct {
+	ID          uint      `json:"id" gorm:"primaryKey;comment:草稿ID"`
+	ArticleID   uint      `json:"articleId" gorm:"not null;default:0;uniqueIndex:idx_article_draft_user;comment:文章ID（0表示新文章）"`
+	UserID      uint      `json:"userId" gorm:"not null;uniqueIndex:idx_article_draft_user;index;comment:用户ID"`
+	Title       string    `json:"title" gorm:"size:200;comment:文章标题"`
+	Summary     string    `json:"summary" gorm:"type:text;comment:文章摘要"`
+	Content     string    `json:"content" gorm:"type:longtext;comment:文章内容（Markdown格式）"`
+	BaseVersion uint      `json:"baseVersion" gorm:"not null;default:0;comment:草稿基于的文章版本号"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"type:datetime(3);comment:最后自动保存时间"`
+
+	// 关联关系
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ArticleDraft) TableName() string {
+	return "article_drafts"
+}
+
+// ArticleEditLock 文章编辑锁（提示性锁，每篇文章一条，过期后可被他人获取）
+type ArticleEditLock struct {
+	ID          uint      `json:"-" gorm:"primaryKey;comment:编辑锁ID"`
+	ArticleID   uint      `json:"articleId" gorm:"not null;uniqueIndex;comment:文章ID"`
+	UserID      uint      `json:"userId" gorm:"not null;index;comment:持有人ID"`
+	SessionID   string    `json:"sessionId" gorm:"not null;size:64;comment:编辑会话ID（区分同一用户的多个窗口）"`
+	AcquiredAt  time.Time `json:"acquiredAt" gorm:"type:datetime(3);comment:获取时间"`
+	HeartbeatAt time.Time `json:"heartbeatAt" gorm:"type:datetime(3);comment:最后心跳时间"`
+	ExpiresAt   time.Time `json:"expiresAt" gorm:"type:datetime(3);index;comment:过期时间"`
+
+	// 关联关系
+	Article Article `json:"-" gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE"`
+	User    User    `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ArticleEditLock) TableName() string {
+	return "article_edit_locks"
+}
+
+// IsExpired 检查编辑锁是否已过期
+func (l *ArticleEditLock) IsExpired(now time.Time) bool {
+	return !l.ExpiresAt.After(now)
+}
+
+// ArticlePreviewToken 文章预览链接令牌
+// 令牌本身经过签名并带有过期时间，数据库记录用于撤销和访问统计
+type ArticlePreviewToken struct {
+	ID           uint       `json:"id" gorm:"primaryKey;comment:预览令牌ID"`
+	ArticleID    uint       `json:"articleId" gorm:"not null;index;comment:文章ID"`
+	CreatedBy    uint       `json:"createdBy" gorm:"not null;index;comment:创建人ID"`
+	Note         string     `json:"note" gorm:"size:100;comment:备注（如分享对象）"`
+	ExpiresAt    time.Time  `json:"expiresAt" gorm:"type:datetime(3);index;comment:过期时间"`
+	RevokedAt    *time.Time `json:"revokedAt" gorm:"type:datetime(3);comment:撤销时间"`
+	AccessCount  uint       `json:"accessCount" gorm:"default:0;comment:访问次数"`
+	LastAccessAt *time.Time `json:"lastAccessAt" gorm:"type:datetime(3);comment:最后访问时间"`
+	CreatedAt    time.Time  `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+
+	// 关联关系
+	Article Article `json:"-" gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE"`
+	Creator *User   `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
+}
+
+// TableName 指定表名
+func (ArticlePreviewToken) TableName() string {
+	return "article_preview_tokens"
+}
+
+// IsActive 检查预览令牌是否仍然有效（未撤销且未过期）
+func (t *ArticlePreviewToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && t.ExpiresAt.After(now)
+}
+
+// ArticleSlugHistory 文章历史Slug
+// 文章修改Slug后保留旧Slug，外部链接仍可通过旧Slug找到文章
+type ArticleSlugHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey;comment:记录ID"`
+	ArticleID uint      `json:"articleId" gorm:"not null;index;comment:文章ID"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null;size:200;comment:历史Slug"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:datetime(3);comment:停用时间"`
+
+	// 关联关系
+	Article Article `json:"-" gorm:"foreignKey:ArticleID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (ArticleSlugHistory) TableName() string {
+	return "article_slug_histories"
+}
+
 // ArticleView 文章浏览统计模型
 type ArticleView struct {
 	ID        uint      `json:"id" gorm:"primaryKey;comment:浏览记录ID"`
-	ArticleID uint      `json:"articleId" gorm:"not null;comment:文章ID"`
+	ArticleID uint      `json:"articleId" gorm:"not null;index:idx_article_views_article_date,priority:1;comment:文章ID"`
 	UserID    *uint     `json:"userId" gorm:"index;comment:用户ID（注册用户）"`
 	VisitorID string    `json:"visitorId" gorm:"size:64;comment:访客标识（匿名用户）"`
 	IPAddress string    `json:"ipAddress" gorm:"size:45;index;comment:IP地址"`
 	UserAgent string    `json:"userAgent" gorm:"type:text;comment:用户代理"`
 	Referer   string    `json:"referer" gorm:"size:500;comment:来源页面"`
-	ViewDate  time.Time `json:"viewDate" gorm:"type:date;index;comment:浏览日期"`
+	ViewDate  time.Time `json:"viewDate" gorm:"type:date;index;index:idx_article_views_article_date,priority:2;comment:浏览日期"`
 	ViewCount uint      `json:"viewCount" gorm:"default:1;comment:当日浏览次数"`
 	CreatedAt time.Time `json:"createdAt" gorm:"type:datetime(3);comment:首次浏览时间"`
 	UpdatedAt time.Time `json:"updatedAt" gorm:"type:datetime(3);comment:最后浏览时间"`
@@ -149,8 +309,47 @@ const (
 	ArticleStatusPublished ArticleStatus = "published" // 已发布
 	ArticleStatusArchived  ArticleStatus = "archived"  // 已归档
 	ArticleStatusPrivate   ArticleStatus = "private"   // 私有
+	ArticleStatusScheduled ArticleStatus = "scheduled" // 定时发布（到达发布时间后自动发布）
+)
+
+// 定义文章可见范围枚举（仅对已发布文章生效）
+type Visibility string
+
+const (
+	VisibilityPublic   Visibility = "public"   // 公开
+	VisibilityPassword Visibility = "password" // 输入密码后可读正文
+	VisibilityMembers  Visibility = "members"  // 登录用户可读正文，游客只能看到摘要
+)
+
+// IsProtected 检查可见范围是否限制了正文访问
+func (v Visibility) IsProtected() bool {
+	return v == VisibilityPassword || v == VisibilityMembers
+}
+
+// 定义文章协作权限枚举
+type CollaboratorRole string
+
+const (
+	CollaboratorRoleViewer    CollaboratorRole = "viewer"    // 只读：可查看未发布的文章
+	CollaboratorRoleEditor    CollaboratorRole = "editor"    // 编辑：可查看和修改文章内容
+	CollaboratorRolePublisher CollaboratorRole = "publisher" // 发布：可编辑并发布/下线文章
 )
 
+// CanView 检查协作权限是否允许查看
+func (r CollaboratorRole) CanView() bool {
+	return r == CollaboratorRoleViewer || r == CollaboratorRoleEditor || r == CollaboratorRolePublisher
+}
+
+// CanEdit 检查协作权限是否允许编辑
+func (r CollaboratorRole) CanEdit() bool {
+	return r == CollaboratorRoleEditor || r == CollaboratorRolePublisher
+}
+
+// CanPublish 检查协作权限是否允许发布
+func (r CollaboratorRole) CanPublish() bool {
+	return r == CollaboratorRolePublisher
+}
+
 // IsPublished 检查文章是否已发布
 func (a *Article) IsPublished() bool {
 	return a.Status == ArticleStatusPublished && a.PublishedAt != nil
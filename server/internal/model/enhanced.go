@@ -45,24 +45,52 @@ func (OperationLog) TableName() string {
 
 // 操作类型常量
 const (
-	ActionLogin         = "login"          // 用户登录
-	ActionLogout        = "logout"         // 用户登出
-	ActionCreateUser    = "create_user"    // 创建用户
-	ActionUpdateUser    = "update_user"    // 更新用户
-	ActionDeleteUser    = "delete_user"    // 删除用户
-	ActionCreateRole    = "create_role"    // 分配角色
-	ActionCreateArticle = "create_article" // 创建文章
-	ActionUpdateArticle = "update_article" // 更新文章
-	ActionDeleteArticle = "delete_article" // 删除文章
-	ActionDeleteComment = "delete_comment" // 删除评论
-	ActionSystemConfig  = "system_config"  // 系统配置
+	ActionLogin              = "login"               // 用户登录
+	ActionLogout             = "logout"              // 用户登出
+	ActionCreateUser         = "create_user"         // 创建用户
+	ActionUpdateUser         = "update_user"         // 更新用户
+	ActionDeleteUser         = "delete_user"         // 删除用户
+	ActionCreateRole         = "create_role"         // 创建角色
+	ActionUpdateRole         = "update_role"         // 更新角色
+	ActionDeleteRole         = "delete_role"         // 删除角色
+	ActionCreateArticle      = "create_article"      // 创建文章
+	ActionUpdateArticle      = "update_article"      // 更新文章
+	ActionDeleteArticle      = "delete_article"      // 删除文章
+	ActionPublishArticle     = "publish_article"     // 发布文章
+	ActionUnpublishArticle   = "unpublish_article"   // 取消发布文章
+	ActionArchiveArticle     = "archive_article"     // 归档文章
+	ActionPrivateArticle     = "private_article"     // 设为私有文章
+	ActionInviteCollaborator = "invite_collaborator" // 邀请协作者
+	ActionRemoveCollaborator = "remove_collaborator" // 移除协作者
+	ActionDeleteComment      = "delete_comment"      // 删除评论
+	ActionCreateToken        = "create_token"        // 创建访问令牌
+	ActionRevokeToken        = "revoke_token"        // 撤销访问令牌
+	ActionRestoreRevision    = "restore_revision"    // 恢复文章修订
+	ActionRerenderArticles   = "rerender_articles"   // 重新渲染全部文章
+	ActionReindexArticles    = "reindex_articles"    // 重建文章搜索索引
+	ActionCreatePreview      = "create_preview"      // 创建文章预览链接
+	ActionRevokePreview      = "revoke_preview"      // 撤销文章预览链接
+	ActionPreviewArticle     = "preview_article"     // 通过预览链接访问文章
+	ActionCreateRedirect     = "create_redirect"     // 创建跳转规则
+	ActionUpdateRedirect     = "update_redirect"     // 更新跳转规则
+	ActionDeleteRedirect     = "delete_redirect"     // 删除跳转规则
+	ActionSystemConfig       = "system_config"       // 系统配置
+)
+
+// 审计资源类型常量
+const (
+	ResourceUser     = "user"     // 用户
+	ResourceRole     = "role"     // 角色
+	ResourceArticle  = "article"  // 文章
+	ResourceToken    = "token"    // 访问令牌
+	ResourceRedirect = "redirect" // 跳转规则
 )
 
 // ArticleLike 文章点赞模型
 type ArticleLike struct {
 	ID        uint      `json:"id" gorm:"primaryKey;comment:点赞ID"`
-	ArticleID uint      `json:"articleId" gorm:"not null;index;comment:文章ID"`
-	UserID    uint      `json:"userId" gorm:"not null;index;comment:用户ID"`
+	ArticleID uint      `json:"articleId" gorm:"not null;index;uniqueIndex:uk_article_likes_article_user,priority:1;comment:文章ID"`
+	UserID    uint      `json:"userId" gorm:"not null;index;uniqueIndex:uk_article_likes_article_user,priority:2;comment:用户ID"`
 	CreatedAt time.Time `json:"createdAt" gorm:"type:datetime(3);index;comment:点赞时间"`
 
 	// 关联关系
@@ -152,10 +180,11 @@ type SearchLog struct {
 	ID           uint      `json:"id" gorm:"primaryKey;comment:搜索记录ID"`
 	UserID       *uint     `json:"userId" gorm:"index;comment:搜索用户ID"`
 	Keyword      string    `json:"keyword" gorm:"not null;size:255;index;comment:搜索关键词"`
+	Normalized   string    `json:"normalized" gorm:"size:255;index:idx_search_logs_normalized_created,priority:1;comment:规范化关键词（小写、合并空白，用于统计）"`
 	ResultsCount int       `json:"resultsCount" gorm:"default:0;comment:搜索结果数量"`
 	IPAddress    *string   `json:"ipAddress" gorm:"size:45;index;comment:IP地址"`
 	UserAgent    *string   `json:"userAgent" gorm:"type:text;comment:用户代理"`
-	CreatedAt    time.Time `json:"createdAt" gorm:"type:datetime(3);index;comment:搜索时间"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"type:datetime(3);index;index:idx_search_logs_normalized_created,priority:2;comment:搜索时间"`
 
 	// 关联关系
 	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
@@ -167,13 +196,14 @@ func (SearchLog) TableName() string {
 }
 
 // ContentStats 内容统计模型（用于性能优化和热门推荐）
+// 同一内容、统计类型和日期只有一条记录，汇总任务重复执行时覆盖原值
 type ContentStats struct {
 	ID          uint      `json:"id" gorm:"primaryKey;comment:统计ID"`
-	ContentType string    `json:"contentType" gorm:"not null;size:50;index;comment:内容类型"`
-	ContentID   uint      `json:"contentId" gorm:"not null;index;comment:内容ID"`
-	StatType    string    `json:"statType" gorm:"not null;size:50;index;comment:统计类型"`
+	ContentType string    `json:"contentType" gorm:"not null;size:50;index;uniqueIndex:uk_content_stats,priority:1;comment:内容类型"`
+	ContentID   uint      `json:"contentId" gorm:"not null;index;uniqueIndex:uk_content_stats,priority:2;comment:内容ID"`
+	StatType    string    `json:"statType" gorm:"not null;size:50;index;uniqueIndex:uk_content_stats,priority:3;comment:统计类型"`
 	StatValue   uint      `json:"statValue" gorm:"default:0;index;comment:统计值"`
-	StatDate    time.Time `json:"statDate" gorm:"type:date;index;comment:统计日期"`
+	StatDate    time.Time `json:"statDate" gorm:"type:date;index;uniqueIndex:uk_content_stats,priority:4;comment:统计日期"`
 	CreatedAt   time.Time `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
 	UpdatedAt   time.Time `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
 }
@@ -193,6 +223,7 @@ const (
 	StatTypeWeeklyViews  = "weekly_views"  // 周浏览量
 	StatTypeMonthlyViews = "monthly_views" // 月浏览量
 	StatTypeLikesCount   = "likes_count"   // 点赞数
+	StatTypeDailyLikes   = "daily_likes"   // 日新增点赞数
 )
 
 // UserFollow 用户关注关系模型
@@ -13,6 +13,13 @@ func Models() []interface{} {
 		&User{},
 		&UserSession{},
 		&UserActivity{},
+		&UserIdentity{},
+		&OAuthState{},
+		&PersonalAccessToken{},
+
+		// 权限模块
+		&Role{},
+		&RolePermission{},
 
 		// 内容模块
 		&Category{},
@@ -20,6 +27,12 @@ func Models() []interface{} {
 		&Article{},
 		&ArticleTag{},
 		&ArticleView{},
+		&ArticleCollaborator{},
+		&ArticleRevision{},
+		&ArticleDraft{},
+		&ArticleEditLock{},
+		&ArticlePreviewToken{},
+		&ArticleSlugHistory{},
 
 		// 评论模块
 		&Comment{},
@@ -29,6 +42,7 @@ func Models() []interface{} {
 
 		// 系统设置模块
 		&Setting{},
+		&Redirect{},
 
 		// 增强功能模块
 		&ArticleCategory{},
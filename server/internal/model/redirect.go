@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Redirect 路径跳转规则
+// 站点改版或调整链接时，把旧路径永久跳转到新路径
+type Redirect struct {
+	ID         uint       `json:"id" gorm:"primaryKey;comment:规则ID"`
+	FromPath   string     `json:"fromPath" gorm:"uniqueIndex;not null;size:500;comment:源路径（以/开头，不含查询参数）"`
+	ToPath     string     `json:"toPath" gorm:"not null;size:1000;comment:目标路径或完整URL"`
+	StatusCode int        `json:"statusCode" gorm:"not null;default:301;comment:跳转状态码 301/302"`
+	Note       string     `json:"note" gorm:"size:200;comment:备注"`
+	HitCount   uint       `json:"hitCount" gorm:"default:0;comment:命中次数"`
+	LastHitAt  *time.Time `json:"lastHitAt" gorm:"type:datetime(3);comment:最后命中时间"`
+	CreatedBy  uint       `json:"createdBy" gorm:"not null;index;comment:创建人ID"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+	UpdatedAt  time.Time  `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Redirect) TableName() string {
+	return "redirects"
+}
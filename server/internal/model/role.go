@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Role 角色模型
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey;comment:角色ID"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null;size:20;comment:角色标识（与users.role对应）"`
+	DisplayName string    `json:"displayName" gorm:"not null;size:50;comment:角色显示名称"`
+	Description string    `json:"description" gorm:"size:255;comment:角色描述"`
+	Level       int       `json:"level" gorm:"not null;default:1;comment:角色层级（数字越大权限越高）"`
+	IsBuiltin   bool      `json:"isBuiltin" gorm:"default:false;comment:是否内置角色（不可删除）"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+
+	// 关联关系
+	Permissions []RolePermission `json:"permissions" gorm:"foreignKey:RoleID"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// RolePermission 角色权限关联模型
+type RolePermission struct {
+	ID         uint      `json:"id" gorm:"primaryKey;comment:关联ID"`
+	RoleID     uint      `json:"roleId" gorm:"not null;uniqueIndex:idx_role_permission;comment:角色ID"`
+	Permission string    `json:"permission" gorm:"not null;size:50;uniqueIndex:idx_role_permission;comment:权限标识"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+
+	// 关联关系
+	Role Role `json:"-" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
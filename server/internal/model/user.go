@@ -29,18 +29,20 @@ type User struct {
 	DeletedAt       gorm.DeletedAt    `json:"-" gorm:"index;comment:软删除时间"`
 
 	// 关联关系
-	Articles         []Article         `json:"-" gorm:"foreignKey:AuthorID"`
-	Comments         []Comment         `json:"-" gorm:"foreignKey:UserID"`
-	MediaFiles       []MediaFile       `json:"-" gorm:"foreignKey:UploaderID"`
-	Sessions         []UserSession     `json:"-" gorm:"foreignKey:UserID"`
-	Activities       []UserActivity    `json:"-" gorm:"foreignKey:UserID"`
-	ArticleLikes     []ArticleLike     `json:"-" gorm:"foreignKey:UserID"`
-	CommentLikes     []CommentLike     `json:"-" gorm:"foreignKey:UserID"`
-	ArticleBookmarks []ArticleBookmark `json:"-" gorm:"foreignKey:UserID"`
-	Notifications    []Notification    `json:"-" gorm:"foreignKey:UserID"`
-	SearchLogs       []SearchLog       `json:"-" gorm:"foreignKey:UserID"`
-	Followers        []UserFollow      `json:"-" gorm:"foreignKey:FollowingID"`
-	Following        []UserFollow      `json:"-" gorm:"foreignKey:FollowerID"`
+	Articles         []Article             `json:"-" gorm:"foreignKey:AuthorID"`
+	Comments         []Comment             `json:"-" gorm:"foreignKey:UserID"`
+	MediaFiles       []MediaFile           `json:"-" gorm:"foreignKey:UploaderID"`
+	Sessions         []UserSession         `json:"-" gorm:"foreignKey:UserID"`
+	Activities       []UserActivity        `json:"-" gorm:"foreignKey:UserID"`
+	ArticleLikes     []ArticleLike         `json:"-" gorm:"foreignKey:UserID"`
+	CommentLikes     []CommentLike         `json:"-" gorm:"foreignKey:UserID"`
+	ArticleBookmarks []ArticleBookmark     `json:"-" gorm:"foreignKey:UserID"`
+	Notifications    []Notification        `json:"-" gorm:"foreignKey:UserID"`
+	SearchLogs       []SearchLog           `json:"-" gorm:"foreignKey:UserID"`
+	Followers        []UserFollow          `json:"-" gorm:"foreignKey:FollowingID"`
+	Following        []UserFollow          `json:"-" gorm:"foreignKey:FollowerID"`
+	Identities       []UserIdentity        `json:"-" gorm:"foreignKey:UserID"`
+	AccessTokens     []PersonalAccessToken `json:"-" gorm:"foreignKey:UserID"`
 }
 
 // TableName 指定表名
@@ -94,6 +96,70 @@ func (UserActivity) TableName() string {
 	return "user_activities"
 }
 
+// UserIdentity 第三方身份绑定模型
+type UserIdentity struct {
+	ID            uint       `json:"id" gorm:"primaryKey;comment:身份ID"`
+	UserID        uint       `json:"userId" gorm:"not null;index;comment:用户ID"`
+	Provider      string     `json:"provider" gorm:"not null;size:50;uniqueIndex:idx_identity_provider_subject;comment:身份提供方"`
+	Subject       string     `json:"subject" gorm:"not null;size:255;uniqueIndex:idx_identity_provider_subject;comment:提供方用户唯一标识"`
+	Email         string     `json:"email" gorm:"size:100;index;comment:提供方邮箱"`
+	EmailVerified bool       `json:"emailVerified" gorm:"default:false;comment:提供方邮箱是否已验证"`
+	Username      string     `json:"username" gorm:"size:100;comment:提供方用户名"`
+	AvatarURL     string     `json:"avatarUrl" gorm:"size:500;comment:提供方头像URL"`
+	RawProfile    string     `json:"-" gorm:"type:json;comment:提供方原始用户信息"`
+	LastLoginAt   *time.Time `json:"lastLoginAt" gorm:"type:datetime(3);comment:最后通过该身份登录时间"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"type:datetime(3);comment:绑定时间"`
+	UpdatedAt     time.Time  `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+
+	// 关联关系
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OAuthState 第三方登录授权状态，回调时一次性消费
+type OAuthState struct {
+	ID           uint      `json:"id" gorm:"primaryKey;comment:状态ID"`
+	StateHash    string    `json:"-" gorm:"uniqueIndex;not null;size:64;comment:state的SHA-256哈希值"`
+	Provider     string    `json:"provider" gorm:"not null;size:50;comment:身份提供方"`
+	CodeVerifier string    `json:"-" gorm:"not null;size:128;comment:PKCE校验码"`
+	LinkUserID   uint      `json:"linkUserId" gorm:"default:0;comment:绑定身份的用户ID，0表示登录"`
+	ExpiresAt    time.Time `json:"expiresAt" gorm:"type:datetime(3);index;comment:过期时间"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+}
+
+// TableName 指定表名
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
+
+// PersonalAccessToken 个人访问令牌模型
+type PersonalAccessToken struct {
+	ID          uint       `json:"id" gorm:"primaryKey;comment:令牌ID"`
+	UserID      uint       `json:"userId" gorm:"not null;index;comment:所属用户ID"`
+	Name        string     `json:"name" gorm:"not null;size:100;comment:令牌名称"`
+	TokenHash   string     `json:"-" gorm:"uniqueIndex;not null;size:64;comment:令牌SHA-256哈希值"`
+	TokenPrefix string     `json:"tokenPrefix" gorm:"size:16;comment:令牌前缀（用于识别）"`
+	Scopes      string     `json:"-" gorm:"type:json;comment:授权范围（权限列表）"`
+	ExpiresAt   *time.Time `json:"expiresAt" gorm:"type:datetime(3);index;comment:过期时间"`
+	LastUsedAt  *time.Time `json:"lastUsedAt" gorm:"type:datetime(3);comment:最后使用时间"`
+	LastUsedIP  string     `json:"lastUsedIp" gorm:"size:45;comment:最后使用IP"`
+	RevokedAt   *time.Time `json:"revokedAt" gorm:"type:datetime(3);comment:撤销时间"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"type:datetime(3);comment:创建时间"`
+	UpdatedAt   time.Time  `json:"updatedAt" gorm:"type:datetime(3);comment:更新时间"`
+
+	// 关联关系
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
 // 定义用户角色常量
 type UserRole string
 
@@ -7,17 +7,36 @@ import (
 	"time"
 
 	"MyBlog/internal/model"
+	"MyBlog/pkg/search"
+	"MyBlog/pkg/slug"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// maxSlugSuffix Slug 重复时最多尝试的数字后缀，超过后改用短哈希
+const maxSlugSuffix = 10
+
+// ErrArticleVersionConflict 文章已被其他请求修改，提交的版本号已过期
+var ErrArticleVersionConflict = errors.New("文章已被其他人修改")
+
+// articleFulltextIndexes mysql 搜索后端使用的全文索引（ngram 分词）
+// InnoDB 每条 ALTER 只能新增一个全文索引，逐个创建
+var articleFulltextIndexes = []struct{ name, column string }{
+	{name: "idx_articles_ft_title", column: "title"},
+	{name: "idx_articles_ft_summary", column: "summary"},
+	{name: "idx_articles_ft_content", column: "content"},
+}
+
 // ArticleRepositoryInterface 文章仓储接口
 type ArticleRepositoryInterface interface {
 	// 基础CRUD操作
 	Create(article *model.Article) error
 	GetByID(id uint) (*model.Article, error)
 	GetBySlug(slug string) (*model.Article, error)
+	GetByHistorySlug(slug string) (*model.Article, error)
 	Update(article *model.Article) error
+	GetVersion(id uint) (uint, error)
 	Delete(id uint) error
 
 	// 查询操作
@@ -26,11 +45,15 @@ type ArticleRepositoryInterface interface {
 	GetByCategory(categoryID uint, params *ArticleListParams) ([]*model.Article, int64, error)
 	GetByTag(tagID uint, params *ArticleListParams) ([]*model.Article, int64, error)
 	Search(keyword string, params *ArticleListParams) ([]*model.Article, int64, error)
+	ListByIDs(ids []uint, params *ArticleListParams) ([]*model.Article, int64, error)
 
 	// 统计操作
 	GetPopular(limit int) ([]*model.Article, error)
 	GetRecent(limit int) ([]*model.Article, error)
 	IncrementViewCount(id uint) error
+	RecordView(view *model.ArticleView) error
+	AddLike(articleID, userID uint) (bool, error)
+	RemoveLike(articleID, userID uint) (bool, error)
 	UpdateLikeCount(id uint) error
 	UpdateCommentCount(id uint) error
 
@@ -47,17 +70,93 @@ type ArticleRepositoryInterface interface {
 	Unpublish(id uint) error
 	Archive(id uint) error
 	SetPrivate(id uint) error
+
+	// 定时发布
+	ListDueScheduled(now time.Time, limit int) ([]*model.Article, error)
+	PublishScheduled(article *model.Article, now time.Time, notification *model.Notification) (bool, error)
+	ArchiveExpired(now time.Time) ([]uint, error)
+
+	// 内容渲染
+	ListForRender(afterID uint, limit int) ([]*model.Article, error)
+	UpdateRendered(article *model.Article, restoreSource bool) error
+
+	// 搜索索引
+	ListForIndex(afterID uint, limit int) ([]*model.Article, error)
+	EnsureFulltextIndexes() error
+
+	// 相关文章
+	ListForRelated(afterID uint, limit int) ([]*model.Article, error)
+
+	// 搜索建议
+	SuggestTitles(prefix string, limit int) ([]*model.Article, error)
+
+	// 分面统计
+	Facets(params *ArticleListParams, limit int) (*ArticleFacets, error)
+
+	// 归档统计（仅已发布文章，按发布时间）
+	ArchiveMonths() ([]*MonthCount, error)
+	ArchiveDays(from, to time.Time) ([]*DayCount, error)
+	PublishedIDsBetween(from, to time.Time) ([]uint, error)
 }
 
 // ArticleListParams 文章列表查询参数
 type ArticleListParams struct {
-	Page     int                 `json:"page"`
-	PageSize int                 `json:"pageSize"`
-	Status   model.ArticleStatus `json:"status"`
-	AuthorID uint                `json:"authorId"`
-	SortBy   string              `json:"sortBy"` // created_at, updated_at, published_at, view_count
-	Order    string              `json:"order"`  // asc, desc
-	Search   string              `json:"search"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"pageSize"`
+	Status     model.ArticleStatus `json:"status"`
+	AuthorID   uint                `json:"authorId"`
+	CategoryID uint                `json:"categoryId"` // 主分类或附加分类
+	TagID      uint                `json:"tagId"`
+	SortBy     string              `json:"sortBy"` // created_at, updated_at, published_at, view_count
+	Order      string              `json:"order"`  // asc, desc
+	Search     string              `json:"search"`
+	// CategoryIDs 多分类筛选（主分类或附加分类），CategoryMatchAll 为 true 时须属于全部分类，否则属于任一分类
+	CategoryIDs      []uint `json:"categoryIds"`
+	CategoryMatchAll bool   `json:"categoryMatchAll"`
+	// TagIDs 多标签筛选，TagMatchAll 为 true 时须包含全部标签，否则包含任一标签
+	TagIDs         []uint     `json:"tagIds"`
+	TagMatchAll    bool       `json:"tagMatchAll"`
+	PublishedFrom  *time.Time `json:"publishedFrom"` // 发布时间下限（含）
+	PublishedTo    *time.Time `json:"publishedTo"`   // 发布时间上限（含）
+	IsFeatured     *bool      `json:"isFeatured"`
+	IsTop          *bool      `json:"isTop"`
+	MinWordCount   uint       `json:"minWordCount"` // 为 0 时不限
+	MaxWordCount   uint       `json:"maxWordCount"`
+	MinReadingTime uint       `json:"minReadingTime"` // 分钟，为 0 时不限
+	MaxReadingTime uint       `json:"maxReadingTime"`
+	// Cursor 游标分页位置，设置时忽略 Page，按 (排序字段, ID) 定位当前页（仅列表查询，搜索不支持）
+	Cursor *ArticleCursor `json:"-"`
+	// Member 访问者是否为登录用户：登录用户可以按正文搜索到会员可见文章
+	Member bool `json:"-"`
+	// Weights 全文搜索各字段的相关度权重
+	Weights SearchWeights `json:"-"`
+}
+
+// SearchWeights 全文搜索相关度权重，为 0 的字段使用默认权重
+type SearchWeights struct {
+	Title   float64
+	Summary float64
+	Content float64
+}
+
+// WithDefaults 补全未设置的权重：标题 5、摘要 3、正文 1
+func (w SearchWeights) WithDefaults() SearchWeights {
+	if w.Title <= 0 {
+		w.Title = 5
+	}
+	if w.Summary <= 0 {
+		w.Summary = 3
+	}
+	if w.Content <= 0 {
+		w.Content = 1
+	}
+	return w
+}
+
+// searchHit 全文搜索命中的文章ID和相关度
+type searchHit struct {
+	ID        uint
+	Relevance float64
 }
 
 // ArticleRepository 文章仓储实现
@@ -73,7 +172,7 @@ func NewArticleRepository(db *gorm.DB) ArticleRepositoryInterface {
 // Create 创建文章
 func (r *ArticleRepository) Create(article *model.Article) error {
 	if article.Slug == "" {
-		article.Slug = generateSlug(article.Title)
+		article.Slug = slug.Make(article.Title, slug.Options{})
 	}
 
 	// 确保slug唯一
@@ -87,6 +186,11 @@ func (r *ArticleRepository) Create(article *model.Article) error {
 		article.PublishedAt = &now
 	}
 
+	// 初始版本号
+	if article.Version == 0 {
+		article.Version = 1
+	}
+
 	return r.db.Create(article).Error
 }
 
@@ -129,7 +233,21 @@ func (r *ArticleRepository) GetBySlug(slug string) (*model.Article, error) {
 	return &article, nil
 }
 
+// GetByHistorySlug 根据历史Slug获取文章
+func (r *ArticleRepository) GetByHistorySlug(slug string) (*model.Article, error) {
+	var history model.ArticleSlugHistory
+	if err := r.db.Where("slug = ?", slug).First(&history).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("文章不存在")
+		}
+		return nil, fmt.Errorf("查询历史Slug失败: %w", err)
+	}
+	return r.GetByID(history.ArticleID)
+}
+
 // Update 更新文章
+// 以 article.Version 作为期望版本条件更新，版本不一致时返回 ErrArticleVersionConflict，成功后版本号加一
+// Slug 发生变化时在同一事务中记录旧Slug
 func (r *ArticleRepository) Update(article *model.Article) error {
 	// 检查slug唯一性
 	if err := r.ensureUniqueSlug(article); err != nil {
@@ -142,7 +260,65 @@ func (r *ArticleRepository) Update(article *model.Article) error {
 		article.PublishedAt = &now
 	}
 
-	return r.db.Save(article).Error
+	expected := article.Version
+	article.Version = expected + 1
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var oldSlug string
+		if err := tx.Model(&model.Article{}).Where("id = ?", article.ID).Select("slug").Scan(&oldSlug).Error; err != nil {
+			return fmt.Errorf("查询文章Slug失败: %w", err)
+		}
+
+		result := tx.Model(article).
+			Select("*").
+			Omit(clause.Associations, "created_at").
+			Where("version = ?", expected).
+			Updates(article)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrArticleVersionConflict
+		}
+
+		if oldSlug != "" && oldSlug != article.Slug {
+			return r.recordSlugChange(tx, article.ID, oldSlug, article.Slug)
+		}
+		return nil
+	})
+	if err != nil {
+		article.Version = expected
+		return err
+	}
+	return nil
+}
+
+// recordSlugChange 记录旧Slug；改回曾用过的Slug时删除对应的历史记录
+func (r *ArticleRepository) recordSlugChange(tx *gorm.DB, articleID uint, oldSlug, newSlug string) error {
+	if err := tx.Where("slug = ?", newSlug).Delete(&model.ArticleSlugHistory{}).Error; err != nil {
+		return fmt.Errorf("清理历史Slug失败: %w", err)
+	}
+
+	history := &model.ArticleSlugHistory{ArticleID: articleID, Slug: oldSlug}
+	err := tx.Omit("Article").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slug"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"article_id": articleID, "created_at": time.Now()}),
+	}).Create(history).Error
+	if err != nil {
+		return fmt.Errorf("记录历史Slug失败: %w", err)
+	}
+	return nil
+}
+
+// GetVersion 获取文章当前版本号
+func (r *ArticleRepository) GetVersion(id uint) (uint, error) {
+	var article model.Article
+	if err := r.db.Select("id", "version").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errors.New("文章不存在")
+		}
+		return 0, fmt.Errorf("查询文章版本失败: %w", err)
+	}
+	return article.Version, nil
 }
 
 // Delete 删除文章（软删除）
@@ -167,11 +343,8 @@ func (r *ArticleRepository) List(params *ArticleListParams) ([]*model.Article, i
 	}
 
 	// 应用分页和排序
-	query = r.applyPagination(query, params)
-	query = r.applySorting(query, params)
-
-	var articles []*model.Article
-	if err := query.Find(&articles).Error; err != nil {
+	articles, err := r.findPage(query, params)
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -200,11 +373,8 @@ func (r *ArticleRepository) GetByCategory(categoryID uint, params *ArticleListPa
 		return nil, 0, err
 	}
 
-	query = r.applyPagination(query, params)
-	query = r.applySorting(query, params)
-
-	var articles []*model.Article
-	if err := query.Find(&articles).Error; err != nil {
+	articles, err := r.findPage(query, params)
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -228,11 +398,8 @@ func (r *ArticleRepository) GetByTag(tagID uint, params *ArticleListParams) ([]*
 		return nil, 0, err
 	}
 
-	query = r.applyPagination(query, params)
-	query = r.applySorting(query, params)
-
-	var articles []*model.Article
-	if err := query.Find(&articles).Error; err != nil {
+	articles, err := r.findPage(query, params)
+	if err != nil {
 		return nil, 0, err
 	}
 
@@ -240,17 +407,103 @@ func (r *ArticleRepository) GetByTag(tagID uint, params *ArticleListParams) ([]*
 }
 
 // Search 全文搜索文章
+// 关键词支持 "短语"、前缀*、-排除，全文索引不可用于该关键词时退回模糊匹配
 func (r *ArticleRepository) Search(keyword string, params *ArticleListParams) ([]*model.Article, int64, error) {
 	if keyword == "" {
 		return r.List(params)
 	}
 
-	searchTerm := "%" + keyword + "%"
+	// 全文索引无法匹配过短的词（ngram 分词最小长度为 2），退回模糊匹配
+	query := search.ParseQuery(keyword)
+	if !query.FulltextReady() {
+		return r.searchLike(keyword, params)
+	}
+	return r.searchFulltext(query.BooleanMode(), params)
+}
+
+// searchFulltext 使用 FULLTEXT 索引（ngram 分词）按布尔模式搜索，结果按加权相关度排序
+// 指定排序字段时以该字段为主、相关度为次
+func (r *ArticleRepository) searchFulltext(against string, params *ArticleListParams) ([]*model.Article, int64, error) {
+	contentVisibility := searchContentVisibility(params.Member)
+	weights := params.Weights.WithDefaults()
+
+	// 与 applySearch 相同的可见范围规则：密码文章只匹配标题，正文仅匹配可阅读的文章
+	matched := func() *gorm.DB {
+		query := r.db.Model(&model.Article{}).Where(
+			"MATCH(title) AGAINST(? IN BOOLEAN MODE) OR "+
+				"(visibility <> ? AND MATCH(summary) AGAINST(? IN BOOLEAN MODE)) OR "+
+				"(visibility IN ? AND MATCH(content) AGAINST(? IN BOOLEAN MODE))",
+			against, model.VisibilityPassword, against, contentVisibility, against,
+		)
+		return r.applyFilters(query, params)
+	}
+
+	var total int64
+	if err := matched().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*model.Article{}, 0, nil
+	}
+
+	query := matched().Select(
+		"id, (? * MATCH(title) AGAINST(? IN BOOLEAN MODE)"+
+			" + ? * IF(visibility <> ?, MATCH(summary) AGAINST(? IN BOOLEAN MODE), 0)"+
+			" + ? * IF(visibility IN ?, MATCH(content) AGAINST(? IN BOOLEAN MODE), 0)) AS relevance",
+		weights.Title, against,
+		weights.Summary, model.VisibilityPassword, against,
+		weights.Content, contentVisibility, against,
+	)
+	if params.SortBy != "" {
+		query = r.applySorting(query, params)
+	}
+	query = query.Order("relevance DESC").Order("id DESC")
+	query = r.applyPagination(query, params)
+
+	var hits []searchHit
+	if err := query.Scan(&hits).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(hits) == 0 {
+		return []*model.Article{}, total, nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var found []*model.Article
+	err := r.db.Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Where("id IN ?", ids).
+		Find(&found).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 按相关度顺序返回
+	byID := make(map[uint]*model.Article, len(found))
+	for _, article := range found {
+		byID[article.ID] = article
+	}
+	articles := make([]*model.Article, 0, len(found))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, article)
+		}
+	}
+
+	return articles, total, nil
+}
+
+// searchLike 使用 LIKE 模糊匹配搜索
+func (r *ArticleRepository) searchLike(keyword string, params *ArticleListParams) ([]*model.Article, int64, error) {
 	query := r.db.Model(&model.Article{}).
 		Preload("Author").
 		Preload("Category").
-		Preload("Tags").
-		Where("title LIKE ? OR content LIKE ? OR summary LIKE ?", searchTerm, searchTerm, searchTerm)
+		Preload("Tags")
+	query = r.applySearch(query, keyword, params.Member)
 
 	// 应用其他筛选条件
 	query = r.applyFilters(query, params)
@@ -260,8 +513,42 @@ func (r *ArticleRepository) Search(keyword string, params *ArticleListParams) ([
 		return nil, 0, err
 	}
 
+	articles, err := r.findPage(query, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// ListByIDs 在给定的文章ID范围内按筛选条件分页查询
+// 未指定排序字段时按 ids 的顺序返回（由搜索后端按相关度排好序），指定时以 ids 顺序作为次要排序
+func (r *ArticleRepository) ListByIDs(ids []uint, params *ArticleListParams) ([]*model.Article, int64, error) {
+	if len(ids) == 0 {
+		return []*model.Article{}, 0, nil
+	}
+
+	query := r.db.Model(&model.Article{}).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Where("id IN ?", ids)
+	query = r.applyFilters(query, params)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	query = r.applyPagination(query, params)
-	query = r.applySorting(query, params)
+	order := "FIELD(id, ?)"
+	if params.SortBy != "" {
+		if params.Order == "" {
+			params.Order = "desc"
+		}
+		order = params.SortBy + " " + strings.ToUpper(params.Order) + ", " + order
+	}
+	query = query.Clauses(clause.OrderBy{Expression: clause.Expr{SQL: order, Vars: []interface{}{ids}, WithoutParentheses: true}})
 
 	var articles []*model.Article
 	if err := query.Find(&articles).Error; err != nil {
@@ -308,6 +595,53 @@ func (r *ArticleRepository) IncrementViewCount(id uint) error {
 		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
 }
 
+// RecordView 记录一次浏览：同一文章同一天内同一用户（游客按访客标识，没有访客标识时按IP）只保留一条记录，重复浏览累加次数
+func (r *ArticleRepository) RecordView(view *model.ArticleView) error {
+	query := r.db.Model(&model.ArticleView{}).Where("article_id = ? AND view_date = ?", view.ArticleID, view.ViewDate)
+	switch {
+	case view.UserID != nil:
+		query = query.Where("user_id = ?", *view.UserID)
+	case view.VisitorID != "":
+		query = query.Where("user_id IS NULL AND visitor_id = ?", view.VisitorID)
+	default:
+		query = query.Where("user_id IS NULL AND visitor_id = '' AND ip_address = ?", view.IPAddress)
+	}
+
+	result := query.UpdateColumns(map[string]interface{}{
+		"view_count": gorm.Expr("view_count + 1"),
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("更新浏览记录失败: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	if err := r.db.Create(view).Error; err != nil {
+		return fmt.Errorf("写入浏览记录失败: %w", err)
+	}
+	return nil
+}
+
+// AddLike 添加点赞记录，已点赞时不重复添加，返回是否新增
+func (r *ArticleRepository) AddLike(articleID, userID uint) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ArticleLike{ArticleID: articleID, UserID: userID})
+	if result.Error != nil {
+		return false, fmt.Errorf("点赞失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RemoveLike 删除点赞记录，返回是否删除
+func (r *ArticleRepository) RemoveLike(articleID, userID uint) (bool, error) {
+	result := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&model.ArticleLike{})
+	if result.Error != nil {
+		return false, fmt.Errorf("取消点赞失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // UpdateLikeCount 更新点赞数
 func (r *ArticleRepository) UpdateLikeCount(id uint) error {
 	return r.db.Model(&model.Article{}).
@@ -399,13 +733,15 @@ func (r *ArticleRepository) SyncCategories(articleID uint, categoryIDs []uint) e
 }
 
 // Publish 发布文章
+// 已有发布时间的保留原时间（重新上线不改变发布日期），未设置或为未来计划时间的改为当前时间
 func (r *ArticleRepository) Publish(id uint) error {
 	now := time.Now()
 	return r.db.Model(&model.Article{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"status":       model.ArticleStatusPublished,
-			"published_at": &now,
+			"published_at": gorm.Expr("CASE WHEN published_at IS NULL OR published_at > ? THEN ? ELSE published_at END", now, now),
+			"version":      gorm.Expr("version + 1"),
 		}).Error
 }
 
@@ -413,21 +749,345 @@ func (r *ArticleRepository) Publish(id uint) error {
 func (r *ArticleRepository) Unpublish(id uint) error {
 	return r.db.Model(&model.Article{}).
 		Where("id = ?", id).
-		Update("status", model.ArticleStatusDraft).Error
+		Updates(map[string]interface{}{
+			"status":  model.ArticleStatusDraft,
+			"version": gorm.Expr("version + 1"),
+		}).Error
 }
 
 // Archive 归档文章
 func (r *ArticleRepository) Archive(id uint) error {
 	return r.db.Model(&model.Article{}).
 		Where("id = ?", id).
-		Update("status", model.ArticleStatusArchived).Error
+		Updates(map[string]interface{}{
+			"status":  model.ArticleStatusArchived,
+			"version": gorm.Expr("version + 1"),
+		}).Error
 }
 
 // SetPrivate 设置为私有文章
 func (r *ArticleRepository) SetPrivate(id uint) error {
 	return r.db.Model(&model.Article{}).
 		Where("id = ?", id).
-		Update("status", model.ArticleStatusPrivate).Error
+		Updates(map[string]interface{}{
+			"status":  model.ArticleStatusPrivate,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+}
+
+// ListDueScheduled 获取已到计划发布时间的定时发布文章
+func (r *ArticleRepository) ListDueScheduled(now time.Time, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	err := r.db.Select("id", "title", "slug", "summary", "author_id", "published_at").
+		Where("status = ? AND published_at <= ?", model.ArticleStatusScheduled, now).
+		Order("published_at ASC, id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询待发布文章失败: %w", err)
+	}
+	return articles, nil
+}
+
+// PublishScheduled 发布一篇到期的定时文章，并在同一事务中给作者的关注者写入新文章通知
+// 以“状态仍为定时发布”作为条件更新，多个实例同时处理同一篇文章时只有一个会成功，返回是否由本次调用发布
+func (r *ArticleRepository) PublishScheduled(article *model.Article, now time.Time, notification *model.Notification) (bool, error) {
+	published := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Article{}).
+			Where("id = ? AND status = ? AND published_at <= ?", article.ID, model.ArticleStatusScheduled, now).
+			UpdateColumns(map[string]interface{}{
+				"status":     model.ArticleStatusPublished,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("发布定时文章失败: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		published = true
+
+		if notification == nil {
+			return nil
+		}
+		err := tx.Exec(`INSERT INTO notifications (user_id, type, title, content, related_type, related_id, is_read, created_at, updated_at)
+			SELECT follower_id, ?, ?, ?, ?, ?, false, ?, ? FROM user_follows WHERE following_id = ?`,
+			notification.Type, notification.Title, notification.Content, notification.RelatedType, notification.RelatedID,
+			now, now, article.AuthorID).Error
+		if err != nil {
+			return fmt.Errorf("写入新文章通知失败: %w", err)
+		}
+		return nil
+	})
+	return published, err
+}
+
+// ArchiveExpired 将已过下线时间的已发布文章改为归档状态，返回被归档的文章ID
+func (r *ArticleRepository) ArchiveExpired(now time.Time) ([]uint, error) {
+	const cond = "status = ? AND expires_at IS NOT NULL AND expires_at <= ?"
+
+	var ids []uint
+	if err := r.db.Model(&model.Article{}).Where(cond, model.ArticleStatusPublished, now).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("查询到期文章失败: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// 条件中保留状态判断，其他实例已归档的文章不会被重复更新
+	err := r.db.Model(&model.Article{}).
+		Where("id IN ?", ids).
+		Where(cond, model.ArticleStatusPublished, now).
+		UpdateColumns(map[string]interface{}{
+			"status":     model.ArticleStatusArchived,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("归档到期文章失败: %w", err)
+	}
+	return ids, nil
+}
+
+// ListForRender 按ID顺序分批获取需要渲染的文章内容字段
+func (r *ArticleRepository) ListForRender(afterID uint, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	err := r.db.Select("id", "content", "summary", "render_version", "version").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询待渲染文章失败: %w", err)
+	}
+	return articles, nil
+}
+
+// ListForIndex 按ID顺序分批获取文章及其分类和标签，用于重建搜索索引
+func (r *ArticleRepository) ListForIndex(afterID uint, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	err := r.db.Preload("Categories").
+		Preload("Tags").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询待索引文章失败: %w", err)
+	}
+	return articles, nil
+}
+
+// EnsureFulltextIndexes 创建缺少的全文索引，已存在的跳过
+// 只在选用 mysql 搜索后端时调用，不允许全文索引的环境选用 embedded 后端即可不创建
+func (r *ArticleRepository) EnsureFulltextIndexes() error {
+	migrator := r.db.Migrator()
+	for _, idx := range articleFulltextIndexes {
+		if migrator.HasIndex(&model.Article{}, idx.name) {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE articles ADD FULLTEXT INDEX %s (%s) WITH PARSER ngram", idx.name, idx.column)
+		if err := r.db.Exec(stmt).Error; err != nil {
+			// 多个实例同时启动时，索引可能已由其他实例创建
+			if migrator.HasIndex(&model.Article{}, idx.name) {
+				continue
+			}
+			return fmt.Errorf("创建全文索引 %s 失败: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// ListForRelated 按ID顺序分批获取已发布文章的标题、摘要、分类和标签ID，用于计算相关文章
+func (r *ArticleRepository) ListForRelated(afterID uint, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	err := r.db.Select("id", "title", "summary", "category_id").
+		Preload("Categories", func(db *gorm.DB) *gorm.DB { return db.Select("id") }).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Select("id") }).
+		Where("status = ? AND id > ?", model.ArticleStatusPublished, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询相关文章计算数据失败: %w", err)
+	}
+	return articles, nil
+}
+
+// SuggestTitles 获取标题以指定前缀开头的已发布文章（只含ID、标题和Slug），按浏览量降序
+func (r *ArticleRepository) SuggestTitles(prefix string, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	err := r.db.Select("id", "title", "slug").
+		Where("status = ? AND title LIKE ?", model.ArticleStatusPublished, escapeLike(prefix)+"%").
+		Order("view_count DESC, id DESC").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询标题建议失败: %w", err)
+	}
+	return articles, nil
+}
+
+// FacetCount 分面统计项
+type FacetCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Slug  string `json:"slug"`
+	Count int64  `json:"count"`
+}
+
+// MonthCount 按发布月份的分面统计项
+type MonthCount struct {
+	Month string `json:"month"` // YYYY-MM
+	Count int64  `json:"count"`
+}
+
+// ArticleFacets 文章列表分面统计
+type ArticleFacets struct {
+	Categories []*FacetCount `json:"categories"`
+	Tags       []*FacetCount `json:"tags"`
+	Authors    []*FacetCount `json:"authors"` // name 为昵称（未设置时为用户名），slug 为用户名
+	Months     []*MonthCount `json:"months"`
+}
+
+// Facets 统计符合筛选条件的文章在各分类、标签、作者和发布月份下的数量
+// 每个维度统计时忽略该维度自身的筛选条件，已选中一个分类时仍能看到切换到其他分类后的数量；
+// 分类、标签、作者按数量降序最多返回 limit 项，月份按时间倒序全部返回
+func (r *ArticleRepository) Facets(params *ArticleListParams, limit int) (*ArticleFacets, error) {
+	matching := func(clear func(p *ArticleListParams)) *gorm.DB {
+		p := *params
+		clear(&p)
+		return r.applyFilters(r.db.Model(&model.Article{}), &p).Select("articles.id")
+	}
+
+	facets := &ArticleFacets{}
+
+	byCategory := matching(func(p *ArticleListParams) { p.CategoryID, p.CategoryIDs = 0, nil })
+	err := r.db.Raw(`SELECT categories.id, categories.name, categories.slug, COUNT(DISTINCT t.article_id) AS count
+		FROM (
+			SELECT id AS article_id, category_id FROM articles WHERE category_id IS NOT NULL AND id IN (?)
+			UNION
+			SELECT article_id, category_id FROM article_categories WHERE article_id IN (?)
+		) AS t
+		JOIN categories ON categories.id = t.category_id AND categories.deleted_at IS NULL
+		GROUP BY categories.id, categories.name, categories.slug
+		ORDER BY count DESC, categories.id
+		LIMIT ?`, byCategory, byCategory, limit).Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计分类分面失败: %w", err)
+	}
+
+	byTag := matching(func(p *ArticleListParams) { p.TagID, p.TagIDs = 0, nil })
+	err = r.db.Raw(`SELECT tags.id, tags.name, tags.slug, COUNT(*) AS count
+		FROM article_tags
+		JOIN tags ON tags.id = article_tags.tag_id
+		WHERE article_tags.article_id IN (?)
+		GROUP BY tags.id, tags.name, tags.slug
+		ORDER BY count DESC, tags.id
+		LIMIT ?`, byTag, limit).Scan(&facets.Tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计标签分面失败: %w", err)
+	}
+
+	byAuthor := matching(func(p *ArticleListParams) { p.AuthorID = 0 })
+	err = r.db.Raw(`SELECT users.id, COALESCE(NULLIF(users.nickname, ''), users.username) AS name, users.username AS slug, COUNT(*) AS count
+		FROM articles
+		JOIN users ON users.id = articles.author_id
+		WHERE articles.id IN (?)
+		GROUP BY users.id, users.nickname, users.username
+		ORDER BY count DESC, users.id
+		LIMIT ?`, byAuthor, limit).Scan(&facets.Authors).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计作者分面失败: %w", err)
+	}
+
+	byMonth := matching(func(p *ArticleListParams) { p.PublishedFrom, p.PublishedTo = nil, nil })
+	err = r.db.Raw(`SELECT DATE_FORMAT(published_at, '%Y-%m') AS month, COUNT(*) AS count
+		FROM articles
+		WHERE published_at IS NOT NULL AND id IN (?)
+		GROUP BY month
+		ORDER BY month DESC`, byMonth).Scan(&facets.Months).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计月份分面失败: %w", err)
+	}
+
+	return facets, nil
+}
+
+// DayCount 按发布日期的文章数
+type DayCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
+// ArchiveMonths 统计已发布文章在每个月的数量，按月份倒序
+func (r *ArticleRepository) ArchiveMonths() ([]*MonthCount, error) {
+	var months []*MonthCount
+	err := r.db.Model(&model.Article{}).
+		Select("DATE_FORMAT(published_at, '%Y-%m') AS month, COUNT(*) AS count").
+		Where("status = ? AND published_at IS NOT NULL", model.ArticleStatusPublished).
+		Group("month").
+		Order("month DESC").
+		Scan(&months).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计归档月份失败: %w", err)
+	}
+	return months, nil
+}
+
+// ArchiveDays 统计时间范围 [from, to) 内已发布文章每天的数量，按日期升序，没有文章的日期不返回
+func (r *ArticleRepository) ArchiveDays(from, to time.Time) ([]*DayCount, error) {
+	var days []*DayCount
+	err := r.db.Model(&model.Article{}).
+		Select("DATE_FORMAT(published_at, '%Y-%m-%d') AS date, COUNT(*) AS count").
+		Where("status = ? AND published_at >= ? AND published_at < ?", model.ArticleStatusPublished, from, to).
+		Group("date").
+		Order("date").
+		Scan(&days).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计每日发布数失败: %w", err)
+	}
+	return days, nil
+}
+
+// PublishedIDsBetween 获取时间范围 [from, to) 内已发布文章的ID，按发布时间倒序
+func (r *ArticleRepository) PublishedIDsBetween(from, to time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Article{}).
+		Where("status = ? AND published_at >= ? AND published_at < ?", model.ArticleStatusPublished, from, to).
+		Order("published_at DESC, id DESC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询归档文章失败: %w", err)
+	}
+	return ids, nil
+}
+
+// UpdateRendered 保存渲染结果，只写入由原文派生的字段，不修改原文、摘要和文章的更新时间
+// restoreSource 为 true 时同时写入还原后的原文和摘要（旧版本保存时做过HTML转义），并递增版本号，
+// 使持有旧原文的编辑会话在保存时得到版本冲突
+// 以读取时的版本号为条件，期间文章已被编辑时不写入（编辑保存时已按新原文重新渲染）
+func (r *ArticleRepository) UpdateRendered(article *model.Article, restoreSource bool) error {
+	columns := map[string]interface{}{
+		"content_html":   article.ContentHTML,
+		"toc":            article.TOC,
+		"word_count":     article.WordCount,
+		"reading_time":   article.ReadingTime,
+		"render_version": article.RenderVersion,
+	}
+	if restoreSource {
+		columns["content"] = article.Content
+		columns["summary"] = article.Summary
+		columns["version"] = gorm.Expr("version + 1")
+	}
+
+	err := r.db.Model(&model.Article{}).Where("id = ? AND version = ?", article.ID, article.Version).UpdateColumns(columns).Error
+	if err != nil {
+		return fmt.Errorf("保存文章渲染结果失败: %w", err)
+	}
+	return nil
 }
 
 // 私有辅助方法
@@ -435,9 +1095,8 @@ func (r *ArticleRepository) SetPrivate(id uint) error {
 // ensureUniqueSlug 确保slug唯一
 func (r *ArticleRepository) ensureUniqueSlug(article *model.Article) error {
 	originalSlug := article.Slug
-	counter := 1
 
-	for {
+	for counter := 1; ; counter++ {
 		var count int64
 		query := r.db.Model(&model.Article{}).Where("slug = ?", article.Slug)
 
@@ -450,33 +1109,28 @@ func (r *ArticleRepository) ensureUniqueSlug(article *model.Article) error {
 			return err
 		}
 
+		// 其他文章用过的Slug同样视为占用，保证旧链接不会指向别的文章
 		if count == 0 {
-			break
+			history := r.db.Model(&model.ArticleSlugHistory{}).Where("slug = ?", article.Slug)
+			if article.ID != 0 {
+				history = history.Where("article_id != ?", article.ID)
+			}
+			if err := history.Count(&count).Error; err != nil {
+				return err
+			}
 		}
 
-		article.Slug = fmt.Sprintf("%s-%d", originalSlug, counter)
-		counter++
-	}
-
-	return nil
-}
-
-// generateSlug 从标题生成slug
-func generateSlug(title string) string {
-	// 简单的slug生成逻辑，实际项目中可能需要更复杂的处理
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-	slug = strings.ReplaceAll(slug, "_", "-")
+		if count == 0 {
+			return nil
+		}
 
-	// 移除特殊字符（保留字母、数字、连字符）
-	var result strings.Builder
-	for _, r := range slug {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			result.WriteRune(r)
+		// 同名较多时改用短哈希后缀，避免逐个尝试数字
+		if counter > maxSlugSuffix {
+			article.Slug = fmt.Sprintf("%s-%s", originalSlug, slug.Fallback(originalSlug))
+		} else {
+			article.Slug = fmt.Sprintf("%s-%d", originalSlug, counter)
 		}
 	}
-
-	return result.String()
 }
 
 // applyFilters 应用筛选条件
@@ -489,14 +1143,87 @@ func (r *ArticleRepository) applyFilters(query *gorm.DB, params *ArticleListPara
 		query = query.Where("author_id = ?", params.AuthorID)
 	}
 
+	if params.CategoryID != 0 {
+		query = query.Where("category_id = ? OR EXISTS (SELECT 1 FROM article_categories WHERE article_categories.article_id = articles.id AND article_categories.category_id = ?)", params.CategoryID, params.CategoryID)
+	}
+
+	if params.TagID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM article_tags WHERE article_tags.article_id = articles.id AND article_tags.tag_id = ?)", params.TagID)
+	}
+
+	if len(params.CategoryIDs) > 0 {
+		if params.CategoryMatchAll {
+			for _, id := range params.CategoryIDs {
+				query = query.Where("category_id = ? OR EXISTS (SELECT 1 FROM article_categories WHERE article_categories.article_id = articles.id AND article_categories.category_id = ?)", id, id)
+			}
+		} else {
+			query = query.Where("category_id IN ? OR EXISTS (SELECT 1 FROM article_categories WHERE article_categories.article_id = articles.id AND article_categories.category_id IN ?)", params.CategoryIDs, params.CategoryIDs)
+		}
+	}
+
+	if len(params.TagIDs) > 0 {
+		if params.TagMatchAll {
+			for _, id := range params.TagIDs {
+				query = query.Where("EXISTS (SELECT 1 FROM article_tags WHERE article_tags.article_id = articles.id AND article_tags.tag_id = ?)", id)
+			}
+		} else {
+			query = query.Where("EXISTS (SELECT 1 FROM article_tags WHERE article_tags.article_id = articles.id AND article_tags.tag_id IN ?)", params.TagIDs)
+		}
+	}
+
+	if params.PublishedFrom != nil {
+		query = query.Where("published_at >= ?", *params.PublishedFrom)
+	}
+	if params.PublishedTo != nil {
+		query = query.Where("published_at <= ?", *params.PublishedTo)
+	}
+
+	if params.IsFeatured != nil {
+		query = query.Where("is_featured = ?", *params.IsFeatured)
+	}
+	if params.IsTop != nil {
+		query = query.Where("is_top = ?", *params.IsTop)
+	}
+
+	if params.MinWordCount > 0 {
+		query = query.Where("word_count >= ?", params.MinWordCount)
+	}
+	if params.MaxWordCount > 0 {
+		query = query.Where("word_count <= ?", params.MaxWordCount)
+	}
+	if params.MinReadingTime > 0 {
+		query = query.Where("reading_time >= ?", params.MinReadingTime)
+	}
+	if params.MaxReadingTime > 0 {
+		query = query.Where("reading_time <= ?", params.MaxReadingTime)
+	}
+
 	if params.Search != "" {
-		searchTerm := "%" + params.Search + "%"
-		query = query.Where("title LIKE ? OR content LIKE ? OR summary LIKE ?", searchTerm, searchTerm, searchTerm)
+		query = r.applySearch(query, params.Search, params.Member)
 	}
 
 	return query
 }
 
+// applySearch 应用关键词搜索条件
+// 受保护文章的隐藏内容不参与匹配，避免通过搜索结果推断正文：
+// 密码文章只匹配标题；会员文章游客可匹配标题和摘要，登录用户可匹配正文
+func (r *ArticleRepository) applySearch(query *gorm.DB, keyword string, member bool) *gorm.DB {
+	searchTerm := "%" + keyword + "%"
+	return query.Where(
+		"title LIKE ? OR (summary LIKE ? AND visibility <> ?) OR (content LIKE ? AND visibility IN ?)",
+		searchTerm, searchTerm, model.VisibilityPassword, searchTerm, searchContentVisibility(member),
+	)
+}
+
+// searchContentVisibility 可按正文搜索的可见范围：游客只能搜索公开文章，登录用户还可以搜索会员文章
+func searchContentVisibility(member bool) []model.Visibility {
+	if member {
+		return []model.Visibility{model.VisibilityPublic, model.VisibilityMembers}
+	}
+	return []model.Visibility{model.VisibilityPublic}
+}
+
 // applyPagination 应用分页
 func (r *ArticleRepository) applyPagination(query *gorm.DB, params *ArticleListParams) *gorm.DB {
 	if params.Page <= 0 {
@@ -510,6 +1237,51 @@ func (r *ArticleRepository) applyPagination(query *gorm.DB, params *ArticleListP
 	return query.Offset(offset).Limit(params.PageSize)
 }
 
+// findPage 应用分页和排序，查询当前页的文章
+// 设置游标时按 (排序字段, ID) 定位，不使用 OFFSET；向前翻页时反向查询，再恢复为排序方向
+func (r *ArticleRepository) findPage(query *gorm.DB, params *ArticleListParams) ([]*model.Article, error) {
+	cursor := params.Cursor
+	if cursor == nil {
+		query = r.applyPagination(query, params)
+		query = r.applySorting(query, params)
+	} else {
+		if params.PageSize <= 0 {
+			params.PageSize = 10
+		}
+		params.SortBy, params.Order = cursor.SortBy, cursor.Order
+	}
+
+	// 实际查询方向：向前翻页时与排序方向相反
+	desc := params.Order == "desc"
+	if cursor != nil && cursor.Backward {
+		desc = !desc
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	if cursor != nil {
+		sql, vars := cursor.condition(desc)
+		query = query.Where(sql, vars...).
+			Order("articles." + cursor.SortBy + " " + direction).
+			Limit(params.PageSize)
+	}
+	// 排序字段相同时按ID排序，保证翻页顺序稳定，与游标的定位方式一致
+	query = query.Order("articles.id " + direction)
+
+	var articles []*model.Article
+	if err := query.Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(articles)-1; i < j; i, j = i+1, j-1 {
+			articles[i], articles[j] = articles[j], articles[i]
+		}
+	}
+	return articles, nil
+}
+
 // applySorting 应用排序
 func (r *ArticleRepository) applySorting(query *gorm.DB, params *ArticleListParams) *gorm.DB {
 	if params.SortBy == "" {
@@ -519,6 +1291,6 @@ func (r *ArticleRepository) applySorting(query *gorm.DB, params *ArticleListPara
 		params.Order = "desc"
 	}
 
-	orderStr := params.SortBy + " " + strings.ToUpper(params.Order)
+	orderStr := "articles." + params.SortBy + " " + strings.ToUpper(params.Order)
 	return query.Order(orderStr)
 }
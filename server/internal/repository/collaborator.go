@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CollaboratorRepositoryInterface 文章协作者仓储接口
+type CollaboratorRepositoryInterface interface {
+	Upsert(collaborator *model.ArticleCollaborator) error
+	Remove(articleID, userID uint) error
+	GetRole(articleID, userID uint) (model.CollaboratorRole, error)
+	ListByArticle(articleID uint) ([]model.ArticleCollaborator, error)
+	ListSharedArticles(userID uint, params *ArticleListParams) ([]*model.Article, int64, error)
+}
+
+// CollaboratorRepository 文章协作者仓储实现
+type CollaboratorRepository struct {
+	db *gorm.DB
+}
+
+// NewCollaboratorRepository 创建文章协作者仓储实例
+func NewCollaboratorRepository(db *gorm.DB) CollaboratorRepositoryInterface {
+	return &CollaboratorRepository{db: db}
+}
+
+// Upsert 添加协作者，已存在时更新协作权限
+func (r *CollaboratorRepository) Upsert(collaborator *model.ArticleCollaborator) error {
+	err := r.db.Omit("Article", "User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "updated_at"}),
+	}).Create(collaborator).Error
+	if err != nil {
+		return fmt.Errorf("保存协作者失败: %w", err)
+	}
+	return nil
+}
+
+// Remove 移除协作者
+func (r *CollaboratorRepository) Remove(articleID, userID uint) error {
+	result := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&model.ArticleCollaborator{})
+	if result.Error != nil {
+		return fmt.Errorf("移除协作者失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("协作者不存在")
+	}
+	return nil
+}
+
+// GetRole 获取用户在文章中的协作权限，不是协作者时返回空字符串
+func (r *CollaboratorRepository) GetRole(articleID, userID uint) (model.CollaboratorRole, error) {
+	var collaborator model.ArticleCollaborator
+	err := r.db.Select("role").Where("article_id = ? AND user_id = ?", articleID, userID).First(&collaborator).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return collaborator.Role, nil
+}
+
+// ListByArticle 获取文章的协作者列表
+func (r *CollaboratorRepository) ListByArticle(articleID uint) ([]model.ArticleCollaborator, error) {
+	var collaborators []model.ArticleCollaborator
+	err := r.db.Preload("User").Where("article_id = ?", articleID).Order("created_at ASC").Find(&collaborators).Error
+	if err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// ListSharedArticles 获取共享给用户协作的文章
+func (r *CollaboratorRepository) ListSharedArticles(userID uint, params *ArticleListParams) ([]*model.Article, int64, error) {
+	query := r.db.Model(&model.Article{}).
+		Preload("Author").
+		Preload("Category").
+		Preload("Tags").
+		Where("EXISTS (SELECT 1 FROM article_collaborators WHERE article_collaborators.article_id = articles.id AND article_collaborators.user_id = ?)", userID)
+
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	var articles []*model.Article
+	if err := query.Order("updated_at DESC").Offset(offset).Limit(params.PageSize).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
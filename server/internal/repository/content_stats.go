@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TrendingParams 趋势文章查询参数
+type TrendingParams struct {
+	Since         time.Time // 统计窗口起始日期（含当天）
+	Now           time.Time // 计算衰减的基准时间
+	HalfLifeHours float64   // 热度半衰期（小时）
+	LikeWeight    float64   // 一次点赞相当于多少次浏览
+	CategoryID    *uint     // 只统计该分类（主分类或附加分类）的文章
+	TagID         *uint     // 只统计带该标签的文章
+	Limit         int
+}
+
+// TrendingScore 文章在统计窗口内的热度
+type TrendingScore struct {
+	ArticleID uint    `json:"articleId"`
+	Score     float64 `json:"score"`
+}
+
+// ContentStatsRepository 内容统计仓库接口
+type ContentStatsRepository interface {
+	// RollupArticleDay 将某天的文章浏览和点赞汇总为日统计，重复执行时替换当天的全部统计值
+	RollupArticleDay(day time.Time) (int64, error)
+	// Trending 按时间衰减后的浏览和点赞计算已发布文章的热度，按热度降序
+	Trending(params *TrendingParams) ([]*TrendingScore, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// contentStatsRepository 内容统计仓库实现
+type contentStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewContentStatsRepository 创建内容统计仓库实例
+func NewContentStatsRepository(db *gorm.DB) ContentStatsRepository {
+	return &contentStatsRepository{db: db}
+}
+
+// rollupUpsert 写入日统计，唯一索引冲突时覆盖统计值
+const rollupUpsert = `INSERT INTO content_stats (content_type, content_id, stat_type, stat_value, stat_date, created_at, updated_at) %s
+ON DUPLICATE KEY UPDATE stat_value = VALUES(stat_value), updated_at = VALUES(updated_at)`
+
+// RollupArticleDay 汇总某天的文章浏览和点赞
+// 点赞按点赞时间归入当天，已取消的点赞不计入
+// 先清除当天已有的日统计再重新写入，点赞全部取消的文章不会保留上次汇总的值
+func (r *contentStatsRepository) RollupArticleDay(day time.Time) (int64, error) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	date := day.Format("2006-01-02")
+	now := time.Now()
+
+	var affected int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("content_type = ? AND stat_type IN ? AND stat_date = ?",
+			model.ContentTypeArticle, []string{model.StatTypeDailyViews, model.StatTypeDailyLikes}, date).
+			Delete(&model.ContentStats{}).Error
+		if err != nil {
+			return err
+		}
+
+		views := tx.Exec(fmt.Sprintf(rollupUpsert,
+			"SELECT ?, article_id, ?, SUM(view_count), ?, ?, ? FROM article_views WHERE view_date = ? GROUP BY article_id"),
+			model.ContentTypeArticle, model.StatTypeDailyViews, date, now, now, date)
+		if views.Error != nil {
+			return views.Error
+		}
+		likes := tx.Exec(fmt.Sprintf(rollupUpsert,
+			"SELECT ?, article_id, ?, COUNT(*), ?, ?, ? FROM article_likes WHERE created_at >= ? AND created_at < ? GROUP BY article_id"),
+			model.ContentTypeArticle, model.StatTypeDailyLikes, date, now, now, day, day.AddDate(0, 0, 1))
+		if likes.Error != nil {
+			return likes.Error
+		}
+		affected = views.RowsAffected + likes.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("汇总文章日统计失败: %w", err)
+	}
+	return affected, nil
+}
+
+// Trending 计算文章热度
+// 每天的浏览和点赞按距当天中午的时长衰减：热度 = Σ (浏览 + 点赞 × 点赞权重) × 0.5^(距今小时数 / 半衰期)
+func (r *contentStatsRepository) Trending(params *TrendingParams) ([]*TrendingScore, error) {
+	score := "SUM(cs.stat_value * (CASE WHEN cs.stat_type = ? THEN ? ELSE 1 END) * " +
+		"POW(0.5, GREATEST(TIMESTAMPDIFF(MINUTE, TIMESTAMP(cs.stat_date) + INTERVAL 12 HOUR, ?), 0) / ?)) AS score"
+	query := r.db.Table("content_stats AS cs").
+		Select("cs.content_id AS article_id, "+score, model.StatTypeDailyLikes, params.LikeWeight, params.Now, params.HalfLifeHours*60).
+		Joins("JOIN articles ON articles.id = cs.content_id").
+		Where("cs.content_type = ? AND cs.stat_type IN ? AND cs.stat_date >= ?",
+			model.ContentTypeArticle, []string{model.StatTypeDailyViews, model.StatTypeDailyLikes}, params.Since.Format("2006-01-02")).
+		Where("articles.status = ? AND articles.deleted_at IS NULL", model.ArticleStatusPublished)
+	if params.CategoryID != nil {
+		query = query.Where("articles.category_id = ? OR EXISTS (SELECT 1 FROM article_categories WHERE article_categories.article_id = articles.id AND article_categories.category_id = ?)",
+			*params.CategoryID, *params.CategoryID)
+	}
+	if params.TagID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM article_tags WHERE article_tags.article_id = articles.id AND article_tags.tag_id = ?)", *params.TagID)
+	}
+
+	var scores []*TrendingScore
+	err := query.Group("cs.content_id").
+		Order("score DESC, cs.content_id DESC").
+		Limit(params.Limit).
+		Scan(&scores).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计趋势文章失败: %w", err)
+	}
+	return scores, nil
+}
+
+// DeleteBefore 删除统计日期早于指定日期的统计
+func (r *contentStatsRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("stat_date < ?", before.Format("2006-01-02")).Delete(&model.ContentStats{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("清理内容统计失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
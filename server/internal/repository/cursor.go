@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"MyBlog/internal/model"
+)
+
+// 游标支持的排序字段及其类型，与 applySorting 可用的排序字段一致
+var cursorSortFields = map[string]cursorFieldKind{
+	"created_at":   cursorTime,
+	"updated_at":   cursorTime,
+	"published_at": cursorNullableTime,
+	"view_count":   cursorCount,
+	"like_count":   cursorCount,
+}
+
+type cursorFieldKind int
+
+const (
+	cursorTime         cursorFieldKind = iota // 非空时间
+	cursorNullableTime                        // 可为 NULL 的时间（MySQL 升序时 NULL 在前，降序时在后）
+	cursorCount                               // 非负整数
+)
+
+// ErrInvalidCursor 分页游标格式错误或与排序方式不一致
+var ErrInvalidCursor = errors.New("分页游标无效")
+
+// ArticleCursor 文章列表的分页游标，记录当前页边界文章的排序字段值和ID
+// 翻页时按 (排序字段, ID) 定位，不受翻页期间新增或删除文章的影响
+type ArticleCursor struct {
+	SortBy   string  `json:"s"`
+	Order    string  `json:"o"`
+	Value    *string `json:"v"` // 排序字段值，为 NULL 时为 nil
+	ID       uint    `json:"i"`
+	Backward bool    `json:"b,omitempty"` // true 表示取该文章之前的一页
+}
+
+// NewArticleCursor 以文章在指定排序方式下的位置创建游标
+func NewArticleCursor(article *model.Article, sortBy, order string, backward bool) *ArticleCursor {
+	sortBy, order = cursorSort(sortBy, order)
+	cursor := &ArticleCursor{SortBy: sortBy, Order: order, ID: article.ID, Backward: backward}
+
+	var value string
+	switch sortBy {
+	case "created_at":
+		value = article.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		value = article.UpdatedAt.Format(time.RFC3339Nano)
+	case "published_at":
+		if article.PublishedAt == nil {
+			return cursor
+		}
+		value = article.PublishedAt.Format(time.RFC3339Nano)
+	case "view_count":
+		value = strconv.FormatUint(uint64(article.ViewCount), 10)
+	case "like_count":
+		value = strconv.FormatUint(uint64(article.LikeCount), 10)
+	}
+	cursor.Value = &value
+	return cursor
+}
+
+// Encode 编码为不透明的游标字符串
+func (c *ArticleCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeArticleCursor 解析游标字符串，并校验其与请求的排序方式一致
+func DecodeArticleCursor(s, sortBy, order string) (*ArticleCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor ArticleCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	sortBy, order = cursorSort(sortBy, order)
+	if cursor.SortBy != sortBy || cursor.Order != order || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := cursor.value(); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// condition 返回取游标之后（按实际查询方向）文章的条件
+// desc 为实际查询方向：向前翻页时与排序方向相反
+func (c *ArticleCursor) condition(desc bool) (string, []interface{}) {
+	column := "articles." + c.SortBy
+	value, _ := c.value()
+
+	if value == nil {
+		// 游标位于 NULL 区间：降序时 NULL 在最后，只需比较ID；升序时 NULL 在最前，之后还有全部非 NULL 的文章
+		if desc {
+			return column + " IS NULL AND articles.id < ?", []interface{}{c.ID}
+		}
+		return "(" + column + " IS NULL AND articles.id > ?) OR " + column + " IS NOT NULL", []interface{}{c.ID}
+	}
+
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	sql := column + " " + op + " ? OR (" + column + " = ? AND articles.id " + op + " ?)"
+	if desc && cursorSortFields[c.SortBy] == cursorNullableTime {
+		sql += " OR " + column + " IS NULL"
+	}
+	return sql, []interface{}{value, value, c.ID}
+}
+
+// value 将游标中的排序字段值解析为查询参数
+func (c *ArticleCursor) value() (interface{}, error) {
+	kind, ok := cursorSortFields[c.SortBy]
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	if c.Value == nil {
+		if kind != cursorNullableTime {
+			return nil, ErrInvalidCursor
+		}
+		return nil, nil
+	}
+	if kind == cursorCount {
+		return strconv.ParseUint(*c.Value, 10, 64)
+	}
+	return time.Parse(time.RFC3339Nano, *c.Value)
+}
+
+// cursorSort 补全默认排序方式，与 applySorting 的默认值一致
+func cursorSort(sortBy, order string) (string, string) {
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+	if order == "" {
+		order = "desc"
+	}
+	return sortBy, order
+}
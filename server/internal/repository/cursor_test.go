@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"MyBlog/internal/model"
+)
+
+func TestArticleCursorCondition(t *testing.T) {
+	published := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	article := &model.Article{ID: 42, PublishedAt: &published, ViewCount: 7}
+	unpublished := &model.Article{ID: 42}
+
+	cases := []struct {
+		name     string
+		article  *model.Article
+		sortBy   string
+		desc     bool
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "非空字段降序",
+			article:  article,
+			sortBy:   "view_count",
+			desc:     true,
+			wantSQL:  "articles.view_count < ? OR (articles.view_count = ? AND articles.id < ?)",
+			wantArgs: []interface{}{uint64(7), uint64(7), uint(42)},
+		},
+		{
+			name:     "非空字段升序",
+			article:  article,
+			sortBy:   "view_count",
+			desc:     false,
+			wantSQL:  "articles.view_count > ? OR (articles.view_count = ? AND articles.id > ?)",
+			wantArgs: []interface{}{uint64(7), uint64(7), uint(42)},
+		},
+		{
+			name:     "可空字段有值时降序，NULL 排在之后",
+			article:  article,
+			sortBy:   "published_at",
+			desc:     true,
+			wantSQL:  "articles.published_at < ? OR (articles.published_at = ? AND articles.id < ?) OR articles.published_at IS NULL",
+			wantArgs: []interface{}{published, published, uint(42)},
+		},
+		{
+			name:     "可空字段有值时升序，NULL 已在之前",
+			article:  article,
+			sortBy:   "published_at",
+			desc:     false,
+			wantSQL:  "articles.published_at > ? OR (articles.published_at = ? AND articles.id > ?)",
+			wantArgs: []interface{}{published, published, uint(42)},
+		},
+		{
+			name:     "游标位于 NULL 区间降序，只剩 NULL 中ID更小的",
+			article:  unpublished,
+			sortBy:   "published_at",
+			desc:     true,
+			wantSQL:  "articles.published_at IS NULL AND articles.id < ?",
+			wantArgs: []interface{}{uint(42)},
+		},
+		{
+			name:     "游标位于 NULL 区间升序，之后还有全部非 NULL 的",
+			article:  unpublished,
+			sortBy:   "published_at",
+			desc:     false,
+			wantSQL:  "(articles.published_at IS NULL AND articles.id > ?) OR articles.published_at IS NOT NULL",
+			wantArgs: []interface{}{uint(42)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			order := "asc"
+			if tc.desc {
+				order = "desc"
+			}
+			// 经过编码和解析，与实际翻页时的游标一致
+			encoded := NewArticleCursor(tc.article, tc.sortBy, order, false).Encode()
+			cursor, err := DecodeArticleCursor(encoded, tc.sortBy, order)
+			if err != nil {
+				t.Fatalf("解析游标失败: %v", err)
+			}
+
+			sql, args := cursor.condition(tc.desc)
+			if sql != tc.wantSQL {
+				t.Fatalf("condition SQL = %q, want %q", sql, tc.wantSQL)
+			}
+			if len(args) != len(tc.wantArgs) {
+				t.Fatalf("condition args = %v, want %v", args, tc.wantArgs)
+			}
+			for i := range args {
+				if got, want := args[i], tc.wantArgs[i]; !reflect.DeepEqual(got, want) {
+					if gt, ok := got.(time.Time); !ok || !gt.Equal(want.(time.Time)) {
+						t.Fatalf("condition args[%d] = %#v, want %#v", i, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeArticleCursor(t *testing.T) {
+	article := &model.Article{ID: 5, CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)}
+	valid := NewArticleCursor(article, "", "", true).Encode()
+
+	cases := []struct {
+		name    string
+		cursor  string
+		sortBy  string
+		order   string
+		wantErr bool
+	}{
+		{name: "默认排序方式", cursor: valid},
+		{name: "显式指定默认排序方式", cursor: valid, sortBy: "created_at", order: "desc"},
+		{name: "排序字段不一致", cursor: valid, sortBy: "updated_at", wantErr: true},
+		{name: "排序方向不一致", cursor: valid, order: "asc", wantErr: true},
+		{name: "不是 base64", cursor: "!!!", wantErr: true},
+		{name: "不是 JSON", cursor: "bm90LWpzb24", wantErr: true},
+		{name: "缺少文章ID", cursor: (&ArticleCursor{SortBy: "created_at", Order: "desc"}).Encode(), wantErr: true},
+		{name: "不支持的排序字段", cursor: (&ArticleCursor{SortBy: "title", Order: "desc", ID: 1}).Encode(), sortBy: "title", wantErr: true},
+		{name: "非空字段的值为 NULL", cursor: (&ArticleCursor{SortBy: "created_at", Order: "desc", ID: 1}).Encode(), wantErr: true},
+		{name: "计数字段的值不是数字", cursor: (&ArticleCursor{SortBy: "view_count", Order: "desc", ID: 1, Value: strPtr("abc")}).Encode(), sortBy: "view_count", wantErr: true},
+		{name: "可空字段的值为 NULL", cursor: (&ArticleCursor{SortBy: "published_at", Order: "asc", ID: 1}).Encode(), sortBy: "published_at", order: "asc"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := DecodeArticleCursor(tc.cursor, tc.sortBy, tc.order)
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidCursor) {
+					t.Fatalf("应返回 ErrInvalidCursor, got cursor=%+v err=%v", cursor, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("解析游标失败: %v", err)
+			}
+		})
+	}
+
+	cursor, err := DecodeArticleCursor(valid, "", "")
+	if err != nil {
+		t.Fatalf("解析游标失败: %v", err)
+	}
+	if cursor.ID != 5 || !cursor.Backward || cursor.Value == nil || *cursor.Value != article.CreatedAt.Format(time.RFC3339Nano) {
+		t.Fatalf("游标内容与编码前不一致: %+v", cursor)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
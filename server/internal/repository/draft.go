@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DraftRepositoryInterface 文章自动保存草稿仓储接口
+type DraftRepositoryInterface interface {
+	Save(draft *model.ArticleDraft) error
+	Get(articleID, userID uint) (*model.ArticleDraft, error)
+	Delete(articleID, userID uint) error
+}
+
+// DraftRepository 文章自动保存草稿仓储实现
+type DraftRepository struct {
+	db *gorm.DB
+}
+
+// NewDraftRepository 创建文章草稿仓储实例
+func NewDraftRepository(db *gorm.DB) DraftRepositoryInterface {
+	return &DraftRepository{db: db}
+}
+
+// Save 保存草稿，同一用户同一文章只保留最新一份
+func (r *DraftRepository) Save(draft *model.ArticleDraft) error {
+	err := r.db.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "article_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "summary", "content", "base_version", "updated_at"}),
+	}).Create(draft).Error
+	if err != nil {
+		return fmt.Errorf("保存草稿失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取用户在文章上的草稿
+func (r *DraftRepository) Get(articleID, userID uint) (*model.ArticleDraft, error) {
+	var draft model.ArticleDraft
+	err := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).First(&draft).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("草稿不存在")
+		}
+		return nil, fmt.Errorf("查询草稿失败: %w", err)
+	}
+	return &draft, nil
+}
+
+// Delete 删除用户在文章上的草稿
+func (r *DraftRepository) Delete(articleID, userID uint) error {
+	err := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&model.ArticleDraft{}).Error
+	if err != nil {
+		return fmt.Errorf("删除草稿失败: %w", err)
+	}
+	return nil
+}
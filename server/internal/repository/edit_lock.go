@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// EditLockRepositoryInterface 文章编辑锁仓储接口
+type EditLockRepositoryInterface interface {
+	Acquire(lock *model.ArticleEditLock, force bool) (bool, error)
+	Refresh(articleID, userID uint, sessionID string, now, expiresAt time.Time) (bool, error)
+	Release(articleID, userID uint, sessionID string) error
+	Get(articleID uint) (*model.ArticleEditLock, error)
+}
+
+// EditLockRepository 文章编辑锁仓储实现
+type EditLockRepository struct {
+	db *gorm.DB
+}
+
+// NewEditLockRepository 创建文章编辑锁仓储实例
+func NewEditLockRepository(db *gorm.DB) EditLockRepositoryInterface {
+	return &EditLockRepository{db: db}
+}
+
+// Acquire 获取编辑锁，返回是否获取成功
+// 锁不存在、已过期或本就属于同一会话时获取成功；force 为 true 时强制接管他人的锁。
+// 通过条件更新和文章ID唯一索引保证多实例并发获取时只有一个会话成功
+func (r *EditLockRepository) Acquire(lock *model.ArticleEditLock, force bool) (bool, error) {
+	now := lock.AcquiredAt
+	query := r.db.Model(&model.ArticleEditLock{}).Where("article_id = ?", lock.ArticleID)
+	if !force {
+		query = query.Where("expires_at <= ? OR (user_id = ? AND session_id = ?)", now, lock.UserID, lock.SessionID)
+	}
+
+	// 已有锁且可接管：续期同一会话时保留原获取时间
+	result := query.Updates(map[string]interface{}{
+		"acquired_at":  gorm.Expr("CASE WHEN user_id = ? AND session_id = ? THEN acquired_at ELSE ? END", lock.UserID, lock.SessionID, now),
+		"user_id":      lock.UserID,
+		"session_id":   lock.SessionID,
+		"heartbeat_at": now,
+		"expires_at":   lock.ExpiresAt,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("获取编辑锁失败: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// 没有可接管的锁时尝试新建，唯一索引冲突说明锁被他人持有
+	if err := r.db.Omit("Article", "User").Create(lock).Error; err != nil {
+		existing, getErr := r.Get(lock.ArticleID)
+		if getErr == nil && existing != nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("获取编辑锁失败: %w", err)
+	}
+	return true, nil
+}
+
+// Refresh 心跳续期，只有仍持有锁的会话可以续期，返回是否续期成功
+func (r *EditLockRepository) Refresh(articleID, userID uint, sessionID string, now, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&model.ArticleEditLock{}).
+		Where("article_id = ? AND user_id = ? AND session_id = ?", articleID, userID, sessionID).
+		Updates(map[string]interface{}{
+			"heartbeat_at": now,
+			"expires_at":   expiresAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("编辑锁续期失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release 释放编辑锁，只删除指定会话持有的锁
+func (r *EditLockRepository) Release(articleID, userID uint, sessionID string) error {
+	err := r.db.Where("article_id = ? AND user_id = ? AND session_id = ?", articleID, userID, sessionID).
+		Delete(&model.ArticleEditLock{}).Error
+	if err != nil {
+		return fmt.Errorf("释放编辑锁失败: %w", err)
+	}
+	return nil
+}
+
+// Get 获取文章的编辑锁（含持有人信息），没有锁时返回 nil
+func (r *EditLockRepository) Get(articleID uint) (*model.ArticleEditLock, error) {
+	var lock model.ArticleEditLock
+	err := r.db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "nickname", "avatar")
+	}).Where("article_id = ?", articleID).First(&lock).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询编辑锁失败: %w", err)
+	}
+	return &lock, nil
+}
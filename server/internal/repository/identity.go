@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IdentityRepository 第三方身份仓库接口
+type IdentityRepository interface {
+	Create(identity *model.UserIdentity) error
+	GetByProviderSubject(provider, subject string) (*model.UserIdentity, error)
+	ListByUser(userID uint) ([]*model.UserIdentity, error)
+	CountByUser(userID uint) (int64, error)
+	Delete(userID uint, provider string) error
+	UpdateLoginInfo(identity *model.UserIdentity) error
+}
+
+// identityRepository 第三方身份仓库实现
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository 创建第三方身份仓库实例
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create 创建身份绑定
+func (r *identityRepository) Create(identity *model.UserIdentity) error {
+	if err := r.db.Create(identity).Error; err != nil {
+		return fmt.Errorf("创建身份绑定失败: %w", err)
+	}
+	return nil
+}
+
+// GetByProviderSubject 根据提供方和提供方用户标识获取身份
+func (r *identityRepository) GetByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("身份绑定不存在")
+		}
+		return nil, fmt.Errorf("查询身份绑定失败: %w", err)
+	}
+	return &identity, nil
+}
+
+// ListByUser 获取用户的全部身份绑定
+func (r *identityRepository) ListByUser(userID uint) ([]*model.UserIdentity, error) {
+	var identities []*model.UserIdentity
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("查询身份绑定列表失败: %w", err)
+	}
+	return identities, nil
+}
+
+// CountByUser 统计用户的身份绑定数量
+func (r *identityRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.UserIdentity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计身份绑定失败: %w", err)
+	}
+	return count, nil
+}
+
+// Delete 解除用户在指定提供方的身份绑定
+func (r *identityRepository) Delete(userID uint, provider string) error {
+	result := r.db.Where("user_id = ? AND provider = ?", userID, provider).Delete(&model.UserIdentity{})
+	if result.Error != nil {
+		return fmt.Errorf("解除身份绑定失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("身份绑定不存在")
+	}
+	return nil
+}
+
+// UpdateLoginInfo 更新身份的资料快照和最后登录时间
+func (r *identityRepository) UpdateLoginInfo(identity *model.UserIdentity) error {
+	now := time.Now()
+	identity.LastLoginAt = &now
+	err := r.db.Model(&model.UserIdentity{}).Where("id = ?", identity.ID).Updates(map[string]interface{}{
+		"email":          identity.Email,
+		"email_verified": identity.EmailVerified,
+		"username":       identity.Username,
+		"avatar_url":     identity.AvatarURL,
+		"raw_profile":    identity.RawProfile,
+		"last_login_at":  now,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新身份登录信息失败: %w", err)
+	}
+	return nil
+}
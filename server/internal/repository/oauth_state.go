@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// OAuthStateRepository 第三方登录授权状态仓库接口
+type OAuthStateRepository interface {
+	Create(state *model.OAuthState) error
+	// Consume 取出并删除授权状态，多个实例并发消费同一 state 时只有一个成功
+	Consume(stateHash string) (*model.OAuthState, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// oauthStateRepository 第三方登录授权状态仓库实现
+type oauthStateRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthStateRepository 创建第三方登录授权状态仓库实例
+func NewOAuthStateRepository(db *gorm.DB) OAuthStateRepository {
+	return &oauthStateRepository{db: db}
+}
+
+// Create 保存授权状态
+func (r *oauthStateRepository) Create(state *model.OAuthState) error {
+	if err := r.db.Create(state).Error; err != nil {
+		return fmt.Errorf("保存授权状态失败: %w", err)
+	}
+	return nil
+}
+
+// Consume 取出并删除授权状态
+func (r *oauthStateRepository) Consume(stateHash string) (*model.OAuthState, error) {
+	var state model.OAuthState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("授权状态不存在")
+		}
+		return nil, fmt.Errorf("查询授权状态失败: %w", err)
+	}
+
+	// 以删除成功作为消费成功的依据，防止同一 state 被重复使用
+	result := r.db.Where("id = ?", state.ID).Delete(&model.OAuthState{})
+	if result.Error != nil {
+		return nil, fmt.Errorf("删除授权状态失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("授权状态不存在")
+	}
+	return &state, nil
+}
+
+// DeleteExpired 删除已过期的授权状态
+func (r *oauthStateRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&model.OAuthState{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("清理授权状态失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// OperationLogFilter 操作日志查询条件
+type OperationLogFilter struct {
+	UserID       uint
+	Action       string
+	ResourceType string
+	ResourceID   uint
+	StartTime    *time.Time
+	EndTime      *time.Time
+}
+
+// OperationLogRepository 操作日志仓库接口
+type OperationLogRepository interface {
+	Create(log *model.OperationLog) error
+	List(filter *OperationLogFilter, offset, limit int) ([]*model.OperationLog, int64, error)
+}
+
+// operationLogRepository 操作日志仓库实现
+type operationLogRepository struct {
+	db *gorm.DB
+}
+
+// NewOperationLogRepository 创建操作日志仓库实例
+func NewOperationLogRepository(db *gorm.DB) OperationLogRepository {
+	return &operationLogRepository{db: db}
+}
+
+// Create 写入操作日志
+func (r *operationLogRepository) Create(log *model.OperationLog) error {
+	if err := r.db.Create(log).Error; err != nil {
+		return fmt.Errorf("写入操作日志失败: %w", err)
+	}
+	return nil
+}
+
+// List 按条件分页查询操作日志，按时间倒序
+func (r *operationLogRepository) List(filter *OperationLogFilter, offset, limit int) ([]*model.OperationLog, int64, error) {
+	query := r.db.Model(&model.OperationLog{})
+	if filter != nil {
+		if filter.UserID > 0 {
+			query = query.Where("user_id = ?", filter.UserID)
+		}
+		if filter.Action != "" {
+			query = query.Where("action = ?", filter.Action)
+		}
+		if filter.ResourceType != "" {
+			query = query.Where("resource_type = ?", filter.ResourceType)
+		}
+		if filter.ResourceID > 0 {
+			query = query.Where("resource_id = ?", filter.ResourceID)
+		}
+		if filter.StartTime != nil {
+			query = query.Where("created_at >= ?", *filter.StartTime)
+		}
+		if filter.EndTime != nil {
+			query = query.Where("created_at <= ?", *filter.EndTime)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计操作日志失败: %w", err)
+	}
+
+	var logs []*model.OperationLog
+	err := query.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "nickname")
+		}).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&logs).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询操作日志失败: %w", err)
+	}
+	return logs, total, nil
+}
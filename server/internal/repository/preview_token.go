@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// PreviewTokenRepository 文章预览令牌仓库接口
+type PreviewTokenRepository interface {
+	Create(token *model.ArticlePreviewToken) error
+	GetByID(id uint) (*model.ArticlePreviewToken, error)
+	ListByArticle(articleID uint) ([]*model.ArticlePreviewToken, error)
+	CountActive(articleID uint, now time.Time) (int64, error)
+	Revoke(articleID, id uint) error
+	RecordAccess(id uint, accessedAt time.Time) error
+}
+
+// previewTokenRepository 文章预览令牌仓库实现
+type previewTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPreviewTokenRepository 创建文章预览令牌仓库实例
+func NewPreviewTokenRepository(db *gorm.DB) PreviewTokenRepository {
+	return &previewTokenRepository{db: db}
+}
+
+// Create 创建预览令牌
+func (r *previewTokenRepository) Create(token *model.ArticlePreviewToken) error {
+	if err := r.db.Omit("Article", "Creator").Create(token).Error; err != nil {
+		return fmt.Errorf("创建预览链接失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取预览令牌
+func (r *previewTokenRepository) GetByID(id uint) (*model.ArticlePreviewToken, error) {
+	var token model.ArticlePreviewToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("预览链接不存在")
+		}
+		return nil, fmt.Errorf("查询预览链接失败: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByArticle 获取文章的全部预览令牌
+func (r *previewTokenRepository) ListByArticle(articleID uint) ([]*model.ArticlePreviewToken, error) {
+	var tokens []*model.ArticlePreviewToken
+	err := r.db.Preload("Creator", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "nickname", "avatar")
+	}).Where("article_id = ?", articleID).Order("created_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询预览链接列表失败: %w", err)
+	}
+	return tokens, nil
+}
+
+// CountActive 统计文章未撤销且未过期的预览令牌数
+func (r *previewTokenRepository) CountActive(articleID uint, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ArticlePreviewToken{}).
+		Where("article_id = ? AND revoked_at IS NULL AND expires_at > ?", articleID, now).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("统计预览链接失败: %w", err)
+	}
+	return count, nil
+}
+
+// Revoke 撤销文章的指定预览令牌
+func (r *previewTokenRepository) Revoke(articleID, id uint) error {
+	result := r.db.Model(&model.ArticlePreviewToken{}).
+		Where("id = ? AND article_id = ? AND revoked_at IS NULL", id, articleID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("撤销预览链接失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("预览链接不存在或已撤销")
+	}
+	return nil
+}
+
+// RecordAccess 累加访问次数并更新最后访问时间
+func (r *previewTokenRepository) RecordAccess(id uint, accessedAt time.Time) error {
+	err := r.db.Model(&model.ArticlePreviewToken{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"access_count":   gorm.Expr("access_count + 1"),
+		"last_access_at": accessedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新预览链接访问记录失败: %w", err)
+	}
+	return nil
+}
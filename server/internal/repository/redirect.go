@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RedirectRepository 跳转规则仓库接口
+type RedirectRepository interface {
+	Create(redirect *model.Redirect) error
+	Update(redirect *model.Redirect) error
+	Delete(id uint) error
+	GetByID(id uint) (*model.Redirect, error)
+	// GetByFromPath 根据源路径获取规则，不存在时返回 nil
+	GetByFromPath(path string) (*model.Redirect, error)
+	List(keyword string, offset, limit int) ([]*model.Redirect, int64, error)
+	RecordHit(id uint, hitAt time.Time) error
+}
+
+// redirectRepository 跳转规则仓库实现
+type redirectRepository struct {
+	db *gorm.DB
+}
+
+// NewRedirectRepository 创建跳转规则仓库实例
+func NewRedirectRepository(db *gorm.DB) RedirectRepository {
+	return &redirectRepository{db: db}
+}
+
+// Create 创建跳转规则
+func (r *redirectRepository) Create(redirect *model.Redirect) error {
+	if err := r.db.Create(redirect).Error; err != nil {
+		return fmt.Errorf("创建跳转规则失败: %w", err)
+	}
+	return nil
+}
+
+// Update 更新跳转规则的路径、状态码和备注
+func (r *redirectRepository) Update(redirect *model.Redirect) error {
+	err := r.db.Model(redirect).Select("from_path", "to_path", "status_code", "note").Updates(redirect).Error
+	if err != nil {
+		return fmt.Errorf("更新跳转规则失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除跳转规则
+func (r *redirectRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.Redirect{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("删除跳转规则失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("跳转规则不存在")
+	}
+	return nil
+}
+
+// GetByID 根据ID获取跳转规则
+func (r *redirectRepository) GetByID(id uint) (*model.Redirect, error) {
+	var redirect model.Redirect
+	if err := r.db.First(&redirect, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("跳转规则不存在")
+		}
+		return nil, fmt.Errorf("查询跳转规则失败: %w", err)
+	}
+	return &redirect, nil
+}
+
+// GetByFromPath 根据源路径获取跳转规则
+func (r *redirectRepository) GetByFromPath(path string) (*model.Redirect, error) {
+	var redirect model.Redirect
+	if err := r.db.Where("from_path = ?", path).First(&redirect).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("查询跳转规则失败: %w", err)
+	}
+	return &redirect, nil
+}
+
+// List 分页获取跳转规则，keyword 同时匹配源路径和目标路径
+func (r *redirectRepository) List(keyword string, offset, limit int) ([]*model.Redirect, int64, error) {
+	query := r.db.Model(&model.Redirect{})
+	if keyword != "" {
+		like := "%" + keyword + "%"
+		query = query.Where("from_path LIKE ? OR to_path LIKE ?", like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计跳转规则失败: %w", err)
+	}
+
+	var redirects []*model.Redirect
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&redirects).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询跳转规则列表失败: %w", err)
+	}
+	return redirects, total, nil
+}
+
+// RecordHit 累加命中次数并更新最后命中时间
+func (r *redirectRepository) RecordHit(id uint, hitAt time.Time) error {
+	err := r.db.Model(&model.Redirect{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+		"hit_count":   gorm.Expr("hit_count + 1"),
+		"last_hit_at": hitAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新跳转规则命中记录失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RevisionRepositoryInterface 文章修订历史仓储接口
+type RevisionRepositoryInterface interface {
+	Create(revision *model.ArticleRevision) error
+	CountByArticle(articleID uint) (int64, error)
+	ListByArticle(articleID uint) ([]*model.ArticleRevision, error)
+	GetByNumber(articleID, number uint) (*model.ArticleRevision, error)
+	PruneBefore(articleID uint, cutoff time.Time) (int64, error)
+}
+
+// RevisionRepository 文章修订历史仓储实现
+type RevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewRevisionRepository 创建文章修订历史仓储实例
+func NewRevisionRepository(db *gorm.DB) RevisionRepositoryInterface {
+	return &RevisionRepository{db: db}
+}
+
+// Create 保存修订快照，修订号在文章内递增
+func (r *RevisionRepository) Create(revision *model.ArticleRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var maxNumber uint
+		err := tx.Model(&model.ArticleRevision{}).
+			Where("article_id = ?", revision.ArticleID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&maxNumber).Error
+		if err != nil {
+			return fmt.Errorf("查询修订号失败: %w", err)
+		}
+
+		revision.Number = maxNumber + 1
+		if err := tx.Omit("Article", "Editor").Create(revision).Error; err != nil {
+			return fmt.Errorf("保存修订记录失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountByArticle 统计文章的修订数
+func (r *RevisionRepository) CountByArticle(articleID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&model.ArticleRevision{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计修订记录失败: %w", err)
+	}
+	return count, nil
+}
+
+// ListByArticle 获取文章的修订列表（不含正文），按修订号倒序
+func (r *RevisionRepository) ListByArticle(articleID uint) ([]*model.ArticleRevision, error) {
+	var revisions []*model.ArticleRevision
+	err := r.db.Omit("content").
+		Preload("Editor", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "nickname", "avatar")
+		}).
+		Where("article_id = ?", articleID).
+		Order("number DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询修订列表失败: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetByNumber 根据修订号获取修订详情
+func (r *RevisionRepository) GetByNumber(articleID, number uint) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	err := r.db.Preload("Editor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "nickname", "avatar")
+	}).Where("article_id = ? AND number = ?", articleID, number).First(&revision).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("修订版本不存在")
+		}
+		return nil, fmt.Errorf("查询修订版本失败: %w", err)
+	}
+	return &revision, nil
+}
+
+// PruneBefore 清理早于截止时间的修订，每天只保留当天最后一个版本
+func (r *RevisionRepository) PruneBefore(articleID uint, cutoff time.Time) (int64, error) {
+	var revisions []*model.ArticleRevision
+	err := r.db.Select("id", "created_at").
+		Where("article_id = ? AND created_at < ?", articleID, cutoff).
+		Order("created_at ASC, id ASC").
+		Find(&revisions).Error
+	if err != nil {
+		return 0, fmt.Errorf("查询待清理修订失败: %w", err)
+	}
+
+	// 同一天的修订按时间排序，只有当天最后一个不删除
+	var staleIDs []uint
+	for i, revision := range revisions {
+		if i+1 < len(revisions) && sameDay(revision.CreatedAt, revisions[i+1].CreatedAt) {
+			staleIDs = append(staleIDs, revision.ID)
+		}
+	}
+	if len(staleIDs) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Where("id IN ?", staleIDs).Delete(&model.ArticleRevision{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("清理修订记录失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// sameDay 判断两个时间是否在同一天（本地时区）
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Local().Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
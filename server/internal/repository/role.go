@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository 角色仓库接口
+type RoleRepository interface {
+	List() ([]*model.Role, error)
+	GetByName(name string) (*model.Role, error)
+	Create(role *model.Role, permissions []string) error
+	Update(role *model.Role, permissions []string) error
+	Delete(id uint) error
+	CountUsers(roleName string) (int64, error)
+}
+
+// roleRepository 角色仓库实现
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository 创建角色仓库实例
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// List 获取全部角色及其权限
+func (r *roleRepository) List() ([]*model.Role, error) {
+	var roles []*model.Role
+	if err := r.db.Preload("Permissions").Order("level DESC, id ASC").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("查询角色列表失败: %w", err)
+	}
+	return roles, nil
+}
+
+// GetByName 根据角色标识获取角色
+func (r *roleRepository) GetByName(name string) (*model.Role, error) {
+	var role model.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("角色不存在")
+		}
+		return nil, fmt.Errorf("查询角色失败: %w", err)
+	}
+	return &role, nil
+}
+
+// Create 创建角色并写入权限
+func (r *roleRepository) Create(role *model.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			return fmt.Errorf("创建角色失败: %w", err)
+		}
+		return replaceRolePermissions(tx, role.ID, permissions)
+	})
+}
+
+// Update 更新角色基本信息并替换权限
+func (r *roleRepository) Update(role *model.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Role{}).Where("id = ?", role.ID).Updates(map[string]interface{}{
+			"display_name": role.DisplayName,
+			"description":  role.Description,
+			"level":        role.Level,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("更新角色失败: %w", err)
+		}
+		return replaceRolePermissions(tx, role.ID, permissions)
+	})
+}
+
+// Delete 删除角色及其权限
+func (r *roleRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&model.RolePermission{}).Error; err != nil {
+			return fmt.Errorf("删除角色权限失败: %w", err)
+		}
+		if err := tx.Delete(&model.Role{}, id).Error; err != nil {
+			return fmt.Errorf("删除角色失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountUsers 统计使用指定角色的用户数
+func (r *roleRepository) CountUsers(roleName string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&User{}).Where("role = ?", roleName).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计角色用户数失败: %w", err)
+	}
+	return count, nil
+}
+
+// replaceRolePermissions 在事务中替换角色权限
+func replaceRolePermissions(tx *gorm.DB, roleID uint, permissions []string) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&model.RolePermission{}).Error; err != nil {
+		return fmt.Errorf("清除角色权限失败: %w", err)
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	records := make([]model.RolePermission, 0, len(permissions))
+	for _, p := range permissions {
+		records = append(records, model.RolePermission{RoleID: roleID, Permission: p})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return fmt.Errorf("写入角色权限失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// searcherExpr 区分搜索者：登录用户按用户ID，游客按IP，同一人重复搜索（含翻页）只计一次
+const searcherExpr = "COUNT(DISTINCT COALESCE(CONCAT('u', user_id), ip_address))"
+
+// KeywordStat 关键词搜索统计
+type KeywordStat struct {
+	Keyword        string    `json:"keyword"`
+	Searches       int64     `json:"searches"`       // 搜索次数
+	Searchers      int64     `json:"searchers"`      // 搜索人数
+	LastSearchedAt time.Time `json:"lastSearchedAt"` // 最近一次搜索时间
+}
+
+// SearchLogRepository 搜索记录仓库接口
+type SearchLogRepository interface {
+	Create(log *model.SearchLog) error
+	// Popular 统计时间范围内有结果的关键词，按搜索人数降序；prefix 非空时只统计以其开头的关键词
+	// minSearchers 大于 0 时只返回搜索人数不少于该值的关键词
+	Popular(prefix string, since time.Time, minSearchers int, limit int) ([]*KeywordStat, error)
+	// ZeroResults 统计时间范围内最近一次搜索没有结果的关键词，按搜索次数降序分页
+	ZeroResults(since time.Time, offset, limit int) ([]*KeywordStat, int64, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// searchLogRepository 搜索记录仓库实现
+type searchLogRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchLogRepository 创建搜索记录仓库实例
+func NewSearchLogRepository(db *gorm.DB) SearchLogRepository {
+	return &searchLogRepository{db: db}
+}
+
+// Create 写入搜索记录
+func (r *searchLogRepository) Create(log *model.SearchLog) error {
+	if err := r.db.Create(log).Error; err != nil {
+		return fmt.Errorf("写入搜索记录失败: %w", err)
+	}
+	return nil
+}
+
+// Popular 统计热门关键词
+func (r *searchLogRepository) Popular(prefix string, since time.Time, minSearchers int, limit int) ([]*KeywordStat, error) {
+	query := r.db.Model(&model.SearchLog{}).
+		Select("normalized AS keyword, COUNT(*) AS searches, "+searcherExpr+" AS searchers, MAX(created_at) AS last_searched_at").
+		Where("created_at >= ? AND results_count > 0 AND normalized <> ''", since)
+	if prefix != "" {
+		query = query.Where("normalized LIKE ?", escapeLike(prefix)+"%")
+	}
+
+	query = query.Group("normalized")
+	if minSearchers > 0 {
+		query = query.Having("searchers >= ?", minSearchers)
+	}
+
+	var stats []*KeywordStat
+	err := query.Order("searchers DESC, searches DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计热门搜索失败: %w", err)
+	}
+	return stats, nil
+}
+
+// ZeroResults 统计无结果关键词
+// 以最近一次搜索的结果为准，补充内容后重新搜到结果的关键词不再出现在报告中
+func (r *searchLogRepository) ZeroResults(since time.Time, offset, limit int) ([]*KeywordStat, int64, error) {
+	latest := r.db.Model(&model.SearchLog{}).
+		Select("normalized, MAX(id) AS id").
+		Where("created_at >= ? AND normalized <> ''", since).
+		Group("normalized")
+	zero := r.db.Table("search_logs AS l").
+		Joins("JOIN (?) AS latest ON latest.id = l.id", latest).
+		Where("l.results_count = 0").
+		Select("l.normalized")
+
+	inReport := func() *gorm.DB {
+		return r.db.Model(&model.SearchLog{}).Where("created_at >= ? AND normalized IN (?)", since, zero)
+	}
+
+	var total int64
+	if err := inReport().Distinct("normalized").Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计无结果搜索失败: %w", err)
+	}
+
+	var stats []*KeywordStat
+	err := inReport().
+		Select("normalized AS keyword, COUNT(*) AS searches, " + searcherExpr + " AS searchers, MAX(created_at) AS last_searched_at").
+		Group("normalized").
+		Order("searches DESC, last_searched_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("统计无结果搜索失败: %w", err)
+	}
+	return stats, total, nil
+}
+
+// DeleteBefore 删除指定时间之前的搜索记录
+func (r *searchLogRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&model.SearchLog{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("清理搜索记录失败: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
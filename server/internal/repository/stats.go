@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// visitorExpr 区分访客：登录用户按用户ID，游客按访客标识，没有访客标识时按IP
+const visitorExpr = "COUNT(DISTINCT COALESCE(CONCAT('u', user_id), CONCAT('v', NULLIF(visitor_id, '')), CONCAT('i', ip_address)))"
+
+// referrerHostExpr 从来源页面URL中取出域名（去掉协议、路径、端口和查询参数）
+// 查询参数的分隔符 ? 与占位符冲突，作为参数传入
+const referrerHostExpr = "LOWER(SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(referer, '://', -1), '/', 1), ?, 1), ':', 1))"
+
+// dayFormat 按天分组时的日期格式
+const dayFormat = "%Y-%m-%d"
+
+// SiteTotals 站点总量统计
+type SiteTotals struct {
+	Users            int64            `json:"users"`            // 用户总数
+	ActiveUsers      int64            `json:"activeUsers"`      // 状态正常的用户数
+	Articles         int64            `json:"articles"`         // 文章总数（不含已删除）
+	ArticlesByStatus map[string]int64 `json:"articlesByStatus"` // 各状态文章数
+	Comments         int64            `json:"comments"`         // 评论总数（不含已删除）
+	CommentsByStatus map[string]int64 `json:"commentsByStatus"` // 各审核状态评论数
+	MediaFiles       int64            `json:"mediaFiles"`       // 媒体文件数
+	MediaBytes       uint64           `json:"mediaBytes"`       // 媒体文件总大小（字节）
+	TotalViews       int64            `json:"totalViews"`       // 文章累计浏览量
+	TotalLikes       int64            `json:"totalLikes"`       // 文章累计点赞数
+}
+
+// DateCount 某天的数量
+type DateCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
+// ViewDay 某天的浏览量和访客数
+type ViewDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// ArticleViewStat 文章在时间范围内的浏览统计
+type ArticleViewStat struct {
+	ArticleID uint   `json:"articleId"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Views     int64  `json:"views"`
+	Visitors  int64  `json:"visitors"`
+}
+
+// ReferrerStat 来源站点统计
+type ReferrerStat struct {
+	Host     string `json:"host"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// StatsRepository 后台统计仓库接口，时间范围均为 [from, to)
+type StatsRepository interface {
+	SiteTotals() (*SiteTotals, error)
+	ViewsByDay(from, to time.Time) ([]*ViewDay, error)
+	// UniqueVisitors 时间范围内的访客数（同一访客多天浏览只计一次）
+	UniqueVisitors(from, to time.Time) (int64, error)
+	CommentsByDay(from, to time.Time) ([]*DateCount, error)
+	UsersByDay(from, to time.Time) ([]*DateCount, error)
+	TopArticles(from, to time.Time, limit int) ([]*ArticleViewStat, error)
+	TopReferrers(from, to time.Time, limit int) ([]*ReferrerStat, error)
+	// TopSearches 时间范围内搜索次数最多的关键词（含无结果的搜索）
+	TopSearches(from, to time.Time, limit int) ([]*KeywordStat, error)
+}
+
+// statsRepository 后台统计仓库实现
+type statsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository 创建后台统计仓库实例
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// statusCount 按状态分组的数量
+type statusCount struct {
+	Status string
+	Count  int64
+}
+
+// SiteTotals 统计站点总量
+func (r *statsRepository) SiteTotals() (*SiteTotals, error) {
+	totals := &SiteTotals{
+		ArticlesByStatus: map[string]int64{},
+		CommentsByStatus: map[string]int64{},
+	}
+	for _, status := range []model.ArticleStatus{model.ArticleStatusDraft, model.ArticleStatusPublished, model.ArticleStatusScheduled, model.ArticleStatusPrivate, model.ArticleStatusArchived} {
+		totals.ArticlesByStatus[string(status)] = 0
+	}
+	for _, status := range []model.CommentStatus{model.CommentStatusPending, model.CommentStatusApproved, model.CommentStatusRejected, model.CommentStatusSpam, model.CommentStatusTrash} {
+		totals.CommentsByStatus[string(status)] = 0
+	}
+
+	var users struct {
+		Total  int64
+		Active int64
+	}
+	if err := r.db.Model(&model.User{}).Select("COUNT(*) AS total, COALESCE(SUM(status = 1), 0) AS active").Scan(&users).Error; err != nil {
+		return nil, fmt.Errorf("统计用户数失败: %w", err)
+	}
+	totals.Users, totals.ActiveUsers = users.Total, users.Active
+
+	var articles []statusCount
+	if err := r.db.Model(&model.Article{}).Select("status, COUNT(*) AS count").Group("status").Scan(&articles).Error; err != nil {
+		return nil, fmt.Errorf("统计文章数失败: %w", err)
+	}
+	for _, item := range articles {
+		totals.ArticlesByStatus[item.Status] = item.Count
+		totals.Articles += item.Count
+	}
+
+	var engagement struct {
+		Views int64
+		Likes int64
+	}
+	if err := r.db.Model(&model.Article{}).Select("COALESCE(SUM(view_count), 0) AS views, COALESCE(SUM(like_count), 0) AS likes").Scan(&engagement).Error; err != nil {
+		return nil, fmt.Errorf("统计浏览量失败: %w", err)
+	}
+	totals.TotalViews, totals.TotalLikes = engagement.Views, engagement.Likes
+
+	var comments []statusCount
+	if err := r.db.Model(&model.Comment{}).Select("status, COUNT(*) AS count").Group("status").Scan(&comments).Error; err != nil {
+		return nil, fmt.Errorf("统计评论数失败: %w", err)
+	}
+	for _, item := range comments {
+		totals.CommentsByStatus[item.Status] = item.Count
+		totals.Comments += item.Count
+	}
+
+	var media struct {
+		Files int64
+		Bytes uint64
+	}
+	if err := r.db.Model(&model.MediaFile{}).Select("COUNT(*) AS files, COALESCE(SUM(file_size), 0) AS bytes").Scan(&media).Error; err != nil {
+		return nil, fmt.Errorf("统计媒体文件失败: %w", err)
+	}
+	totals.MediaFiles, totals.MediaBytes = media.Files, media.Bytes
+
+	return totals, nil
+}
+
+// ViewsByDay 按天统计浏览量和访客数，只含有浏览的日期
+func (r *statsRepository) ViewsByDay(from, to time.Time) ([]*ViewDay, error) {
+	var days []*ViewDay
+	err := r.db.Model(&model.ArticleView{}).
+		Select("DATE_FORMAT(view_date, ?) AS date, SUM(view_count) AS views, "+visitorExpr+" AS visitors", dayFormat).
+		Where("view_date >= ? AND view_date < ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("date").
+		Order("date ASC").
+		Scan(&days).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计每日浏览量失败: %w", err)
+	}
+	return days, nil
+}
+
+// UniqueVisitors 统计时间范围内的访客数
+func (r *statsRepository) UniqueVisitors(from, to time.Time) (int64, error) {
+	var visitors int64
+	err := r.db.Model(&model.ArticleView{}).
+		Select(visitorExpr).
+		Where("view_date >= ? AND view_date < ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Scan(&visitors).Error
+	if err != nil {
+		return 0, fmt.Errorf("统计访客数失败: %w", err)
+	}
+	return visitors, nil
+}
+
+// CommentsByDay 按天统计新增评论数（不含已删除），只含有评论的日期
+func (r *statsRepository) CommentsByDay(from, to time.Time) ([]*DateCount, error) {
+	days, err := r.countByDay(r.db.Model(&model.Comment{}), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计每日评论数失败: %w", err)
+	}
+	return days, nil
+}
+
+// UsersByDay 按天统计新注册用户数，只含有注册的日期
+func (r *statsRepository) UsersByDay(from, to time.Time) ([]*DateCount, error) {
+	days, err := r.countByDay(r.db.Model(&model.User{}), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计每日新用户数失败: %w", err)
+	}
+	return days, nil
+}
+
+// countByDay 按创建日期统计数量
+func (r *statsRepository) countByDay(query *gorm.DB, from, to time.Time) ([]*DateCount, error) {
+	var days []*DateCount
+	err := query.Select("DATE_FORMAT(created_at, ?) AS date, COUNT(*) AS count", dayFormat).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("date").
+		Order("date ASC").
+		Scan(&days).Error
+	return days, err
+}
+
+// TopArticles 统计时间范围内浏览量最高的文章（不含已删除的文章）
+func (r *statsRepository) TopArticles(from, to time.Time, limit int) ([]*ArticleViewStat, error) {
+	var stats []*ArticleViewStat
+	err := r.db.Table("article_views").
+		Select("articles.id AS article_id, articles.title, articles.slug, SUM(article_views.view_count) AS views, "+
+			"COUNT(DISTINCT COALESCE(CONCAT('u', article_views.user_id), CONCAT('v', NULLIF(article_views.visitor_id, '')), CONCAT('i', article_views.ip_address))) AS visitors").
+		Joins("JOIN articles ON articles.id = article_views.article_id AND articles.deleted_at IS NULL").
+		Where("article_views.view_date >= ? AND article_views.view_date < ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("articles.id, articles.title, articles.slug").
+		Order("views DESC, visitors DESC, articles.id DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计热门文章失败: %w", err)
+	}
+	return stats, nil
+}
+
+// TopReferrers 统计时间范围内带来浏览最多的来源站点
+func (r *statsRepository) TopReferrers(from, to time.Time, limit int) ([]*ReferrerStat, error) {
+	var stats []*ReferrerStat
+	err := r.db.Model(&model.ArticleView{}).
+		Select(referrerHostExpr+" AS host, SUM(view_count) AS views, "+visitorExpr+" AS visitors", "?").
+		Where("view_date >= ? AND view_date < ? AND referer <> ''", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("host").
+		Having("host <> ''").
+		Order("views DESC, visitors DESC, host ASC").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计来源站点失败: %w", err)
+	}
+	return stats, nil
+}
+
+// TopSearches 统计时间范围内的热门搜索词
+func (r *statsRepository) TopSearches(from, to time.Time, limit int) ([]*KeywordStat, error) {
+	var stats []*KeywordStat
+	err := r.db.Model(&model.SearchLog{}).
+		Select("normalized AS keyword, COUNT(*) AS searches, "+searcherExpr+" AS searchers, MAX(created_at) AS last_searched_at").
+		Where("created_at >= ? AND created_at < ? AND normalized <> ''", from, to).
+		Group("normalized").
+		Order("searches DESC, searchers DESC, last_searched_at DESC").
+		Limit(limit).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, fmt.Errorf("统计热门搜索词失败: %w", err)
+	}
+	return stats, nil
+}
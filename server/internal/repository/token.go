@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"MyBlog/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// TokenRepository 个人访问令牌仓库接口
+type TokenRepository interface {
+	Create(token *model.PersonalAccessToken) error
+	GetByHash(tokenHash string) (*model.PersonalAccessToken, error)
+	ListByUser(userID uint) ([]*model.PersonalAccessToken, error)
+	Revoke(userID, id uint) error
+	UpdateLastUsed(id uint, ip string, usedAt time.Time) error
+}
+
+// tokenRepository 个人访问令牌仓库实现
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository 创建个人访问令牌仓库实例
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+// Create 创建令牌
+func (r *tokenRepository) Create(token *model.PersonalAccessToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("创建访问令牌失败: %w", err)
+	}
+	return nil
+}
+
+// GetByHash 根据令牌哈希获取令牌
+func (r *tokenRepository) GetByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	var token model.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("访问令牌不存在")
+		}
+		return nil, fmt.Errorf("查询访问令牌失败: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByUser 获取用户的全部令牌
+func (r *tokenRepository) ListByUser(userID uint) ([]*model.PersonalAccessToken, error) {
+	var tokens []*model.PersonalAccessToken
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("查询访问令牌列表失败: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke 撤销用户的指定令牌
+func (r *tokenRepository) Revoke(userID, id uint) error {
+	result := r.db.Model(&model.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("撤销访问令牌失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("访问令牌不存在或已撤销")
+	}
+	return nil
+}
+
+// UpdateLastUsed 更新令牌最后使用信息
+func (r *tokenRepository) UpdateLastUsed(id uint, ip string, usedAt time.Time) error {
+	err := r.db.Model(&model.PersonalAccessToken{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_used_at": usedAt,
+		"last_used_ip": ip,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("更新访问令牌使用信息失败: %w", err)
+	}
+	return nil
+}
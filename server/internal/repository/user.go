@@ -25,6 +25,8 @@ type User struct {
 	CreatedAt time.Time         `json:"createdAt" gorm:"type:datetime(3)"`
 	UpdatedAt time.Time         `json:"updatedAt" gorm:"type:datetime(3)"`
 	DeletedAt gorm.DeletedAt    `json:"-" gorm:"index"`
+
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt" gorm:"type:datetime(3)"`
 }
 
 // CreateUserRequest 创建用户请求
@@ -33,7 +35,7 @@ type CreateUserRequest struct {
 	Email    string            `json:"email" binding:"required,email"`
 	Password string            `json:"password" binding:"required,min=6,max=100"`
 	Nickname string            `json:"nickname" binding:"max=50"`
-	Role     string            `json:"role" binding:"omitempty,oneof=user editor admin superadmin"`
+	Role     string            `json:"role" binding:"omitempty,max=20"` // 角色有效性由RBAC服务校验
 	Birthday datetime.JSONDate `json:"birthday" binding:"omitempty"`
 }
 
@@ -44,7 +46,7 @@ type UpdateUserRequest struct {
 	Email    string            `json:"email" binding:"required,email"`
 	Password string            `json:"password" binding:"omitempty,min=6,max=100"` // 可选，留空则不更新
 	Nickname string            `json:"nickname" binding:"max=50"`
-	Role     string            `json:"role" binding:"omitempty,oneof=user editor admin superadmin"`
+	Role     string            `json:"role" binding:"omitempty,max=20"` // 角色有效性由RBAC服务校验
 	Birthday datetime.JSONDate `json:"birthday" binding:"omitempty"`
 	Status   int               `json:"status" binding:"omitempty,oneof=0 1"` // 用户状态，可选
 }
@@ -1,8 +1,11 @@
 package router
 
 import (
+	"time"
+
 	"MyBlog/internal/handler"
 	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
 	"MyBlog/internal/repository"
 	"MyBlog/internal/service"
 
@@ -15,6 +18,7 @@ type ArticleRoutes struct {
 	jwtService     service.JWTService
 	userRepo       repository.UserRepository
 	rbacService    service.RBACService
+	auditService   service.AuditService
 }
 
 // NewArticleRoutes 创建文章路由实例
@@ -23,31 +27,46 @@ func NewArticleRoutes(
 	jwtService service.JWTService,
 	userRepo repository.UserRepository,
 	rbacService service.RBACService,
+	auditService service.AuditService,
 ) *ArticleRoutes {
 	return &ArticleRoutes{
 		articleHandler: articleHandler,
 		jwtService:     jwtService,
 		userRepo:       userRepo,
 		rbacService:    rbacService,
+		auditService:   auditService,
 	}
 }
 
 // RegisterRoutes 注册文章相关路由
 func (ar *ArticleRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 	// 公开访问的文章路由
+	// 可选认证：登录用户可以查看自己有权访问的未发布文章（作者、协作者、管理员）
 	publicArticles := rg.Group("/articles")
+	publicArticles.Use(middleware.OptionalAuth(ar.jwtService))
+	// 解锁密码按IP限流，防止暴力破解
+	unlockLimit := middleware.RateLimit(10, time.Minute)
 	{
 		// 文章查看相关（无需登录）
-		publicArticles.POST("/get", ar.articleHandler.GetArticle)                   // 根据ID获取文章
-		publicArticles.POST("/getBySlug", ar.articleHandler.GetArticleBySlug)       // 根据Slug获取文章
-		publicArticles.POST("/list", ar.articleHandler.GetArticleList)              // 文章列表（支持筛选）
-		publicArticles.POST("/byAuthor", ar.articleHandler.GetArticlesByAuthor)     // 作者文章列表
-		publicArticles.POST("/byCategory", ar.articleHandler.GetArticlesByCategory) // 分类文章列表
-		publicArticles.POST("/byTag", ar.articleHandler.GetArticlesByTag)           // 标签文章列表
-		publicArticles.POST("/search", ar.articleHandler.SearchArticles)            // 搜索文章
-		publicArticles.POST("/popular", ar.articleHandler.GetPopularArticles)       // 热门文章
-		publicArticles.POST("/recent", ar.articleHandler.GetRecentArticles)         // 最新文章
-		publicArticles.POST("/related", ar.articleHandler.GetRelatedArticles)       // 相关文章
+		publicArticles.POST("/get", ar.articleHandler.GetArticle)                    // 根据ID获取文章
+		publicArticles.POST("/getBySlug", ar.articleHandler.GetArticleBySlug)        // 根据Slug获取文章
+		publicArticles.POST("/unlock", unlockLimit, ar.articleHandler.UnlockArticle) // 输入密码解锁文章
+		publicArticles.POST("/list", ar.articleHandler.GetArticleList)               // 文章列表（支持筛选）
+		publicArticles.POST("/byAuthor", ar.articleHandler.GetArticlesByAuthor)      // 作者文章列表
+		publicArticles.POST("/byCategory", ar.articleHandler.GetArticlesByCategory)  // 分类文章列表
+		publicArticles.POST("/byTag", ar.articleHandler.GetArticlesByTag)            // 标签文章列表
+		publicArticles.POST("/search", ar.articleHandler.SearchArticles)             // 搜索文章
+		publicArticles.POST("/popular", ar.articleHandler.GetPopularArticles)        // 热门文章
+		publicArticles.POST("/recent", ar.articleHandler.GetRecentArticles)          // 最新文章
+		publicArticles.POST("/related", ar.articleHandler.GetRelatedArticles)        // 相关文章
+		publicArticles.POST("/trending", ar.articleHandler.GetTrendingArticles)      // 趋势文章（按时间衰减的热度）
+
+		// 归档统计（仅已发布文章，结果有缓存）
+		publicArticles.POST("/archives", ar.articleHandler.GetArchives)                 // 按年月归档的文章数
+		publicArticles.POST("/archives/month", ar.articleHandler.GetArchiveMonth)       // 某月的文章列表
+		publicArticles.POST("/archives/calendar", ar.articleHandler.GetArchiveCalendar) // 某年每天的发布数
+
+		publicArticles.POST("/collaborators/list", ar.articleHandler.GetCollaborators) // 文章协作者列表
 
 		// 文章统计（无需登录）
 		publicArticles.POST("/view", ar.articleHandler.ViewArticle) // 记录浏览量
@@ -57,23 +76,55 @@ func (ar *ArticleRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 	authArticles := rg.Group("/articles")
 	authArticles.Use(middleware.Auth(ar.jwtService))
 	{
-		// 文章互动操作（需要登录）
-		authArticles.POST("/like", ar.articleHandler.LikeArticle)             // 点赞文章
-		authArticles.POST("/unlike", ar.articleHandler.UnlikeArticle)         // 取消点赞
-		authArticles.POST("/bookmark", ar.articleHandler.BookmarkArticle)     // 收藏文章
-		authArticles.POST("/unbookmark", ar.articleHandler.UnbookmarkArticle) // 取消收藏
+		// 文章互动操作（需要账号登录，没有对应的授权范围，不接受个人访问令牌）
+		interactions := authArticles.Group("")
+		interactions.Use(middleware.DenyPersonalToken())
+		{
+			interactions.POST("/like", ar.articleHandler.LikeArticle)             // 点赞文章
+			interactions.POST("/unlike", ar.articleHandler.UnlikeArticle)         // 取消点赞
+			interactions.POST("/bookmark", ar.articleHandler.BookmarkArticle)     // 收藏文章
+			interactions.POST("/unbookmark", ar.articleHandler.UnbookmarkArticle) // 取消收藏
+		}
+
+		// 以下接口的权限在服务层按文章校验，个人访问令牌取授权范围与角色权限的交集
+
+		// 协作
+		authArticles.POST("/shared", ar.articleHandler.GetSharedArticles)                                                          // 共享给我的文章
+		authArticles.POST("/collaborators/invite", ar.audit(model.ActionInviteCollaborator), ar.articleHandler.InviteCollaborator) // 邀请协作者
+		authArticles.POST("/collaborators/remove", ar.audit(model.ActionRemoveCollaborator), ar.articleHandler.RemoveCollaborator) // 移除协作者
+
+		// 修订历史
+		authArticles.POST("/revisions/list", ar.articleHandler.ListRevisions)                                             // 修订列表
+		authArticles.POST("/revisions/diff", ar.articleHandler.DiffRevisions)                                             // 比较修订
+		authArticles.POST("/revisions/restore", ar.audit(model.ActionRestoreRevision), ar.articleHandler.RestoreRevision) // 恢复修订
+
+		// 自动保存草稿（只保存当前用户的草稿缓冲区，不修改正式文章）
+		authArticles.POST("/drafts/save", ar.articleHandler.SaveDraft)       // 自动保存
+		authArticles.POST("/drafts/get", ar.articleHandler.GetDraft)         // 获取草稿
+		authArticles.POST("/drafts/discard", ar.articleHandler.DiscardDraft) // 丢弃草稿
+
+		// 编辑锁（提示性锁）
+		authArticles.POST("/lock/acquire", ar.articleHandler.AcquireEditLock)     // 获取编辑锁
+		authArticles.POST("/lock/heartbeat", ar.articleHandler.HeartbeatEditLock) // 心跳续期
+		authArticles.POST("/lock/release", ar.articleHandler.ReleaseEditLock)     // 释放编辑锁
+		authArticles.POST("/lock/status", ar.articleHandler.GetEditLock)          // 查询编辑锁
+
+		// 预览链接（仅作者和管理员）
+		authArticles.POST("/previews/create", ar.audit(model.ActionCreatePreview), ar.articleHandler.CreatePreview) // 创建预览链接
+		authArticles.POST("/previews/list", ar.articleHandler.ListPreviews)                                         // 预览链接列表
+		authArticles.POST("/previews/revoke", ar.audit(model.ActionRevokePreview), ar.articleHandler.RevokePreview) // 撤销预览链接
 
 		// 文章管理操作（需要编辑权限）
 		editorArticles := authArticles.Group("")
 		editorArticles.Use(middleware.RequirePermission(ar.jwtService, ar.userRepo, ar.rbacService, service.PermissionArticleCreate))
 		{
-			editorArticles.POST("/create", ar.articleHandler.CreateArticle)       // 创建文章
-			editorArticles.POST("/update", ar.articleHandler.UpdateArticle)       // 更新文章
-			editorArticles.POST("/delete", ar.articleHandler.DeleteArticle)       // 删除文章（软删除）
-			editorArticles.POST("/publish", ar.articleHandler.PublishArticle)     // 发布文章
-			editorArticles.POST("/unpublish", ar.articleHandler.UnpublishArticle) // 取消发布
-			editorArticles.POST("/archive", ar.articleHandler.ArchiveArticle)     // 归档文章
-			editorArticles.POST("/private", ar.articleHandler.SetArticlePrivate)  // 设为私有
+			editorArticles.POST("/create", ar.audit(model.ActionCreateArticle), ar.articleHandler.CreateArticle)          // 创建文章
+			editorArticles.POST("/update", ar.audit(model.ActionUpdateArticle), ar.articleHandler.UpdateArticle)          // 更新文章
+			editorArticles.POST("/delete", ar.audit(model.ActionDeleteArticle), ar.articleHandler.DeleteArticle)          // 删除文章（软删除）
+			editorArticles.POST("/publish", ar.audit(model.ActionPublishArticle), ar.articleHandler.PublishArticle)       // 发布文章
+			editorArticles.POST("/unpublish", ar.audit(model.ActionUnpublishArticle), ar.articleHandler.UnpublishArticle) // 取消发布
+			editorArticles.POST("/archive", ar.audit(model.ActionArchiveArticle), ar.articleHandler.ArchiveArticle)       // 归档文章
+			editorArticles.POST("/private", ar.audit(model.ActionPrivateArticle), ar.articleHandler.SetArticlePrivate)    // 设为私有
 		}
 	}
 
@@ -81,12 +132,19 @@ func (ar *ArticleRoutes) RegisterRoutes(rg *gin.RouterGroup) {
 	adminArticles := rg.Group("/admin/articles")
 	adminArticles.Use(middleware.RequirePermission(ar.jwtService, ar.userRepo, ar.rbacService, service.PermissionArticleManage))
 	{
-		adminArticles.POST("/list", ar.articleHandler.GetArticleList)        // 管理员文章列表（所有状态）
-		adminArticles.POST("/update", ar.articleHandler.UpdateArticle)       // 管理员更新任意文章
-		adminArticles.POST("/delete", ar.articleHandler.DeleteArticle)       // 管理员删除任意文章
-		adminArticles.POST("/publish", ar.articleHandler.PublishArticle)     // 管理员发布任意文章
-		adminArticles.POST("/unpublish", ar.articleHandler.UnpublishArticle) // 管理员取消发布
-		adminArticles.POST("/archive", ar.articleHandler.ArchiveArticle)     // 管理员归档文章
-		adminArticles.POST("/private", ar.articleHandler.SetArticlePrivate)  // 管理员设为私有
+		adminArticles.POST("/list", ar.articleHandler.GetArticleList)                                                // 管理员文章列表（所有状态）
+		adminArticles.POST("/update", ar.audit(model.ActionUpdateArticle), ar.articleHandler.UpdateArticle)          // 管理员更新任意文章
+		adminArticles.POST("/delete", ar.audit(model.ActionDeleteArticle), ar.articleHandler.DeleteArticle)          // 管理员删除任意文章
+		adminArticles.POST("/publish", ar.audit(model.ActionPublishArticle), ar.articleHandler.PublishArticle)       // 管理员发布任意文章
+		adminArticles.POST("/unpublish", ar.audit(model.ActionUnpublishArticle), ar.articleHandler.UnpublishArticle) // 管理员取消发布
+		adminArticles.POST("/archive", ar.audit(model.ActionArchiveArticle), ar.articleHandler.ArchiveArticle)       // 管理员归档文章
+		adminArticles.POST("/private", ar.audit(model.ActionPrivateArticle), ar.articleHandler.SetArticlePrivate)    // 管理员设为私有
+		adminArticles.POST("/rerender", ar.audit(model.ActionRerenderArticles), ar.articleHandler.RerenderArticles)  // 重新渲染文章内容
+		adminArticles.POST("/reindex", ar.audit(model.ActionReindexArticles), ar.articleHandler.ReindexArticles)     // 重建搜索索引
 	}
 }
+
+// audit 创建文章资源的审计中间件
+func (ar *ArticleRoutes) audit(action string) gin.HandlerFunc {
+	return middleware.Audit(ar.auditService, action, model.ResourceArticle)
+}
@@ -0,0 +1,38 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/repository"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRoutes 审计日志路由模块
+type AuditRoutes struct {
+	handler     AuditHandlerInterface
+	jwtService  service.JWTService
+	userRepo    repository.UserRepository
+	rbacService service.RBACService
+}
+
+// NewAuditRoutes 创建审计日志路由模块
+func NewAuditRoutes(handler AuditHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) *AuditRoutes {
+	return &AuditRoutes{
+		handler:     handler,
+		jwtService:  jwtService,
+		userRepo:    userRepo,
+		rbacService: rbacService,
+	}
+}
+
+// RegisterRoutes 注册审计日志相关路由
+func (ar *AuditRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 审计日志需要系统日志权限
+	logGroup := api.Group("/admin/logs")
+	logGroup.Use(middleware.RequirePermission(ar.jwtService, ar.userRepo, ar.rbacService, service.PermissionSystemLogs))
+	{
+		logGroup.POST("/list", ar.handler.ListLogs)
+		logGroup.POST("/export", ar.handler.ExportLogs)
+	}
+}
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthRoutes 第三方登录路由模块
+type OAuthRoutes struct {
+	handler    OAuthHandlerInterface
+	jwtService service.JWTService
+}
+
+// NewOAuthRoutes 创建第三方登录路由模块
+func NewOAuthRoutes(handler OAuthHandlerInterface, jwtService service.JWTService) *OAuthRoutes {
+	return &OAuthRoutes{
+		handler:    handler,
+		jwtService: jwtService,
+	}
+}
+
+// RegisterRoutes 注册第三方登录相关路由
+func (or *OAuthRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 第三方登录流程（无需认证）
+	oauthGroup := api.Group("/auth/oauth")
+	{
+		oauthGroup.POST("/providers", or.handler.ListProviders)
+		oauthGroup.POST("/authorize", or.handler.Authorize)
+		oauthGroup.POST("/callback", or.handler.Callback)
+	}
+
+	// 身份绑定管理（需要认证）
+	identityGroup := api.Group("/users/identities")
+	identityGroup.Use(middleware.Auth(or.jwtService), middleware.DenyPersonalToken())
+	{
+		identityGroup.POST("/list", or.handler.ListIdentities)
+		identityGroup.POST("/link", or.handler.LinkIdentity)
+		identityGroup.POST("/unlink", or.handler.UnlinkIdentity)
+	}
+}
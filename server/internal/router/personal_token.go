@@ -0,0 +1,37 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalTokenRoutes 个人访问令牌路由模块
+type PersonalTokenRoutes struct {
+	handler      PersonalTokenHandlerInterface
+	jwtService   service.JWTService
+	auditService service.AuditService
+}
+
+// NewPersonalTokenRoutes 创建个人访问令牌路由模块
+func NewPersonalTokenRoutes(handler PersonalTokenHandlerInterface, jwtService service.JWTService, auditService service.AuditService) *PersonalTokenRoutes {
+	return &PersonalTokenRoutes{
+		handler:      handler,
+		jwtService:   jwtService,
+		auditService: auditService,
+	}
+}
+
+// RegisterRoutes 注册个人访问令牌相关路由
+func (pr *PersonalTokenRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 令牌管理只允许账号登录后操作，不能用令牌管理令牌
+	tokenGroup := api.Group("/users/tokens")
+	tokenGroup.Use(middleware.Auth(pr.jwtService), middleware.DenyPersonalToken())
+	{
+		tokenGroup.POST("/create", middleware.Audit(pr.auditService, model.ActionCreateToken, model.ResourceToken), pr.handler.CreateToken)
+		tokenGroup.POST("/list", pr.handler.ListTokens)
+		tokenGroup.POST("/revoke", middleware.Audit(pr.auditService, model.ActionRevokeToken, model.ResourceToken), pr.handler.RevokeToken)
+	}
+}
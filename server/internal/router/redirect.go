@@ -0,0 +1,51 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedirectRoutes 跳转规则路由模块
+type RedirectRoutes struct {
+	handler      RedirectHandlerInterface
+	jwtService   service.JWTService
+	userRepo     repository.UserRepository
+	rbacService  service.RBACService
+	auditService service.AuditService
+}
+
+// NewRedirectRoutes 创建跳转规则路由模块
+func NewRedirectRoutes(handler RedirectHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, auditService service.AuditService) *RedirectRoutes {
+	return &RedirectRoutes{
+		handler:      handler,
+		jwtService:   jwtService,
+		userRepo:     userRepo,
+		rbacService:  rbacService,
+		auditService: auditService,
+	}
+}
+
+// RegisterRoutes 注册跳转规则相关路由
+func (rr *RedirectRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 查询跳转目标（无需登录）
+	api.POST("/redirects/resolve", rr.handler.ResolveRedirect)
+
+	// 跳转规则管理需要系统配置权限
+	redirectGroup := api.Group("/admin/redirects")
+	redirectGroup.Use(middleware.RequirePermission(rr.jwtService, rr.userRepo, rr.rbacService, service.PermissionSystemConfig))
+	{
+		redirectGroup.POST("/list", rr.handler.ListRedirects)
+		redirectGroup.POST("/create", rr.audit(model.ActionCreateRedirect), rr.handler.CreateRedirect)
+		redirectGroup.POST("/update", rr.audit(model.ActionUpdateRedirect), rr.handler.UpdateRedirect)
+		redirectGroup.POST("/delete", rr.audit(model.ActionDeleteRedirect), rr.handler.DeleteRedirect)
+	}
+}
+
+// audit 创建跳转规则资源的审计中间件
+func (rr *RedirectRoutes) audit(action string) gin.HandlerFunc {
+	return middleware.Audit(rr.auditService, action, model.ResourceRedirect)
+}
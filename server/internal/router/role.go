@@ -0,0 +1,44 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleRoutes 角色管理路由模块
+type RoleRoutes struct {
+	handler      RoleHandlerInterface
+	jwtService   service.JWTService
+	userRepo     repository.UserRepository
+	rbacService  service.RBACService
+	auditService service.AuditService
+}
+
+// NewRoleRoutes 创建角色管理路由模块
+func NewRoleRoutes(handler RoleHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, auditService service.AuditService) *RoleRoutes {
+	return &RoleRoutes{
+		handler:      handler,
+		jwtService:   jwtService,
+		userRepo:     userRepo,
+		rbacService:  rbacService,
+		auditService: auditService,
+	}
+}
+
+// RegisterRoutes 注册角色管理相关路由
+func (rr *RoleRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 角色管理仅限超级管理员
+	roleGroup := api.Group("/admin/roles")
+	roleGroup.Use(middleware.RequireSuperAdmin(rr.jwtService, rr.userRepo, rr.rbacService))
+	{
+		roleGroup.POST("/list", rr.handler.ListRoles)
+		roleGroup.POST("/permissions", rr.handler.ListPermissions)
+		roleGroup.POST("/create", middleware.Audit(rr.auditService, model.ActionCreateRole, model.ResourceRole), rr.handler.CreateRole)
+		roleGroup.POST("/update", middleware.Audit(rr.auditService, model.ActionUpdateRole, model.ResourceRole), rr.handler.UpdateRole)
+		roleGroup.POST("/delete", middleware.Audit(rr.auditService, model.ActionDeleteRole, model.ResourceRole), rr.handler.DeleteRole)
+	}
+}
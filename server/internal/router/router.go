@@ -46,25 +46,83 @@ func (r *Router) SetupRoutes(deps *Dependencies) {
 	// 注册用户相关路由
 	if deps.UserHandler != nil {
 		userHandler := deps.UserHandler.(UserHandlerInterface)
-		userRoutes := NewUserRoutes(userHandler, deps.JWTService, deps.UserRepository)
+		userRoutes := NewUserRoutes(userHandler, deps.JWTService, deps.UserRepository, deps.RBACService, deps.AuditService)
 		userRoutes.RegisterRoutes(api)
 	}
 
 	// 注册文章相关路由
 	if deps.ArticleHandler != nil {
 		articleHandler := deps.ArticleHandler.(ArticleHandlerInterface)
-		articleRoutes := NewArticleRoutes(articleHandler, deps.JWTService, deps.UserRepository, deps.RBACService)
+		articleRoutes := NewArticleRoutes(articleHandler, deps.JWTService, deps.UserRepository, deps.RBACService, deps.AuditService)
 		articleRoutes.RegisterRoutes(api)
 	}
+
+	// 注册第三方登录相关路由
+	if deps.OAuthHandler != nil {
+		oauthHandler := deps.OAuthHandler.(OAuthHandlerInterface)
+		oauthRoutes := NewOAuthRoutes(oauthHandler, deps.JWTService)
+		oauthRoutes.RegisterRoutes(api)
+	}
+
+	// 注册个人访问令牌相关路由
+	if deps.PersonalTokenHandler != nil {
+		tokenHandler := deps.PersonalTokenHandler.(PersonalTokenHandlerInterface)
+		tokenRoutes := NewPersonalTokenRoutes(tokenHandler, deps.JWTService, deps.AuditService)
+		tokenRoutes.RegisterRoutes(api)
+	}
+
+	// 注册角色管理路由
+	if deps.RoleHandler != nil {
+		roleHandler := deps.RoleHandler.(RoleHandlerInterface)
+		roleRoutes := NewRoleRoutes(roleHandler, deps.JWTService, deps.UserRepository, deps.RBACService, deps.AuditService)
+		roleRoutes.RegisterRoutes(api)
+	}
+
+	// 注册审计日志路由
+	if deps.AuditHandler != nil {
+		auditHandler := deps.AuditHandler.(AuditHandlerInterface)
+		auditRoutes := NewAuditRoutes(auditHandler, deps.JWTService, deps.UserRepository, deps.RBACService)
+		auditRoutes.RegisterRoutes(api)
+	}
+
+	// 注册跳转规则路由，未匹配的页面请求按规则跳转
+	if deps.RedirectHandler != nil {
+		redirectHandler := deps.RedirectHandler.(RedirectHandlerInterface)
+		redirectRoutes := NewRedirectRoutes(redirectHandler, deps.JWTService, deps.UserRepository, deps.RBACService, deps.AuditService)
+		redirectRoutes.RegisterRoutes(api)
+		r.engine.NoRoute(redirectHandler.ServeRedirect)
+	}
+
+	// 注册搜索建议与统计路由
+	if deps.SearchHandler != nil {
+		searchHandler := deps.SearchHandler.(SearchHandlerInterface)
+		searchRoutes := NewSearchRoutes(searchHandler, deps.JWTService, deps.UserRepository, deps.RBACService)
+		searchRoutes.RegisterRoutes(api)
+	}
+
+	// 注册后台统计路由
+	if deps.StatsHandler != nil {
+		statsHandler := deps.StatsHandler.(StatsHandlerInterface)
+		statsRoutes := NewStatsRoutes(statsHandler, deps.JWTService, deps.UserRepository, deps.RBACService)
+		statsRoutes.RegisterRoutes(api)
+	}
 }
 
 // Dependencies 依赖注入结构
 type Dependencies struct {
-	UserHandler    interface{}               // 用户处理器接口
-	ArticleHandler interface{}               // 文章处理器接口
-	JWTService     service.JWTService        // JWT服务
-	UserRepository repository.UserRepository // 用户仓库
-	RBACService    service.RBACService       // RBAC权限服务
+	UserHandler          interface{}               // 用户处理器接口
+	ArticleHandler       interface{}               // 文章处理器接口
+	OAuthHandler         interface{}               // 第三方登录处理器接口
+	PersonalTokenHandler interface{}               // 个人访问令牌处理器接口
+	RoleHandler          interface{}               // 角色管理处理器接口
+	AuditHandler         interface{}               // 审计日志处理器接口
+	RedirectHandler      interface{}               // 跳转规则处理器接口
+	SearchHandler        interface{}               // 搜索建议与统计处理器接口
+	StatsHandler         interface{}               // 后台统计处理器接口
+	JWTService           service.JWTService        // JWT服务
+	UserRepository       repository.UserRepository // 用户仓库
+	RBACService          service.RBACService       // RBAC权限服务
+	AuditService         service.AuditService      // 审计日志服务
 }
 
 // UserHandlerInterface 用户处理器接口
@@ -85,6 +143,7 @@ type ArticleHandlerInterface interface {
 	CreateArticle(c *gin.Context)
 	GetArticle(c *gin.Context)
 	GetArticleBySlug(c *gin.Context)
+	UnlockArticle(c *gin.Context)
 	UpdateArticle(c *gin.Context)
 	DeleteArticle(c *gin.Context)
 
@@ -99,6 +158,10 @@ type ArticleHandlerInterface interface {
 	GetPopularArticles(c *gin.Context)
 	GetRecentArticles(c *gin.Context)
 	GetRelatedArticles(c *gin.Context)
+	GetTrendingArticles(c *gin.Context)
+	GetArchives(c *gin.Context)
+	GetArchiveMonth(c *gin.Context)
+	GetArchiveCalendar(c *gin.Context)
 
 	// 互动操作
 	ViewArticle(c *gin.Context)
@@ -112,4 +175,93 @@ type ArticleHandlerInterface interface {
 	UnpublishArticle(c *gin.Context)
 	ArchiveArticle(c *gin.Context)
 	SetArticlePrivate(c *gin.Context)
+
+	// 协作者管理
+	InviteCollaborator(c *gin.Context)
+	RemoveCollaborator(c *gin.Context)
+	GetCollaborators(c *gin.Context)
+	GetSharedArticles(c *gin.Context)
+
+	// 修订历史
+	ListRevisions(c *gin.Context)
+	DiffRevisions(c *gin.Context)
+	RestoreRevision(c *gin.Context)
+
+	// 自动保存草稿
+	SaveDraft(c *gin.Context)
+	GetDraft(c *gin.Context)
+	DiscardDraft(c *gin.Context)
+
+	// 编辑锁
+	AcquireEditLock(c *gin.Context)
+	HeartbeatEditLock(c *gin.Context)
+	ReleaseEditLock(c *gin.Context)
+	GetEditLock(c *gin.Context)
+
+	// 预览链接
+	CreatePreview(c *gin.Context)
+	ListPreviews(c *gin.Context)
+	RevokePreview(c *gin.Context)
+
+	// 内容渲染
+	RerenderArticles(c *gin.Context)
+
+	// 搜索索引
+	ReindexArticles(c *gin.Context)
+}
+
+// OAuthHandlerInterface 第三方登录处理器接口
+type OAuthHandlerInterface interface {
+	ListProviders(c *gin.Context)  // POST /api/auth/oauth/providers
+	Authorize(c *gin.Context)      // POST /api/auth/oauth/authorize
+	Callback(c *gin.Context)       // POST /api/auth/oauth/callback
+	ListIdentities(c *gin.Context) // POST /api/users/identities/list
+	LinkIdentity(c *gin.Context)   // POST /api/users/identities/link
+	UnlinkIdentity(c *gin.Context) // POST /api/users/identities/unlink
+}
+
+// PersonalTokenHandlerInterface 个人访问令牌处理器接口
+type PersonalTokenHandlerInterface interface {
+	CreateToken(c *gin.Context) // POST /api/users/tokens/create
+	ListTokens(c *gin.Context)  // POST /api/users/tokens/list
+	RevokeToken(c *gin.Context) // POST /api/users/tokens/revoke
+}
+
+// RoleHandlerInterface 角色管理处理器接口
+type RoleHandlerInterface interface {
+	ListRoles(c *gin.Context)       // POST /api/admin/roles/list
+	ListPermissions(c *gin.Context) // POST /api/admin/roles/permissions
+	CreateRole(c *gin.Context)      // POST /api/admin/roles/create
+	UpdateRole(c *gin.Context)      // POST /api/admin/roles/update
+	DeleteRole(c *gin.Context)      // POST /api/admin/roles/delete
+}
+
+// AuditHandlerInterface 审计日志处理器接口
+type AuditHandlerInterface interface {
+	ListLogs(c *gin.Context)   // POST /api/admin/logs/list
+	ExportLogs(c *gin.Context) // POST /api/admin/logs/export
+}
+
+// RedirectHandlerInterface 跳转规则处理器接口
+type RedirectHandlerInterface interface {
+	ListRedirects(c *gin.Context)   // POST /api/admin/redirects/list
+	CreateRedirect(c *gin.Context)  // POST /api/admin/redirects/create
+	UpdateRedirect(c *gin.Context)  // POST /api/admin/redirects/update
+	DeleteRedirect(c *gin.Context)  // POST /api/admin/redirects/delete
+	ResolveRedirect(c *gin.Context) // POST /api/redirects/resolve
+	ServeRedirect(c *gin.Context)   // 未匹配路由（NoRoute）
+}
+
+// SearchHandlerInterface 搜索建议与统计处理器接口
+type SearchHandlerInterface interface {
+	Suggest(c *gin.Context)          // POST /api/search/suggest
+	HotSearches(c *gin.Context)      // POST /api/search/hot
+	ZeroResultReport(c *gin.Context) // POST /api/admin/search/zero-results
+}
+
+// StatsHandlerInterface 后台统计处理器接口
+type StatsHandlerInterface interface {
+	Overview(c *gin.Context)   // POST /api/admin/stats/overview
+	TimeSeries(c *gin.Context) // POST /api/admin/stats/timeseries
+	Top(c *gin.Context)        // POST /api/admin/stats/top
 }
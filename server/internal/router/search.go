@@ -0,0 +1,49 @@
+package router
+
+import (
+	"time"
+
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/repository"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchRoutes 搜索建议与统计路由模块
+type SearchRoutes struct {
+	handler     SearchHandlerInterface
+	jwtService  service.JWTService
+	userRepo    repository.UserRepository
+	rbacService service.RBACService
+}
+
+// NewSearchRoutes 创建搜索建议与统计路由模块
+func NewSearchRoutes(handler SearchHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) *SearchRoutes {
+	return &SearchRoutes{
+		handler:     handler,
+		jwtService:  jwtService,
+		userRepo:    userRepo,
+		rbacService: rbacService,
+	}
+}
+
+// RegisterRoutes 注册搜索建议与统计相关路由
+func (sr *SearchRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	// 搜索建议随输入频繁调用，按IP限流
+	suggestLimit := middleware.RateLimit(60, time.Minute)
+
+	// 搜索建议和热门搜索（无需登录）
+	searchGroup := api.Group("/search")
+	{
+		searchGroup.POST("/suggest", suggestLimit, sr.handler.Suggest)
+		searchGroup.POST("/hot", sr.handler.HotSearches)
+	}
+
+	// 无结果搜索报告需要系统统计权限
+	adminGroup := api.Group("/admin/search")
+	adminGroup.Use(middleware.RequirePermission(sr.jwtService, sr.userRepo, sr.rbacService, service.PermissionSystemStats))
+	{
+		adminGroup.POST("/zero-results", sr.handler.ZeroResultReport)
+	}
+}
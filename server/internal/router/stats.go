@@ -0,0 +1,38 @@
+package router
+
+import (
+	"MyBlog/internal/middleware"
+	"MyBlog/internal/repository"
+	"MyBlog/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsRoutes 后台统计路由模块
+type StatsRoutes struct {
+	handler     StatsHandlerInterface
+	jwtService  service.JWTService
+	userRepo    repository.UserRepository
+	rbacService service.RBACService
+}
+
+// NewStatsRoutes 创建后台统计路由模块
+func NewStatsRoutes(handler StatsHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService) *StatsRoutes {
+	return &StatsRoutes{
+		handler:     handler,
+		jwtService:  jwtService,
+		userRepo:    userRepo,
+		rbacService: rbacService,
+	}
+}
+
+// RegisterRoutes 注册后台统计相关路由，均需要系统统计权限
+func (sr *StatsRoutes) RegisterRoutes(api *gin.RouterGroup) {
+	statsGroup := api.Group("/admin/stats")
+	statsGroup.Use(middleware.RequirePermission(sr.jwtService, sr.userRepo, sr.rbacService, service.PermissionSystemStats))
+	{
+		statsGroup.POST("/overview", sr.handler.Overview)
+		statsGroup.POST("/timeseries", sr.handler.TimeSeries)
+		statsGroup.POST("/top", sr.handler.Top)
+	}
+}
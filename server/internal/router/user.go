@@ -2,6 +2,7 @@ package router
 
 import (
 	"MyBlog/internal/middleware"
+	"MyBlog/internal/model"
 	"MyBlog/internal/repository"
 	"MyBlog/internal/service"
 
@@ -10,19 +11,21 @@ import (
 
 // UserRoutes 用户路由模块
 type UserRoutes struct {
-	handler     UserHandlerInterface
-	jwtService  service.JWTService
-	userRepo    repository.UserRepository
-	rbacService service.RBACService
+	handler      UserHandlerInterface
+	jwtService   service.JWTService
+	userRepo     repository.UserRepository
+	rbacService  service.RBACService
+	auditService service.AuditService
 }
 
 // NewUserRoutes 创建用户路由模块
-func NewUserRoutes(handler UserHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository) *UserRoutes {
+func NewUserRoutes(handler UserHandlerInterface, jwtService service.JWTService, userRepo repository.UserRepository, rbacService service.RBACService, auditService service.AuditService) *UserRoutes {
 	return &UserRoutes{
-		handler:     handler,
-		jwtService:  jwtService,
-		userRepo:    userRepo,
-		rbacService: service.NewRBACService(),
+		handler:      handler,
+		jwtService:   jwtService,
+		userRepo:     userRepo,
+		rbacService:  rbacService,
+		auditService: auditService,
 	}
 }
 
@@ -34,24 +37,28 @@ func (ur *UserRoutes) RegisterRoutes(api *gin.RouterGroup) {
 		// 认证相关路由（无需token验证）
 		userGroup.POST("/login", ur.handler.Login)
 
-		// 用户查看接口（需要基础认证）
+		// 用户查看接口（需要基础认证，不接受个人访问令牌）
 		userGroup.POST("/get",
 			middleware.Auth(ur.jwtService),
+			middleware.DenyPersonalToken(),
 			ur.handler.GetUserByID)
 
 		// 用户创建（需要用户创建权限）
 		userGroup.POST("/create",
 			middleware.RequirePermission(ur.jwtService, ur.userRepo, ur.rbacService, service.PermissionUserCreate),
+			middleware.Audit(ur.auditService, model.ActionCreateUser, model.ResourceUser),
 			ur.handler.CreateUser)
 
 		// 用户更新（需要用户更新权限）
 		userGroup.POST("/update",
 			middleware.RequirePermission(ur.jwtService, ur.userRepo, ur.rbacService, service.PermissionUserUpdate),
+			middleware.Audit(ur.auditService, model.ActionUpdateUser, model.ResourceUser),
 			ur.handler.UpdateUser)
 
 		// 用户删除（需要用户删除权限）
 		userGroup.POST("/delete",
 			middleware.RequirePermission(ur.jwtService, ur.userRepo, ur.rbacService, service.PermissionUserDelete),
+			middleware.Audit(ur.auditService, model.ActionDeleteUser, model.ResourceUser),
 			ur.handler.DeleteUser)
 
 		// 用户列表（需要用户列表权限）
@@ -66,7 +73,7 @@ func (ur *UserRoutes) RegisterRoutes(api *gin.RouterGroup) {
 		// 刷新令牌（无需认证）
 		authGroup.POST("/refresh", ur.handler.RefreshToken)
 
-		// 登出（需要认证）
-		authGroup.POST("/logout", middleware.Auth(ur.jwtService), ur.handler.Logout)
+		// 登出（需要认证，个人访问令牌应通过令牌管理接口吊销）
+		authGroup.POST("/logout", middleware.Auth(ur.jwtService), middleware.DenyPersonalToken(), ur.handler.Logout)
 	}
 }
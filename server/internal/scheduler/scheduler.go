@@ -0,0 +1,87 @@
+// Package scheduler 提供进程内的周期任务调度
+//
+// 任务状态全部保存在数据库中，调度器只负责按间隔触发：
+// 重启后首次扫描即可补上停机期间到期的任务；多实例同时运行时由任务自身保证幂等（条件更新抢占）。
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// JobFunc 任务函数
+type JobFunc func(ctx context.Context) error
+
+// job 已注册的周期任务
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler 周期任务调度器
+type Scheduler struct {
+	jobs   []*job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建调度器实例
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every 注册按固定间隔执行的任务，需在 Start 之前调用
+func (s *Scheduler) Every(name string, interval time.Duration, run JobFunc) {
+	s.jobs = append(s.jobs, &job{name: name, interval: interval, run: run})
+}
+
+// Start 启动所有任务，每个任务启动后立即执行一次
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop 停止调度并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+}
+
+// loop 单个任务的执行循环，同一任务不会并发执行
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, j)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 执行一次任务，捕获 panic 避免影响其他任务
+func (s *Scheduler) runOnce(ctx context.Context, j *job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("定时任务 %s 异常: %v", j.name, r)
+		}
+	}()
+	if err := j.run(ctx); err != nil {
+		log.Printf("定时任务 %s 执行失败: %v", j.name, err)
+	}
+}
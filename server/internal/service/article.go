@@ -1,38 +1,62 @@
 package service
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"html"
+	"log"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"MyBlog/internal/cache"
+	"MyBlog/internal/config"
 	"MyBlog/internal/model"
 	"MyBlog/internal/repository"
+	"MyBlog/pkg/diff"
+	"MyBlog/pkg/markdown"
+	"MyBlog/pkg/search"
+	"MyBlog/pkg/slug"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // ArticleServiceInterface 文章服务接口
 type ArticleServiceInterface interface {
 	// 基础CRUD操作
 	CreateArticle(req *CreateArticleRequest, authorID uint) (*model.Article, error)
-	GetArticle(id uint, userID *uint) (*model.Article, error)
-	GetArticleBySlug(slug string, userID *uint) (*model.Article, error)
+	// unlockToken 为密码保护文章的解锁令牌，没有时传空字符串
+	GetArticle(id uint, userID *uint, unlockToken string) (*model.Article, error)
+	GetArticleBySlug(slug string, userID *uint, unlockToken string) (*model.Article, error)
+	UnlockArticle(id uint, password string) (*UnlockArticleResponse, error)
 	UpdateArticle(id uint, req *UpdateArticleRequest, userID uint) (*model.Article, error)
 	DeleteArticle(id uint, userID uint) error
 
 	// 查询操作
 	GetArticleList(req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error)
-	GetArticlesByAuthor(authorID uint, req *GetArticleListRequest) (*ArticleListResponse, error)
-	GetArticlesByCategory(categoryID uint, req *GetArticleListRequest) (*ArticleListResponse, error)
-	GetArticlesByTag(tagID uint, req *GetArticleListRequest) (*ArticleListResponse, error)
-	SearchArticles(keyword string, req *GetArticleListRequest) (*ArticleListResponse, error)
+	GetArticlesByAuthor(authorID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error)
+	GetArticlesByCategory(categoryID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error)
+	GetArticlesByTag(tagID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error)
+	SearchArticles(keyword string, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error)
 
 	// 统计和推荐
-	GetPopularArticles(limit int) ([]*model.Article, error)
-	GetRecentArticles(limit int) ([]*model.Article, error)
-	GetRelatedArticles(articleID uint, limit int) ([]*model.Article, error)
+	GetPopularArticles(limit int, userID *uint) ([]*model.Article, error)
+	GetRecentArticles(limit int, userID *uint) ([]*model.Article, error)
+	GetRelatedArticles(articleID uint, limit int, userID *uint) ([]*model.Article, error)
+	GetTrendingArticles(req *TrendingRequest, userID *uint) (*TrendingResponse, error)
+
+	// 归档统计
+	GetArchives() (*ArchiveSummary, error)
+	GetArchiveMonth(req *ArchiveMonthRequest, userID *uint) (*ArticleListResponse, error)
+	GetArchiveCalendar(req *ArchiveCalendarRequest) (*ArchiveCalendar, error)
 
 	// 互动操作
-	ViewArticle(articleID uint, userID *uint, visitorID string, ipAddress string) error
+	ViewArticle(articleID uint, userID *uint, visitorID string, ipAddress string, referer string) error
 	LikeArticle(articleID uint, userID uint) error
 	UnlikeArticle(articleID uint, userID uint) error
 	BookmarkArticle(articleID uint, userID uint) error
@@ -44,83 +68,277 @@ type ArticleServiceInterface interface {
 	ArchiveArticle(id uint, userID uint) error
 	SetArticlePrivate(id uint, userID uint) error
 
+	// 协作者管理
+	InviteCollaborator(articleID uint, req *InviteCollaboratorRequest, operatorID uint) (*model.ArticleCollaborator, error)
+	RemoveCollaborator(articleID, collaboratorID, operatorID uint) error
+	GetCollaborators(articleID uint, userID *uint) ([]model.ArticleCollaborator, error)
+	GetSharedArticles(userID uint, req *GetArticleListRequest) (*ArticleListResponse, error)
+
+	// 修订历史
+	ListRevisions(articleID uint, userID uint) ([]*model.ArticleRevision, error)
+	DiffRevisions(req *DiffRevisionsRequest, userID uint) (*RevisionDiffResponse, error)
+	RestoreRevision(articleID, number uint, userID uint) (*model.Article, error)
+
+	// 自动保存草稿
+	SaveDraft(req *SaveDraftRequest, userID uint) (*model.ArticleDraft, error)
+	GetDraft(articleID uint, userID uint) (*ArticleDraftResponse, error)
+	DiscardDraft(articleID uint, userID uint) error
+
+	// 编辑锁
+	AcquireEditLock(req *EditLockRequest, userID uint) (*EditLockResponse, error)
+	HeartbeatEditLock(req *EditLockRequest, userID uint) (*EditLockResponse, error)
+	ReleaseEditLock(req *EditLockRequest, userID uint) error
+	GetEditLock(articleID uint, userID uint) (*EditLockResponse, error)
+
+	// 内容渲染
+	RerenderArticles(force bool) (*RerenderResult, error)
+
+	// 搜索索引
+	ReindexArticles() (*ReindexResult, error)
+
+	// 相关文章（由后台任务定时重新计算）
+	RebuildRelated() (*RelatedResult, error)
+
+	// 互动统计（由后台任务定时汇总）
+	RollupEngagement() (int64, error)
+
+	// 定时发布（由后台任务调用）
+	PublishDueArticles() (*ScheduleResult, error)
+	ArchiveExpiredArticles() (int64, error)
+
 	// 权限检查
 	CanView(article *model.Article, userID *uint) bool
 	CanEdit(article *model.Article, userID uint) bool
+	CanPublish(article *model.Article, userID uint) bool
 	CanDelete(article *model.Article, userID uint) bool
+	CanManageCollaborators(article *model.Article, userID uint) bool
+
+	// WithTokenScopes 返回按个人访问令牌授权范围校验权限的服务副本
+	WithTokenScopes(scopes []Permission) ArticleServiceInterface
 }
 
 // 请求和响应结构体
 type CreateArticleRequest struct {
-	Title          string `json:"title" binding:"required,min=1,max=200"`
-	Slug           string `json:"slug" binding:"max=200"`
-	Summary        string `json:"summary" binding:"max=500"`
-	Content        string `json:"content" binding:"required"`
-	CoverImage     string `json:"coverImage" binding:"max=500"`
-	CategoryID     *uint  `json:"categoryId"`
-	CategoryIDs    []uint `json:"categoryIds"`
-	TagIDs         []uint `json:"tagIds"`
-	Status         string `json:"status" binding:"oneof=draft published private"`
-	IsFeatured     bool   `json:"isFeatured"`
-	IsTop          bool   `json:"isTop"`
-	CommentEnabled bool   `json:"commentEnabled"`
-	SEOTitle       string `json:"seoTitle" binding:"max=100"`
-	SEODescription string `json:"seoDescription" binding:"max=255"`
-	SEOKeywords    string `json:"seoKeywords" binding:"max=200"`
+	Title          string     `json:"title" binding:"required,min=1,max=200"`
+	Slug           string     `json:"slug" binding:"max=200"`
+	Summary        string     `json:"summary" binding:"max=500"`
+	Content        string     `json:"content" binding:"required"`
+	CoverImage     string     `json:"coverImage" binding:"max=500"`
+	CategoryID     *uint      `json:"categoryId"`
+	CategoryIDs    []uint     `json:"categoryIds"`
+	TagIDs         []uint     `json:"tagIds"`
+	Status         string     `json:"status" binding:"oneof=draft published private scheduled"`
+	Visibility     string     `json:"visibility" binding:"omitempty,oneof=public password members"` // 可见范围，默认 public
+	Password       string     `json:"password" binding:"omitempty,min=4,max=64"`                    // 访问密码，可见范围为 password 时使用
+	PublishedAt    *time.Time `json:"publishedAt"`                                                  // 计划发布时间，仅定时发布时使用
+	ExpiresAt      *time.Time `json:"expiresAt"`                                                    // 下线时间，到期后自动归档
+	IsFeatured     bool       `json:"isFeatured"`
+	IsTop          bool       `json:"isTop"`
+	CommentEnabled bool       `json:"commentEnabled"`
+	SEOTitle       string     `json:"seoTitle" binding:"max=100"`
+	SEODescription string     `json:"seoDescription" binding:"max=255"`
+	SEOKeywords    string     `json:"seoKeywords" binding:"max=200"`
 }
 
 type UpdateArticleRequest struct {
-	Title          string `json:"title" binding:"required,min=1,max=200"`
-	Slug           string `json:"slug" binding:"max=200"`
-	Summary        string `json:"summary" binding:"max=500"`
-	Content        string `json:"content" binding:"required"`
-	CoverImage     string `json:"coverImage" binding:"max=500"`
-	CategoryID     *uint  `json:"categoryId"`
-	CategoryIDs    []uint `json:"categoryIds"`
-	TagIDs         []uint `json:"tagIds"`
-	Status         string `json:"status" binding:"oneof=draft published archived private"`
-	IsFeatured     bool   `json:"isFeatured"`
-	IsTop          bool   `json:"isTop"`
-	CommentEnabled bool   `json:"commentEnabled"`
-	SEOTitle       string `json:"seoTitle" binding:"max=100"`
-	SEODescription string `json:"seoDescription" binding:"max=255"`
-	SEOKeywords    string `json:"seoKeywords" binding:"max=200"`
+	Title          string     `json:"title" binding:"required,min=1,max=200"`
+	Slug           string     `json:"slug" binding:"max=200"`
+	Summary        string     `json:"summary" binding:"max=500"`
+	Content        string     `json:"content" binding:"required"`
+	CoverImage     string     `json:"coverImage" binding:"max=500"`
+	CategoryID     *uint      `json:"categoryId"`
+	CategoryIDs    []uint     `json:"categoryIds"`
+	TagIDs         []uint     `json:"tagIds"`
+	Status         string     `json:"status" binding:"oneof=draft published archived private scheduled"`
+	Visibility     string     `json:"visibility" binding:"omitempty,oneof=public password members"` // 可见范围，默认 public
+	Password       string     `json:"password" binding:"omitempty,min=4,max=64"`                    // 访问密码，可见范围为 password 时使用
+	PublishedAt    *time.Time `json:"publishedAt"`                                                  // 计划发布时间，仅定时发布时使用
+	ExpiresAt      *time.Time `json:"expiresAt"`                                                    // 下线时间，到期后自动归档，不传保持原值
+	ClearExpiresAt bool       `json:"clearExpiresAt"`                                               // 为 true 时清除下线时间
+	IsFeatured     bool       `json:"isFeatured"`
+	IsTop          bool       `json:"isTop"`
+	CommentEnabled bool       `json:"commentEnabled"`
+	SEOTitle       string     `json:"seoTitle" binding:"max=100"`
+	SEODescription string     `json:"seoDescription" binding:"max=255"`
+	SEOKeywords    string     `json:"seoKeywords" binding:"max=200"`
+	RevisionNote   string     `json:"revisionNote" binding:"max=255"` // 本次修改说明，记录到修订历史
+	Version        uint       `json:"version"`                        // 客户端持有的文章版本号，也可通过 If-Match 请求头传递
 }
 
+// maxFacetItems 分面统计中分类、标签、作者各自最多返回的项数
+const maxFacetItems = 50
+
+// ErrInvalidListFilter 文章列表筛选条件无效（时间格式错误、范围颠倒等）
+var ErrInvalidListFilter = errors.New("筛选条件无效")
+
 type GetArticleListRequest struct {
-	Page     int    `json:"page" binding:"min=1"`
-	PageSize int    `json:"pageSize" binding:"min=1,max=100"`
-	Status   string `json:"status" binding:"oneof='' draft published archived private"`
-	AuthorID uint   `json:"authorId"`
-	SortBy   string `json:"sortBy" binding:"oneof='' created_at updated_at published_at view_count like_count"`
-	Order    string `json:"order" binding:"oneof='' asc desc"`
-	Search   string `json:"search"`
+	Page       int    `json:"page" binding:"min=1"`
+	PageSize   int    `json:"pageSize" binding:"min=1,max=100"`
+	Status     string `json:"status" binding:"oneof='' draft published archived private scheduled"`
+	AuthorID   uint   `json:"authorId"`
+	CategoryID uint   `json:"categoryId"` // 分类筛选（主分类或附加分类）
+	TagID      uint   `json:"tagId"`      // 标签筛选
+	SortBy     string `json:"sortBy" binding:"oneof='' created_at updated_at published_at view_count like_count"`
+	Order      string `json:"order" binding:"oneof='' asc desc"`
+	Search     string `json:"search"`
+	// 多分类、多标签筛选，match 为 any（默认，满足任一）或 all（全部满足）
+	CategoryIDs   []uint `json:"categoryIds" binding:"omitempty,max=20"`
+	CategoryMatch string `json:"categoryMatch" binding:"oneof='' any all"`
+	TagIDs        []uint `json:"tagIds" binding:"omitempty,max=20"`
+	TagMatch      string `json:"tagMatch" binding:"oneof='' any all"`
+	// 发布时间范围：YYYY-MM-DD 或 YYYY-MM-DD HH:mm:ss，结束日期只给日期时包含当天
+	PublishedFrom  string `json:"publishedFrom"`
+	PublishedTo    string `json:"publishedTo"`
+	IsFeatured     *bool  `json:"isFeatured"`
+	IsTop          *bool  `json:"isTop"`
+	MinWordCount   uint   `json:"minWordCount"`
+	MaxWordCount   uint   `json:"maxWordCount"`
+	MinReadingTime uint   `json:"minReadingTime"` // 分钟
+	MaxReadingTime uint   `json:"maxReadingTime"`
+	Facets         bool   `json:"facets"` // 是否返回分面统计（仅文章列表接口）
+	// 游标分页：传上次响应中的 nextCursor/prevCursor 时忽略 page，搜索接口不支持
+	Cursor string `json:"cursor" binding:"max=512"`
+}
+
+type InviteCollaboratorRequest struct {
+	UserID uint   `json:"userId" binding:"required,min=1"`
+	Role   string `json:"role" binding:"required,oneof=viewer editor publisher"`
 }
 
 type ArticleListResponse struct {
-	Articles []*model.Article `json:"articles"`
-	Total    int64            `json:"total"`
-	Page     int              `json:"page"`
-	PageSize int              `json:"pageSize"`
+	Articles []*model.Article          `json:"articles"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"pageSize"`
+	Facets   *repository.ArticleFacets `json:"facets,omitempty"`
+	// 游标分页：本页之后、之前还可能有文章时返回，下一页可能为空
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+type DiffRevisionsRequest struct {
+	ArticleID uint   `json:"articleId" binding:"required,min=1"`
+	From      uint   `json:"from" binding:"required,min=1"`               // 起始修订号
+	To        uint   `json:"to" binding:"required,min=1"`                 // 目标修订号
+	Mode      string `json:"mode" binding:"omitempty,oneof=unified word"` // 默认 unified
+}
+
+type RevisionDiffResponse struct {
+	ArticleID uint        `json:"articleId"`
+	From      uint        `json:"from"`
+	To        uint        `json:"to"`
+	Mode      string      `json:"mode"`
+	TitleFrom string      `json:"titleFrom"`
+	TitleTo   string      `json:"titleTo"`
+	Unified   string      `json:"unified,omitempty"` // 按行的统一格式差异（mode=unified）
+	Edits     []diff.Edit `json:"edits,omitempty"`   // 按词的差异片段（mode=word）
+}
+
+type UnlockArticleResponse struct {
+	ArticleID   uint      `json:"articleId"`
+	UnlockToken string    `json:"unlockToken"` // 解锁令牌，获取详情时携带
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type SaveDraftRequest struct {
+	ArticleID   uint   `json:"articleId"` // 0 表示尚未创建的新文章
+	Title       string `json:"title" binding:"max=200"`
+	Summary     string `json:"summary" binding:"max=500"`
+	Content     string `json:"content"`
+	BaseVersion uint   `json:"baseVersion"` // 草稿基于的文章版本号，默认当前版本
+}
+
+type ArticleDraftResponse struct {
+	Draft          *model.ArticleDraft `json:"draft"`
+	CurrentVersion uint                `json:"currentVersion"` // 文章当前版本号（新文章为0）
+	Outdated       bool                `json:"outdated"`       // 草稿保存后文章已被修改，恢复前需要合并
+}
+
+type EditLockRequest struct {
+	ArticleID uint   `json:"articleId" binding:"required,min=1"`
+	SessionID string `json:"sessionId" binding:"required,max=64"` // 编辑器会话ID，由客户端生成
+	Force     bool   `json:"force"`                               // 强制接管他人持有的锁（仅获取时有效）
+}
+
+type EditLockResponse struct {
+	Acquired bool                   `json:"acquired"` // 当前会话是否持有锁
+	Lock     *model.ArticleEditLock `json:"lock"`     // 当前有效的锁（含持有人），无人编辑时为空
+}
+
+type RerenderResult struct {
+	Version  int `json:"version"`  // 当前渲染器版本
+	Scanned  int `json:"scanned"`  // 检查的文章数
+	Rendered int `json:"rendered"` // 重新渲染的文章数
+	Failed   int `json:"failed"`   // 渲染失败的文章数
+}
+
+type ReindexResult struct {
+	Backend    string `json:"backend"`    // 搜索后端
+	Indexed    int    `json:"indexed"`    // 已索引的文章数（mysql 后端由数据库维护索引，始终为 0）
+	DurationMs int64  `json:"durationMs"` // 耗时（毫秒）
+}
+
+// ArticleConflictError 文章版本冲突错误，携带服务端当前版本号
+type ArticleConflictError struct {
+	ArticleID      uint `json:"articleId"`
+	CurrentVersion uint `json:"currentVersion"`
+}
+
+func (e *ArticleConflictError) Error() string {
+	return fmt.Sprintf("文章已被其他人修改，当前版本为 %d，请合并后重试", e.CurrentVersion)
+}
+
+type ScheduleResult struct {
+	Due       int `json:"due"`       // 到期的定时文章数
+	Published int `json:"published"` // 由本实例发布的文章数
+	Failed    int `json:"failed"`    // 发布失败的文章数
 }
 
 // ArticleService 文章服务实现
 type ArticleService struct {
-	articleRepo repository.ArticleRepositoryInterface
-	userRepo    repository.UserRepository
-	rbacService RBACService
+	articleRepo      repository.ArticleRepositoryInterface
+	collaboratorRepo repository.CollaboratorRepositoryInterface
+	revisionRepo     repository.RevisionRepositoryInterface
+	draftRepo        repository.DraftRepositoryInterface
+	editLockRepo     repository.EditLockRepositoryInterface
+	userRepo         repository.UserRepository
+	statsRepo        repository.ContentStatsRepository
+	rbacService      RBACService
+	searchBackend    SearchBackend
+	cache            cache.CacheService
+	config           *config.Config
+
+	// 通过个人访问令牌调用时的授权范围，scoped 为 false 表示账号登录，不受授权范围限制
+	scoped      bool
+	tokenScopes []Permission
 }
 
 // NewArticleService 创建文章服务实例
 func NewArticleService(
 	articleRepo repository.ArticleRepositoryInterface,
+	collaboratorRepo repository.CollaboratorRepositoryInterface,
+	revisionRepo repository.RevisionRepositoryInterface,
+	draftRepo repository.DraftRepositoryInterface,
+	editLockRepo repository.EditLockRepositoryInterface,
 	userRepo repository.UserRepository,
+	statsRepo repository.ContentStatsRepository,
 	rbacService RBACService,
+	searchBackend SearchBackend,
+	cacheService cache.CacheService,
+	cfg *config.Config,
 ) ArticleServiceInterface {
 	return &ArticleService{
-		articleRepo: articleRepo,
-		userRepo:    userRepo,
-		rbacService: rbacService,
+		articleRepo:      articleRepo,
+		collaboratorRepo: collaboratorRepo,
+		revisionRepo:     revisionRepo,
+		draftRepo:        draftRepo,
+		editLockRepo:     editLockRepo,
+		userRepo:         userRepo,
+		statsRepo:        statsRepo,
+		rbacService:      rbacService,
+		searchBackend:    searchBackend,
+		cache:            cacheService,
+		config:           cfg,
 	}
 }
 
@@ -133,14 +351,14 @@ func (s *ArticleService) CreateArticle(req *CreateArticleRequest, authorID uint)
 	}
 
 	// 权限检查
-	if !s.rbacService.HasPermission(user.Role, PermissionArticleCreate) {
+	if !s.hasPermission(user, PermissionArticleCreate) {
 		return nil, errors.New("没有创建文章的权限")
 	}
 
 	// 构建文章对象
 	article := &model.Article{
 		Title:          req.Title,
-		Slug:           req.Slug,
+		Slug:           s.makeSlug(req.Slug, req.Title),
 		Summary:        req.Summary,
 		Content:        req.Content,
 		CoverImage:     req.CoverImage,
@@ -155,6 +373,16 @@ func (s *ArticleService) CreateArticle(req *CreateArticleRequest, authorID uint)
 		SEOKeywords:    req.SEOKeywords,
 	}
 
+	// 定时发布和下线时间
+	if err := applySchedule(article, req.PublishedAt, req.ExpiresAt, false); err != nil {
+		return nil, err
+	}
+
+	// 可见范围
+	if err := applyVisibility(article, req.Visibility, req.Password); err != nil {
+		return nil, err
+	}
+
 	// 处理内容
 	if err := s.processContent(article); err != nil {
 		return nil, err
@@ -165,6 +393,14 @@ func (s *ArticleService) CreateArticle(req *CreateArticleRequest, authorID uint)
 		return nil, err
 	}
 
+	// 记录首个修订
+	if err := s.saveRevision(article, authorID, ""); err != nil {
+		return nil, err
+	}
+
+	// 新文章已保存，丢弃自动保存的草稿（失败不影响创建）
+	_ = s.draftRepo.Delete(0, authorID)
+
 	// 同步分类关联
 	if len(req.CategoryIDs) > 0 {
 		if err := s.articleRepo.SyncCategories(article.ID, req.CategoryIDs); err != nil {
@@ -180,11 +416,11 @@ func (s *ArticleService) CreateArticle(req *CreateArticleRequest, authorID uint)
 	}
 
 	// 重新获取完整的文章信息
-	return s.articleRepo.GetByID(article.ID)
+	return s.reloadIndexed(article.ID)
 }
 
 // GetArticle 获取文章详情
-func (s *ArticleService) GetArticle(id uint, userID *uint) (*model.Article, error) {
+func (s *ArticleService) GetArticle(id uint, userID *uint, unlockToken string) (*model.Article, error) {
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -195,14 +431,24 @@ func (s *ArticleService) GetArticle(id uint, userID *uint) (*model.Article, erro
 		return nil, errors.New("没有查看此文章的权限")
 	}
 
+	// 受保护文章按访问者隐藏正文
+	s.protectArticle(article, userID, unlockToken)
+
+	s.loadCollaborators(article)
 	return article, nil
 }
 
 // GetArticleBySlug 根据Slug获取文章
-func (s *ArticleService) GetArticleBySlug(slug string, userID *uint) (*model.Article, error) {
+// 当前Slug不存在时按历史Slug查找，找到后设置 MovedFrom 提示客户端跳转
+func (s *ArticleService) GetArticleBySlug(slug string, userID *uint, unlockToken string) (*model.Article, error) {
 	article, err := s.articleRepo.GetBySlug(slug)
 	if err != nil {
-		return nil, err
+		moved, historyErr := s.articleRepo.GetByHistorySlug(slug)
+		if historyErr != nil {
+			return nil, err
+		}
+		article = moved
+		article.MovedFrom = slug
 	}
 
 	// 权限检查
@@ -210,9 +456,34 @@ func (s *ArticleService) GetArticleBySlug(slug string, userID *uint) (*model.Art
 		return nil, errors.New("没有查看此文章的权限")
 	}
 
+	// 受保护文章按访问者隐藏正文
+	s.protectArticle(article, userID, unlockToken)
+
+	s.loadCollaborators(article)
 	return article, nil
 }
 
+// UnlockArticle 校验密码保护文章的访问密码，返回短期有效的解锁令牌
+func (s *ArticleService) UnlockArticle(id uint, password string) (*UnlockArticleResponse, error) {
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsPublic() || article.Visibility != model.VisibilityPassword || article.PasswordHash == "" {
+		return nil, errors.New("文章不需要密码")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(article.PasswordHash), []byte(password)) != nil {
+		return nil, errors.New("访问密码错误")
+	}
+
+	expiresAt := time.Now().Add(s.unlockTTL()).Truncate(time.Second)
+	return &UnlockArticleResponse{
+		ArticleID:   article.ID,
+		UnlockToken: s.signUnlockToken(article, expiresAt),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
 // UpdateArticle 更新文章
 func (s *ArticleService) UpdateArticle(id uint, req *UpdateArticleRequest, userID uint) (*model.Article, error) {
 	// 获取现有文章
@@ -226,9 +497,31 @@ func (s *ArticleService) UpdateArticle(id uint, req *UpdateArticleRequest, userI
 		return nil, errors.New("没有编辑此文章的权限")
 	}
 
+	// 乐观锁：客户端必须基于最新版本修改
+	if req.Version == 0 {
+		return nil, errors.New("缺少文章版本号")
+	}
+	if req.Version != article.Version {
+		return nil, &ArticleConflictError{ArticleID: article.ID, CurrentVersion: article.Version}
+	}
+
+	// 变更发布状态、计划发布时间、下线时间或可见范围需要发布权限
+	if (model.ArticleStatus(req.Status) != article.Status || scheduleChanged(article, req) || visibilityChanged(article, req)) &&
+		!s.CanPublish(article, userID) {
+		return nil, errors.New("没有变更此文章状态的权限")
+	}
+
+	// 功能上线前创建的文章没有修订，先保存修改前的内容
+	if err := s.ensureBaseRevision(article); err != nil {
+		return nil, err
+	}
+
 	// 更新字段
 	article.Title = req.Title
-	article.Slug = req.Slug
+	if req.Slug != "" {
+		// 未指定时保留原Slug；修改后旧Slug记入历史，旧链接仍可访问
+		article.Slug = s.makeSlug(req.Slug, req.Title)
+	}
 	article.Summary = req.Summary
 	article.Content = req.Content
 	article.CoverImage = req.CoverImage
@@ -241,16 +534,34 @@ func (s *ArticleService) UpdateArticle(id uint, req *UpdateArticleRequest, userI
 	article.SEODescription = req.SEODescription
 	article.SEOKeywords = req.SEOKeywords
 
+	// 定时发布和下线时间
+	if err := applySchedule(article, req.PublishedAt, req.ExpiresAt, req.ClearExpiresAt); err != nil {
+		return nil, err
+	}
+
+	// 可见范围
+	if err := applyVisibility(article, req.Visibility, req.Password); err != nil {
+		return nil, err
+	}
+
 	// 处理内容
 	if err := s.processContent(article); err != nil {
 		return nil, err
 	}
 
 	// 更新文章
-	if err := s.articleRepo.Update(article); err != nil {
+	if err := s.updateArticle(article); err != nil {
 		return nil, err
 	}
 
+	// 记录修订
+	if err := s.saveRevision(article, userID, req.RevisionNote); err != nil {
+		return nil, err
+	}
+
+	// 修改已保存，丢弃该用户的自动保存草稿（失败不影响更新）
+	_ = s.draftRepo.Delete(article.ID, userID)
+
 	// 同步分类关联
 	if len(req.CategoryIDs) > 0 {
 		if err := s.articleRepo.SyncCategories(article.ID, req.CategoryIDs); err != nil {
@@ -266,7 +577,7 @@ func (s *ArticleService) UpdateArticle(id uint, req *UpdateArticleRequest, userI
 	}
 
 	// 重新获取完整的文章信息
-	return s.articleRepo.GetByID(article.ID)
+	return s.reloadIndexed(article.ID)
 }
 
 // DeleteArticle 删除文章
@@ -282,36 +593,40 @@ func (s *ArticleService) DeleteArticle(id uint, userID uint) error {
 		return errors.New("没有删除此文章的权限")
 	}
 
-	return s.articleRepo.Delete(id)
+	if err := s.articleRepo.Delete(id); err != nil {
+		return err
+	}
+	if err := s.searchBackend.Remove(id); err != nil {
+		log.Printf("删除文章搜索索引失败(文章ID=%d): %v", id, err)
+	}
+	s.invalidateArchives()
+	return nil
 }
 
 // GetArticleList 获取文章列表
 func (s *ArticleService) GetArticleList(req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error) {
-	params := &repository.ArticleListParams{
-		Page:     req.Page,
-		PageSize: req.PageSize,
-		Status:   model.ArticleStatus(req.Status),
-		AuthorID: req.AuthorID,
-		SortBy:   req.SortBy,
-		Order:    req.Order,
-		Search:   req.Search,
+	params, err := req.toParams()
+	if err != nil {
+		return nil, err
 	}
+	params.Search = req.Search
+	params.Member = userID != nil
 
 	// 如果不是管理员，只能看到已发布的文章
-	if userID == nil {
-		params.Status = model.ArticleStatusPublished
-	} else {
-		user, err := s.userRepo.GetByID(*userID)
-		if err != nil || !s.rbacService.HasPermission(user.Role, PermissionArticleManage) {
-			params.Status = model.ArticleStatusPublished
-		}
-	}
+	params.Status = s.listStatus(req.Status, userID)
 
 	articles, total, err := s.articleRepo.List(params)
 	if err != nil {
 		return nil, err
 	}
 
+	var facets *repository.ArticleFacets
+	if req.Facets {
+		if facets, err = s.articleRepo.Facets(params, maxFacetItems); err != nil {
+			return nil, err
+		}
+	}
+
 	// 过滤用户没有权限查看的文章
 	var filteredArticles []*model.Article
 	for _, article := range articles {
@@ -319,101 +634,147 @@ func (s *ArticleService) GetArticleList(req *GetArticleListRequest, userID *uint
 			filteredArticles = append(filteredArticles, article)
 		}
 	}
+	s.protectArticles(filteredArticles, userID)
+	next, prev := pageCursors(articles, params)
 
 	return &ArticleListResponse{
-		Articles: filteredArticles,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+		Articles:   filteredArticles,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		Facets:     facets,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
 // GetArticlesByAuthor 获取指定作者的文章
-func (s *ArticleService) GetArticlesByAuthor(authorID uint, req *GetArticleListRequest) (*ArticleListResponse, error) {
+func (s *ArticleService) GetArticlesByAuthor(authorID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error) {
+	cursor, err := req.listCursor()
+	if err != nil {
+		return nil, err
+	}
 	params := &repository.ArticleListParams{
 		Page:     req.Page,
 		PageSize: req.PageSize,
 		Status:   model.ArticleStatus(req.Status),
 		SortBy:   req.SortBy,
 		Order:    req.Order,
+		Member:   userID != nil,
 		Search:   req.Search,
+		Cursor:   cursor,
 	}
 
 	articles, total, err := s.articleRepo.GetByAuthor(authorID, params)
 	if err != nil {
 		return nil, err
 	}
+	s.protectArticles(articles, userID)
+	next, prev := pageCursors(articles, params)
 
 	return &ArticleListResponse{
-		Articles: articles,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+		Articles:   articles,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
 // GetArticlesByCategory 获取指定分类的文章
-func (s *ArticleService) GetArticlesByCategory(categoryID uint, req *GetArticleListRequest) (*ArticleListResponse, error) {
+func (s *ArticleService) GetArticlesByCategory(categoryID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error) {
+	cursor, err := req.listCursor()
+	if err != nil {
+		return nil, err
+	}
 	params := &repository.ArticleListParams{
 		Page:     req.Page,
 		PageSize: req.PageSize,
 		Status:   model.ArticleStatusPublished,
 		SortBy:   req.SortBy,
 		Order:    req.Order,
+		Member:   userID != nil,
 		Search:   req.Search,
+		Cursor:   cursor,
 	}
 
 	articles, total, err := s.articleRepo.GetByCategory(categoryID, params)
 	if err != nil {
 		return nil, err
 	}
+	s.protectArticles(articles, userID)
+	next, prev := pageCursors(articles, params)
 
 	return &ArticleListResponse{
-		Articles: articles,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+		Articles:   articles,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
 // GetArticlesByTag 获取指定标签的文章
-func (s *ArticleService) GetArticlesByTag(tagID uint, req *GetArticleListRequest) (*ArticleListResponse, error) {
+func (s *ArticleService) GetArticlesByTag(tagID uint, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error) {
+	cursor, err := req.listCursor()
+	if err != nil {
+		return nil, err
+	}
 	params := &repository.ArticleListParams{
 		Page:     req.Page,
 		PageSize: req.PageSize,
 		Status:   model.ArticleStatusPublished,
 		SortBy:   req.SortBy,
 		Order:    req.Order,
+		Member:   userID != nil,
 		Search:   req.Search,
+		Cursor:   cursor,
 	}
 
 	articles, total, err := s.articleRepo.GetByTag(tagID, params)
 	if err != nil {
 		return nil, err
 	}
+	s.protectArticles(articles, userID)
+	next, prev := pageCursors(articles, params)
 
 	return &ArticleListResponse{
-		Articles: articles,
-		Total:    total,
-		Page:     req.Page,
-		PageSize: req.PageSize,
+		Articles:   articles,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		NextCursor: next,
+		PrevCursor: prev,
 	}, nil
 }
 
 // SearchArticles 搜索文章
-func (s *ArticleService) SearchArticles(keyword string, req *GetArticleListRequest) (*ArticleListResponse, error) {
-	params := &repository.ArticleListParams{
-		Page:     req.Page,
-		PageSize: req.PageSize,
-		Status:   model.ArticleStatusPublished,
-		SortBy:   req.SortBy,
-		Order:    req.Order,
+func (s *ArticleService) SearchArticles(keyword string, req *GetArticleListRequest, userID *uint) (*ArticleListResponse, error) {
+	// 默认只搜索已发布的文章
+	status := req.Status
+	if status == "" {
+		status = string(model.ArticleStatusPublished)
+	}
+	// 搜索按相关度排序，没有稳定的排序键，只支持页码分页
+	if req.Cursor != "" {
+		return nil, fmt.Errorf("%w: 搜索不支持游标分页", ErrInvalidListFilter)
 	}
+	params, err := req.toParams()
+	if err != nil {
+		return nil, err
+	}
+	params.Status = s.listStatus(status, userID)
+	params.Member = userID != nil
+	params.Weights = s.searchWeights()
 
-	articles, total, err := s.articleRepo.Search(keyword, params)
+	articles, total, err := s.searchBackend.Search(keyword, params)
 	if err != nil {
 		return nil, err
 	}
+	s.protectArticles(articles, userID)
+	s.highlightArticles(articles, keyword)
 
 	return &ArticleListResponse{
 		Articles: articles,
@@ -424,92 +785,70 @@ func (s *ArticleService) SearchArticles(keyword string, req *GetArticleListReque
 }
 
 // GetPopularArticles 获取热门文章
-func (s *ArticleService) GetPopularArticles(limit int) ([]*model.Article, error) {
-	return s.articleRepo.GetPopular(limit)
+func (s *ArticleService) GetPopularArticles(limit int, userID *uint) ([]*model.Article, error) {
+	articles, err := s.articleRepo.GetPopular(limit)
+	if err != nil {
+		return nil, err
+	}
+	s.protectArticles(articles, userID)
+	return articles, nil
 }
 
 // GetRecentArticles 获取最新文章
-func (s *ArticleService) GetRecentArticles(limit int) ([]*model.Article, error) {
-	return s.articleRepo.GetRecent(limit)
-}
-
-// GetRelatedArticles 获取相关文章
-func (s *ArticleService) GetRelatedArticles(articleID uint, limit int) ([]*model.Article, error) {
-	// 获取当前文章
-	article, err := s.articleRepo.GetByID(articleID)
+func (s *ArticleService) GetRecentArticles(limit int, userID *uint) ([]*model.Article, error) {
+	articles, err := s.articleRepo.GetRecent(limit)
 	if err != nil {
 		return nil, err
 	}
-
-	// 基于分类和标签获取相关文章
-	params := &repository.ArticleListParams{
-		Page:     1,
-		PageSize: limit * 2, // 获取更多文章用于筛选
-		Status:   model.ArticleStatusPublished,
-		SortBy:   "view_count",
-		Order:    "desc",
-	}
-
-	var relatedArticles []*model.Article
-
-	// 优先获取同分类的文章
-	if article.CategoryID != nil {
-		articles, _, err := s.articleRepo.GetByCategory(*article.CategoryID, params)
-		if err == nil {
-			for _, a := range articles {
-				if a.ID != articleID && len(relatedArticles) < limit {
-					relatedArticles = append(relatedArticles, a)
-				}
-			}
-		}
-	}
-
-	// 如果还不够，获取同标签的文章
-	if len(relatedArticles) < limit && len(article.Tags) > 0 {
-		for _, tag := range article.Tags {
-			if len(relatedArticles) >= limit {
-				break
-			}
-
-			articles, _, err := s.articleRepo.GetByTag(tag.ID, params)
-			if err != nil {
-				continue
-			}
-
-			for _, a := range articles {
-				if a.ID != articleID && !containsArticle(relatedArticles, a.ID) && len(relatedArticles) < limit {
-					relatedArticles = append(relatedArticles, a)
-				}
-			}
-		}
-	}
-
-	return relatedArticles, nil
+	s.protectArticles(articles, userID)
+	return articles, nil
 }
 
-// ViewArticle 记录文章浏览
-func (s *ArticleService) ViewArticle(articleID uint, userID *uint, visitorID string, ipAddress string) error {
+// ViewArticle 记录文章浏览，referer 为访客进入文章前所在的页面
+func (s *ArticleService) ViewArticle(articleID uint, userID *uint, visitorID string, ipAddress string, referer string) error {
 	// 增加浏览量
 	if err := s.articleRepo.IncrementViewCount(articleID); err != nil {
 		return err
 	}
 
-	// TODO: 记录详细的浏览记录到 article_views 表
-	// 这里可以异步处理，避免影响响应速度
+	// 记录每日浏览明细，供趋势统计汇总；写入失败不影响累计浏览量
+	now := time.Now()
+	view := &model.ArticleView{
+		ArticleID: articleID,
+		UserID:    userID,
+		VisitorID: visitorID,
+		IPAddress: ipAddress,
+		Referer:   referer,
+		ViewDate:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	if err := s.articleRepo.RecordView(view); err != nil {
+		log.Printf("记录文章 %d 浏览明细失败: %v", articleID, err)
+	}
 
 	return nil
 }
 
-// LikeArticle 点赞文章
+// LikeArticle 点赞文章，重复点赞不重复计数
 func (s *ArticleService) LikeArticle(articleID uint, userID uint) error {
-	// TODO: 实现点赞逻辑，操作 article_likes 表
-	// 更新文章的点赞数
+	added, err := s.articleRepo.AddLike(articleID, userID)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return nil
+	}
 	return s.articleRepo.UpdateLikeCount(articleID)
 }
 
 // UnlikeArticle 取消点赞文章
 func (s *ArticleService) UnlikeArticle(articleID uint, userID uint) error {
-	// TODO: 实现取消点赞逻辑
+	removed, err := s.articleRepo.RemoveLike(articleID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return nil
+	}
 	return s.articleRepo.UpdateLikeCount(articleID)
 }
 
@@ -532,11 +871,15 @@ func (s *ArticleService) PublishArticle(id uint, userID uint) error {
 		return err
 	}
 
-	if !s.CanEdit(article, userID) {
-		return errors.New("没有编辑此文章的权限")
+	if !s.CanPublish(article, userID) {
+		return errors.New("没有变更此文章状态的权限")
 	}
 
-	return s.articleRepo.Publish(id)
+	if err := s.articleRepo.Publish(id); err != nil {
+		return err
+	}
+	s.reindexArticle(id)
+	return nil
 }
 
 // UnpublishArticle 取消发布文章
@@ -546,11 +889,15 @@ func (s *ArticleService) UnpublishArticle(id uint, userID uint) error {
 		return err
 	}
 
-	if !s.CanEdit(article, userID) {
-		return errors.New("没有编辑此文章的权限")
+	if !s.CanPublish(article, userID) {
+		return errors.New("没有变更此文章状态的权限")
 	}
 
-	return s.articleRepo.Unpublish(id)
+	if err := s.articleRepo.Unpublish(id); err != nil {
+		return err
+	}
+	s.reindexArticle(id)
+	return nil
 }
 
 // ArchiveArticle 归档文章
@@ -560,11 +907,15 @@ func (s *ArticleService) ArchiveArticle(id uint, userID uint) error {
 		return err
 	}
 
-	if !s.CanEdit(article, userID) {
-		return errors.New("没有编辑此文章的权限")
+	if !s.CanPublish(article, userID) {
+		return errors.New("没有变更此文章状态的权限")
 	}
 
-	return s.articleRepo.Archive(id)
+	if err := s.articleRepo.Archive(id); err != nil {
+		return err
+	}
+	s.reindexArticle(id)
+	return nil
 }
 
 // SetArticlePrivate 设置文章为私有
@@ -574,14 +925,19 @@ func (s *ArticleService) SetArticlePrivate(id uint, userID uint) error {
 		return err
 	}
 
-	if !s.CanEdit(article, userID) {
-		return errors.New("没有编辑此文章的权限")
+	if !s.CanPublish(article, userID) {
+		return errors.New("没有变更此文章状态的权限")
 	}
 
-	return s.articleRepo.SetPrivate(id)
+	if err := s.articleRepo.SetPrivate(id); err != nil {
+		return err
+	}
+	s.reindexArticle(id)
+	return nil
 }
 
 // CanView 检查用户是否可以查看文章
+// 密码保护和会员可见文章的标题等信息对所有人可见，正文是否可读由 canReadContent 判断
 func (s *ArticleService) CanView(article *model.Article, userID *uint) bool {
 	// 已发布的文章所有人都可以查看
 	if article.Status == model.ArticleStatusPublished {
@@ -593,20 +949,117 @@ func (s *ArticleService) CanView(article *model.Article, userID *uint) bool {
 		return false
 	}
 
-	// 作者可以查看自己的所有文章
-	if article.AuthorID == *userID {
+	// 作者可以查看自己的所有文章，协作者可以查看共享的文章
+	if (article.AuthorID == *userID || s.collaboratorRole(article.ID, *userID).CanView()) && s.scopeAllows(PermissionArticleRead) {
 		return true
 	}
 
 	// 管理员可以查看所有文章
 	user, err := s.userRepo.GetByID(*userID)
-	if err == nil && s.rbacService.HasPermission(user.Role, PermissionArticleManage) {
+	if err == nil && s.hasPermission(user, PermissionArticleManage) {
 		return true
 	}
 
 	return false
 }
 
+// canReadContent 检查用户是否可以阅读文章正文（调用前已通过 CanView 检查）
+// 作者、协作者和管理员不受可见范围限制；会员可见文章需要登录；密码保护文章需要有效的解锁令牌
+func (s *ArticleService) canReadContent(article *model.Article, userID *uint, unlockToken string) bool {
+	if !article.Visibility.IsProtected() {
+		return true
+	}
+	if article.Visibility == model.VisibilityPassword && s.verifyUnlockToken(article, unlockToken) {
+		return true
+	}
+	if userID == nil {
+		return false
+	}
+	if article.Visibility == model.VisibilityMembers {
+		return true
+	}
+	if (article.AuthorID == *userID || s.collaboratorRole(article.ID, *userID).CanView()) && s.scopeAllows(PermissionArticleRead) {
+		return true
+	}
+	user, err := s.userRepo.GetByID(*userID)
+	return err == nil && s.hasPermission(user, PermissionArticleManage)
+}
+
+// protectArticle 用户无权阅读正文时隐藏正文（密码保护文章同时隐藏摘要），并标记为已锁定
+func (s *ArticleService) protectArticle(article *model.Article, userID *uint, unlockToken string) {
+	if s.canReadContent(article, userID, unlockToken) {
+		return
+	}
+	article.Content = ""
+	article.ContentHTML = ""
+	article.TOC = nil
+	if article.Visibility == model.VisibilityPassword {
+		article.Summary = ""
+	}
+	article.Locked = true
+}
+
+// protectArticles 对列表中的文章逐篇隐藏无权阅读的正文（列表接口不接受解锁令牌）
+func (s *ArticleService) protectArticles(articles []*model.Article, userID *uint) {
+	for _, article := range articles {
+		s.protectArticle(article, userID, "")
+	}
+}
+
+// signUnlockToken 生成解锁令牌：文章ID.过期时间戳.签名
+// 签名包含密码哈希，修改密码后已发放的令牌全部失效
+func (s *ArticleService) signUnlockToken(article *model.Article, expiresAt time.Time) string {
+	payload := fmt.Sprintf("%d.%d", article.ID, expiresAt.Unix())
+	return payload + "." + s.unlockSignature(article, payload)
+}
+
+// verifyUnlockToken 校验解锁令牌是否属于该文章且未过期
+func (s *ArticleService) verifyUnlockToken(article *model.Article, token string) bool {
+	if token == "" || article.PasswordHash == "" {
+		return false
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.unlockSignature(article, payload))) {
+		return false
+	}
+	id, err1 := strconv.ParseUint(parts[0], 10, 64)
+	exp, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil || uint(id) != article.ID {
+		return false
+	}
+	return time.Now().Before(time.Unix(exp, 0))
+}
+
+// unlockSignature 计算解锁令牌的 HMAC-SHA256 签名（URL安全的Base64编码）
+func (s *ArticleService) unlockSignature(article *model.Article, payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.unlockSecret()))
+	mac.Write([]byte(payload + "." + article.PasswordHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// unlockSecret 解锁令牌签名密钥，未配置时使用 JWT 访问令牌密钥
+func (s *ArticleService) unlockSecret() string {
+	if s.config == nil {
+		return ""
+	}
+	if s.config.Article.Unlock.Secret != "" {
+		return s.config.Article.Unlock.Secret
+	}
+	return s.config.JWT.AccessSecret
+}
+
+// unlockTTL 解锁令牌有效期
+func (s *ArticleService) unlockTTL() time.Duration {
+	if s.config != nil && s.config.Article.Unlock.TTLMinutes > 0 {
+		return time.Duration(s.config.Article.Unlock.TTLMinutes) * time.Minute
+	}
+	return 120 * time.Minute
+}
+
 // CanEdit 检查用户是否可以编辑文章
 func (s *ArticleService) CanEdit(article *model.Article, userID uint) bool {
 	user, err := s.userRepo.GetByID(userID)
@@ -616,14 +1069,40 @@ func (s *ArticleService) CanEdit(article *model.Article, userID uint) bool {
 
 	// 作者可以编辑自己的文章
 	if article.AuthorID == userID {
-		return s.rbacService.HasPermission(user.Role, PermissionArticleCreate)
+		return s.hasPermission(user, PermissionArticleCreate)
+	}
+
+	// 拥有编辑或发布协作权限的协作者可以编辑
+	if s.collaboratorRole(article.ID, userID).CanEdit() && s.hasPermission(user, PermissionArticleUpdate) {
+		return true
 	}
 
 	// 管理员可以编辑所有文章
-	return s.rbacService.HasPermission(user.Role, PermissionArticleManage)
+	return s.hasPermission(user, PermissionArticleManage)
+}
+
+// CanPublish 检查用户是否可以变更文章发布状态
+func (s *ArticleService) CanPublish(article *model.Article, userID uint) bool {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false
+	}
+
+	// 作者可以发布自己的文章
+	if article.AuthorID == userID {
+		return s.hasPermission(user, PermissionArticleCreate)
+	}
+
+	// 拥有发布协作权限的协作者可以发布
+	if s.collaboratorRole(article.ID, userID).CanPublish() && s.hasPermission(user, PermissionArticlePublish) {
+		return true
+	}
+
+	// 管理员可以发布所有文章
+	return s.hasPermission(user, PermissionArticleManage)
 }
 
-// CanDelete 检查用户是否可以删除文章
+// CanDelete 检查用户是否可以删除文章（协作者不能删除文章）
 func (s *ArticleService) CanDelete(article *model.Article, userID uint) bool {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
@@ -632,33 +1111,823 @@ func (s *ArticleService) CanDelete(article *model.Article, userID uint) bool {
 
 	// 作者可以删除自己的文章
 	if article.AuthorID == userID {
-		return s.rbacService.HasPermission(user.Role, PermissionArticleCreate)
+		return s.hasPermission(user, PermissionArticleCreate)
 	}
 
 	// 管理员可以删除所有文章
-	return s.rbacService.HasPermission(user.Role, PermissionArticleManage)
+	return s.hasPermission(user, PermissionArticleManage)
 }
 
-// 私有辅助方法
-
-// processContent 处理文章内容
-func (s *ArticleService) processContent(article *model.Article) error {
-	// 清理和转义内容
-	article.Content = html.EscapeString(article.Content)
-	article.Summary = html.EscapeString(article.Summary)
+// WithTokenScopes 返回按个人访问令牌授权范围校验权限的服务副本
+// 令牌的有效权限为授权范围与所属用户角色权限的交集，避免仅授权创建文章的令牌借助管理员角色修改任意文章
+func (s *ArticleService) WithTokenScopes(scopes []Permission) ArticleServiceInterface {
+	scoped := *s
+	scoped.scoped = true
+	scoped.tokenScopes = scopes
+	return &scoped
+}
 
-	// 计算字数
-	article.WordCount = uint(len(strings.Fields(article.Content)))
+// hasPermission 检查用户是否拥有权限，通过个人访问令牌调用时还要求授权范围包含该权限
+func (s *ArticleService) hasPermission(user *repository.User, permission Permission) bool {
+	return s.rbacService.HasPermission(user.Role, permission) && s.scopeAllows(permission)
+}
 
-	// 计算阅读时间（假设每分钟200字）
-	article.ReadingTime = article.WordCount / 200
-	if article.ReadingTime == 0 {
-		article.ReadingTime = 1
+// scopeAllows 检查个人访问令牌的授权范围是否包含权限，账号登录时始终返回 true
+func (s *ArticleService) scopeAllows(permission Permission) bool {
+	if !s.scoped {
+		return true
+	}
+	for _, scope := range s.tokenScopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManageCollaborators 检查用户是否可以管理文章协作者（仅作者和管理员）
+func (s *ArticleService) CanManageCollaborators(article *model.Article, userID uint) bool {
+	return s.CanDelete(article, userID)
+}
+
+// InviteCollaborator 邀请协作者，已是协作者时更新其协作权限
+func (s *ArticleService) InviteCollaborator(articleID uint, req *InviteCollaboratorRequest, operatorID uint) (*model.ArticleCollaborator, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.CanManageCollaborators(article, operatorID) {
+		return nil, errors.New("没有管理此文章协作者的权限")
+	}
+
+	if req.UserID == article.AuthorID {
+		return nil, errors.New("不能邀请文章作者作为协作者")
+	}
+
+	invitee, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if invitee.Status != 1 {
+		return nil, errors.New("该用户已被禁用")
+	}
+
+	collaborator := &model.ArticleCollaborator{
+		ArticleID: articleID,
+		UserID:    req.UserID,
+		Role:      model.CollaboratorRole(req.Role),
+		InvitedBy: operatorID,
+	}
+	if err := s.collaboratorRepo.Upsert(collaborator); err != nil {
+		return nil, err
+	}
+
+	return collaborator, nil
+}
+
+// RemoveCollaborator 移除协作者，协作者也可以主动退出
+func (s *ArticleService) RemoveCollaborator(articleID, collaboratorID, operatorID uint) error {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return err
+	}
+
+	if collaboratorID != operatorID && !s.CanManageCollaborators(article, operatorID) {
+		return errors.New("没有管理此文章协作者的权限")
+	}
+
+	return s.collaboratorRepo.Remove(articleID, collaboratorID)
+}
+
+// GetCollaborators 获取文章协作者列表
+func (s *ArticleService) GetCollaborators(articleID uint, userID *uint) ([]model.ArticleCollaborator, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.CanView(article, userID) {
+		return nil, errors.New("没有查看此文章的权限")
+	}
+
+	return s.collaboratorRepo.ListByArticle(articleID)
+}
+
+// GetSharedArticles 获取共享给当前用户协作的文章
+func (s *ArticleService) GetSharedArticles(userID uint, req *GetArticleListRequest) (*ArticleListResponse, error) {
+	if !s.scopeAllows(PermissionArticleRead) {
+		return nil, errors.New("没有查看共享文章的权限")
+	}
+
+	params := &repository.ArticleListParams{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Status:   model.ArticleStatus(req.Status),
+	}
+
+	articles, total, err := s.collaboratorRepo.ListSharedArticles(userID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArticleListResponse{
+		Articles: articles,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// ListRevisions 获取文章修订列表
+func (s *ArticleService) ListRevisions(articleID uint, userID uint) ([]*model.ArticleRevision, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 修订中可能包含未发布内容，只对可编辑的用户开放
+	if !s.CanEdit(article, userID) {
+		return nil, errors.New("没有查看此文章修订历史的权限")
+	}
+
+	return s.revisionRepo.ListByArticle(articleID)
+}
+
+// DiffRevisions 比较文章的两个修订
+func (s *ArticleService) DiffRevisions(req *DiffRevisionsRequest, userID uint) (*RevisionDiffResponse, error) {
+	article, err := s.articleRepo.GetByID(req.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.CanEdit(article, userID) {
+		return nil, errors.New("没有查看此文章修订历史的权限")
+	}
+
+	from, err := s.revisionRepo.GetByNumber(req.ArticleID, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.revisionRepo.GetByNumber(req.ArticleID, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RevisionDiffResponse{
+		ArticleID: req.ArticleID,
+		From:      from.Number,
+		To:        to.Number,
+		Mode:      req.Mode,
+		TitleFrom: from.Title,
+		TitleTo:   to.Title,
+	}
+	if result.Mode == "word" {
+		result.Edits = diff.Words(from.Content, to.Content)
+	} else {
+		result.Mode = "unified"
+		result.Unified = diff.Unified(from.Content, to.Content,
+			fmt.Sprintf("#%d", from.Number), fmt.Sprintf("#%d", to.Number), 3)
+	}
+
+	return result, nil
+}
+
+// RestoreRevision 将文章恢复到指定修订，恢复结果作为新的修订保存
+func (s *ArticleService) RestoreRevision(articleID, number uint, userID uint) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.CanEdit(article, userID) {
+		return nil, errors.New("没有编辑此文章的权限")
+	}
+
+	revision, err := s.revisionRepo.GetByNumber(articleID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureBaseRevision(article); err != nil {
+		return nil, err
+	}
+
+	article.Title = revision.Title
+	article.Summary = revision.Summary
+	article.Content = revision.Content
+
+	if err := s.processContent(article); err != nil {
+		return nil, err
+	}
+	if err := s.updateArticle(article); err != nil {
+		return nil, err
+	}
+	if err := s.saveRevision(article, userID, fmt.Sprintf("恢复自修订 #%d", revision.Number)); err != nil {
+		return nil, err
+	}
+
+	return s.reloadIndexed(article.ID)
+}
+
+// SaveDraft 自动保存草稿，不修改正式文章
+func (s *ArticleService) SaveDraft(req *SaveDraftRequest, userID uint) (*model.ArticleDraft, error) {
+	draft := &model.ArticleDraft{
+		ArticleID:   req.ArticleID,
+		UserID:      userID,
+		Title:       req.Title,
+		Summary:     req.Summary,
+		Content:     req.Content,
+		BaseVersion: req.BaseVersion,
+	}
+
+	if req.ArticleID == 0 {
+		// 新文章草稿：需要创建文章的权限
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, errors.New("用户不存在")
+		}
+		if !s.hasPermission(user, PermissionArticleCreate) {
+			return nil, errors.New("没有创建文章的权限")
+		}
+		draft.BaseVersion = 0
+	} else {
+		article, err := s.articleRepo.GetByID(req.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+		if !s.CanEdit(article, userID) {
+			return nil, errors.New("没有编辑此文章的权限")
+		}
+		if draft.BaseVersion == 0 {
+			draft.BaseVersion = article.Version
+		}
+	}
+
+	if err := s.draftRepo.Save(draft); err != nil {
+		return nil, err
+	}
+	return s.draftRepo.Get(draft.ArticleID, userID)
+}
+
+// GetDraft 获取当前用户在文章上的自动保存草稿，并标记草稿是否落后于文章当前版本
+func (s *ArticleService) GetDraft(articleID uint, userID uint) (*ArticleDraftResponse, error) {
+	resp := &ArticleDraftResponse{}
+	if articleID != 0 {
+		article, err := s.articleRepo.GetByID(articleID)
+		if err != nil {
+			return nil, err
+		}
+		if !s.CanEdit(article, userID) {
+			return nil, errors.New("没有编辑此文章的权限")
+		}
+		resp.CurrentVersion = article.Version
 	}
 
+	draft, err := s.draftRepo.Get(articleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp.Draft = draft
+	resp.Outdated = articleID != 0 && draft.BaseVersion < resp.CurrentVersion
+	return resp, nil
+}
+
+// DiscardDraft 丢弃当前用户在文章上的自动保存草稿
+func (s *ArticleService) DiscardDraft(articleID uint, userID uint) error {
+	return s.draftRepo.Delete(articleID, userID)
+}
+
+// AcquireEditLock 获取文章编辑锁
+// 锁只是提示性的：获取失败时返回当前持有人，由编辑器提示“某人正在编辑”，不阻止保存
+func (s *ArticleService) AcquireEditLock(req *EditLockRequest, userID uint) (*EditLockResponse, error) {
+	article, err := s.articleRepo.GetByID(req.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanEdit(article, userID) {
+		return nil, errors.New("没有编辑此文章的权限")
+	}
+
+	now := time.Now()
+	lock := &model.ArticleEditLock{
+		ArticleID:   req.ArticleID,
+		UserID:      userID,
+		SessionID:   req.SessionID,
+		AcquiredAt:  now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(s.editLockTTL()),
+	}
+	acquired, err := s.editLockRepo.Acquire(lock, req.Force)
+	if err != nil {
+		return nil, err
+	}
+	return s.editLockResponse(req.ArticleID, acquired)
+}
+
+// HeartbeatEditLock 编辑锁心跳续期
+// 锁已被他人接管时按普通获取处理：对方的锁已过期则重新获取，否则返回当前持有人
+func (s *ArticleService) HeartbeatEditLock(req *EditLockRequest, userID uint) (*EditLockResponse, error) {
+	now := time.Now()
+	refreshed, err := s.editLockRepo.Refresh(req.ArticleID, userID, req.SessionID, now, now.Add(s.editLockTTL()))
+	if err != nil {
+		return nil, err
+	}
+	if !refreshed {
+		return s.AcquireEditLock(&EditLockRequest{ArticleID: req.ArticleID, SessionID: req.SessionID}, userID)
+	}
+	return s.editLockResponse(req.ArticleID, true)
+}
+
+// ReleaseEditLock 释放编辑锁
+func (s *ArticleService) ReleaseEditLock(req *EditLockRequest, userID uint) error {
+	return s.editLockRepo.Release(req.ArticleID, userID, req.SessionID)
+}
+
+// GetEditLock 查询文章当前的编辑锁
+func (s *ArticleService) GetEditLock(articleID uint, userID uint) (*EditLockResponse, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.CanEdit(article, userID) {
+		return nil, errors.New("没有编辑此文章的权限")
+	}
+	return s.editLockResponse(articleID, false)
+}
+
+// editLockResponse 组装编辑锁响应，已过期的锁视为无人编辑
+func (s *ArticleService) editLockResponse(articleID uint, acquired bool) (*EditLockResponse, error) {
+	lock, err := s.editLockRepo.Get(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if lock != nil && lock.IsExpired(time.Now()) {
+		lock = nil
+	}
+	return &EditLockResponse{Acquired: acquired, Lock: lock}, nil
+}
+
+// editLockTTL 编辑锁有效期
+func (s *ArticleService) editLockTTL() time.Duration {
+	if s.config != nil && s.config.Article.EditLock.TTLSeconds > 0 {
+		return time.Duration(s.config.Article.EditLock.TTLSeconds) * time.Second
+	}
+	return 90 * time.Second
+}
+
+// RerenderArticles 使用当前渲染器重新渲染文章
+// 默认只处理渲染版本落后的文章，force 为 true 时全部重新渲染
+func (s *ArticleService) RerenderArticles(force bool) (*RerenderResult, error) {
+	const batchSize = 100
+	result := &RerenderResult{Version: markdown.Version}
+
+	var lastID uint
+	for {
+		articles, err := s.articleRepo.ListForRender(lastID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			lastID = article.ID
+			result.Scanned++
+			if !force && article.RenderVersion >= markdown.Version {
+				continue
+			}
+
+			// 旧版本保存时对原文和摘要做过HTML转义，还原后随渲染结果一起写回
+			// 写入以读取时的版本号为条件，不会覆盖并发的编辑
+			legacy := article.RenderVersion == 0
+			if legacy {
+				article.Content = html.UnescapeString(article.Content)
+				article.Summary = html.UnescapeString(article.Summary)
+			}
+
+			if err := s.processContent(article); err != nil {
+				result.Failed++
+				continue
+			}
+			if err := s.articleRepo.UpdateRendered(article, legacy); err != nil {
+				result.Failed++
+				continue
+			}
+			if legacy {
+				// 原文已变化，搜索索引按还原后的内容更新
+				s.reindexArticle(article.ID)
+			}
+			result.Rendered++
+		}
+		if len(articles) < batchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// 私有辅助方法
+
+// PublishDueArticles 发布已到计划时间的定时文章，并通知作者的关注者
+// 每篇文章的发布以条件更新抢占，多实例同时运行时同一篇文章只会被发布和通知一次
+func (s *ArticleService) PublishDueArticles() (*ScheduleResult, error) {
+	now := time.Now()
+	articles, err := s.articleRepo.ListDueScheduled(now, s.scheduleBatchSize())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScheduleResult{Due: len(articles)}
+	for _, article := range articles {
+		published, err := s.articleRepo.PublishScheduled(article, now, newArticleNotification(article))
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if published {
+			result.Published++
+			s.reindexArticle(article.ID)
+		}
+	}
+	return result, nil
+}
+
+// ArchiveExpiredArticles 归档已过下线时间的文章
+func (s *ArticleService) ArchiveExpiredArticles() (int64, error) {
+	ids, err := s.articleRepo.ArchiveExpired(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.reindexArticle(id)
+	}
+	return int64(len(ids)), nil
+}
+
+// ReindexArticles 重建文章搜索索引
+func (s *ArticleService) ReindexArticles() (*ReindexResult, error) {
+	start := time.Now()
+	indexed, err := s.searchBackend.Rebuild()
+	if err != nil {
+		return nil, fmt.Errorf("重建搜索索引失败: %w", err)
+	}
+	return &ReindexResult{
+		Backend:    s.searchBackend.Name(),
+		Indexed:    indexed,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// reloadIndexed 重新获取完整的文章信息并更新搜索索引
+func (s *ArticleService) reloadIndexed(id uint) (*model.Article, error) {
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.indexArticle(article)
+	return article, nil
+}
+
+// reindexArticle 按数据库中的最新状态更新文章的搜索索引
+func (s *ArticleService) reindexArticle(id uint) {
+	article, err := s.articleRepo.GetByID(id)
+	if err != nil {
+		log.Printf("更新文章搜索索引失败(文章ID=%d): %v", id, err)
+		return
+	}
+	s.indexArticle(article)
+}
+
+// indexArticle 更新文章的搜索索引并使归档统计缓存失效，失败只记录日志，不影响文章本身的修改
+// 索引与数据库不一致时可通过重建索引修复
+func (s *ArticleService) indexArticle(article *model.Article) {
+	if err := s.searchBackend.Index(article); err != nil {
+		log.Printf("更新文章搜索索引失败(文章ID=%d): %v", article.ID, err)
+	}
+	s.invalidateArchives()
+}
+
+// scheduleBatchSize 每次扫描处理的最大文章数
+func (s *ArticleService) scheduleBatchSize() int {
+	if s.config != nil && s.config.Article.Schedule.BatchSize > 0 {
+		return s.config.Article.Schedule.BatchSize
+	}
+	return 100
+}
+
+// makeSlug 生成文章Slug：优先规范化指定的Slug，为空时由标题转写，仍为空时使用短哈希
+func (s *ArticleService) makeSlug(custom, title string) string {
+	opts := slug.Options{MaxLength: s.slugMaxLength()}
+	if result := slug.Clean(custom, opts); result != "" {
+		return result
+	}
+	return slug.Make(title, opts)
+}
+
+// listStatus 列表和搜索的状态条件：拥有文章管理权限的用户可以按任意状态筛选，其他用户只能看到已发布的文章
+func (s *ArticleService) listStatus(status string, userID *uint) model.ArticleStatus {
+	if userID == nil {
+		return model.ArticleStatusPublished
+	}
+	user, err := s.userRepo.GetByID(*userID)
+	if err != nil || !s.hasPermission(user, PermissionArticleManage) {
+		return model.ArticleStatusPublished
+	}
+	return model.ArticleStatus(status)
+}
+
+// toParams 将列表请求转换为仓库层查询参数，不含状态、关键词和访问者相关的字段
+func (req *GetArticleListRequest) toParams() (*repository.ArticleListParams, error) {
+	params := &repository.ArticleListParams{
+		Page:             req.Page,
+		PageSize:         req.PageSize,
+		AuthorID:         req.AuthorID,
+		CategoryID:       req.CategoryID,
+		TagID:            req.TagID,
+		SortBy:           req.SortBy,
+		Order:            req.Order,
+		CategoryIDs:      req.CategoryIDs,
+		CategoryMatchAll: req.CategoryMatch == "all",
+		TagIDs:           req.TagIDs,
+		TagMatchAll:      req.TagMatch == "all",
+		IsFeatured:       req.IsFeatured,
+		IsTop:            req.IsTop,
+		MinWordCount:     req.MinWordCount,
+		MaxWordCount:     req.MaxWordCount,
+		MinReadingTime:   req.MinReadingTime,
+		MaxReadingTime:   req.MaxReadingTime,
+	}
+
+	if req.PublishedFrom != "" {
+		t, _, err := parseQueryTime(req.PublishedFrom)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 发布开始时间格式错误: %v", ErrInvalidListFilter, err)
+		}
+		params.PublishedFrom = &t
+	}
+	if req.PublishedTo != "" {
+		t, dateOnly, err := parseQueryTime(req.PublishedTo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 发布结束时间格式错误: %v", ErrInvalidListFilter, err)
+		}
+		// 只给日期时包含当天全部文章
+		if dateOnly {
+			t = t.Add(24*time.Hour - time.Millisecond)
+		}
+		params.PublishedTo = &t
+	}
+	if params.PublishedFrom != nil && params.PublishedTo != nil && params.PublishedTo.Before(*params.PublishedFrom) {
+		return nil, fmt.Errorf("%w: 发布结束时间不能早于开始时间", ErrInvalidListFilter)
+	}
+	if req.MaxWordCount > 0 && req.MaxWordCount < req.MinWordCount {
+		return nil, fmt.Errorf("%w: 最大字数不能小于最小字数", ErrInvalidListFilter)
+	}
+	if req.MaxReadingTime > 0 && req.MaxReadingTime < req.MinReadingTime {
+		return nil, fmt.Errorf("%w: 最长阅读时间不能小于最短阅读时间", ErrInvalidListFilter)
+	}
+
+	cursor, err := req.listCursor()
+	if err != nil {
+		return nil, err
+	}
+	params.Cursor = cursor
+
+	return params, nil
+}
+
+// listCursor 解析请求中的分页游标，游标须与请求的排序方式一致
+func (req *GetArticleListRequest) listCursor() (*repository.ArticleCursor, error) {
+	if req.Cursor == "" {
+		return nil, nil
+	}
+	cursor, err := repository.DecodeArticleCursor(req.Cursor, req.SortBy, req.Order)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidListFilter, err)
+	}
+	return cursor, nil
+}
+
+// pageCursors 根据当前页查询到的文章（可见性过滤之前）生成翻页游标
+// 满页时认为之后可能还有文章；按游标向后翻页时之前一定有文章，向前翻页时之后一定有文章
+func pageCursors(articles []*model.Article, params *repository.ArticleListParams) (next, prev string) {
+	if len(articles) == 0 {
+		return "", ""
+	}
+	full := len(articles) >= params.PageSize
+	cursor := params.Cursor
+	backward := cursor != nil && cursor.Backward
+
+	if full || backward {
+		next = repository.NewArticleCursor(articles[len(articles)-1], params.SortBy, params.Order, false).Encode()
+	}
+	if (cursor == nil && params.Page > 1) || (cursor != nil && (!backward || full)) {
+		prev = repository.NewArticleCursor(articles[0], params.SortBy, params.Order, true).Encode()
+	}
+	return next, prev
+}
+
+// searchWeights 返回全文搜索相关度权重，未配置时由仓储层使用默认值
+func (s *ArticleService) searchWeights() repository.SearchWeights {
+	if s.config == nil {
+		return repository.SearchWeights{}
+	}
+	cfg := s.config.Article.Search
+	return repository.SearchWeights{
+		Title:   cfg.TitleWeight,
+		Summary: cfg.SummaryWeight,
+		Content: cfg.ContentWeight,
+	}
+}
+
+// highlightArticles 为搜索结果生成高亮片段
+// 必须在 protectArticles 之后调用，被隐藏的正文和摘要已清空，不会出现在片段中
+func (s *ArticleService) highlightArticles(articles []*model.Article, keyword string) {
+	terms := search.ParseQuery(keyword).HighlightTerms()
+	if len(terms) == 0 {
+		return
+	}
+	length := 120
+	if s.config != nil && s.config.Article.Search.SnippetLength > 0 {
+		length = s.config.Article.Search.SnippetLength
+	}
+
+	for _, article := range articles {
+		highlight := &model.ArticleHighlight{
+			Title:   search.Highlight(article.Title, terms),
+			Summary: search.Snippet(article.Summary, terms, length),
+		}
+		if article.Content != "" {
+			highlight.Content = search.Snippet(markdown.PlainText(article.Content), terms, length)
+		}
+		if *highlight != (model.ArticleHighlight{}) {
+			article.Highlight = highlight
+		}
+	}
+}
+
+// slugMaxLength Slug 最大长度，为重复时追加的后缀预留空间
+func (s *ArticleService) slugMaxLength() int {
+	const limit = 180
+	if s.config == nil || s.config.Article.Slug.MaxLength <= 0 {
+		return slug.DefaultMaxLength
+	}
+	return min(s.config.Article.Slug.MaxLength, limit)
+}
+
+// applySchedule 校验并设置计划发布时间和下线时间
+// 定时发布必须指定未来的发布时间；其他状态忽略请求中的发布时间，由发布操作决定
+// 未指定下线时间时保持原值，clearExpiresAt 为 true 时清除
+func applySchedule(article *model.Article, publishedAt, expiresAt *time.Time, clearExpiresAt bool) error {
+	if clearExpiresAt && expiresAt != nil {
+		return errors.New("不能同时设置和清除下线时间")
+	}
+
+	now := time.Now()
+	switch article.Status {
+	case model.ArticleStatusScheduled:
+		if publishedAt == nil || !publishedAt.After(now) {
+			return errors.New("定时发布需要设置晚于当前的发布时间")
+		}
+		article.PublishedAt = publishedAt
+	case model.ArticleStatusPublished:
+		// 从定时发布直接改为发布时，计划时间尚未到达，改为立即发布
+		if article.PublishedAt != nil && article.PublishedAt.After(now) {
+			article.PublishedAt = &now
+		}
+	}
+
+	switch {
+	case clearExpiresAt:
+		article.ExpiresAt = nil
+	case expiresAt != nil:
+		start := now
+		if article.Status == model.ArticleStatusScheduled {
+			start = *article.PublishedAt
+		}
+		if !expiresAt.After(start) {
+			return errors.New("下线时间必须晚于发布时间")
+		}
+		article.ExpiresAt = expiresAt
+	case article.ExpiresAt != nil && article.Status == model.ArticleStatusScheduled:
+		// 沿用原下线时间时，仍需晚于新的计划发布时间
+		if !article.ExpiresAt.After(*article.PublishedAt) {
+			return errors.New("下线时间必须晚于发布时间")
+		}
+	}
+	return nil
+}
+
+// applyVisibility 校验并设置可见范围，visibility 为空时保持原值（新文章默认公开）
+// 设为密码保护时必须提供密码，已有密码的文章可以不传以沿用原密码
+func applyVisibility(article *model.Article, visibility, password string) error {
+	if visibility != "" {
+		article.Visibility = model.Visibility(visibility)
+	}
+	if article.Visibility == "" {
+		article.Visibility = model.VisibilityPublic
+	}
+
+	if article.Visibility != model.VisibilityPassword {
+		article.PasswordHash = ""
+		return nil
+	}
+	if password == "" {
+		if article.PasswordHash == "" {
+			return errors.New("密码保护文章需要设置访问密码")
+		}
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), BcryptCost)
+	if err != nil {
+		return fmt.Errorf("访问密码加密失败: %w", err)
+	}
+	article.PasswordHash = string(hash)
+	return nil
+}
+
+// visibilityChanged 检查更新请求是否修改了可见范围或访问密码
+func visibilityChanged(article *model.Article, req *UpdateArticleRequest) bool {
+	if req.Password != "" {
+		return true
+	}
+	return req.Visibility != "" && model.Visibility(req.Visibility) != article.Visibility
+}
+
+// scheduleChanged 检查更新请求是否修改了计划发布时间或下线时间
+func scheduleChanged(article *model.Article, req *UpdateArticleRequest) bool {
+	if req.ClearExpiresAt && article.ExpiresAt != nil {
+		return true
+	}
+	if req.ExpiresAt != nil && !sameTime(article.ExpiresAt, req.ExpiresAt) {
+		return true
+	}
+	return req.Status == string(model.ArticleStatusScheduled) && !sameTime(article.PublishedAt, req.PublishedAt)
+}
+
+// sameTime 比较两个可空时间是否相同
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// newArticleNotification 构建定时文章发布后发给关注者的通知
+func newArticleNotification(article *model.Article) *model.Notification {
+	relatedType := model.ResourceArticle
+	content := article.Summary
+	if article.Visibility == model.VisibilityPassword {
+		// 密码保护文章的摘要同样不公开
+		content = ""
+	}
+	return &model.Notification{
+		Type:        model.NotificationTypeArticleNew,
+		Title:       fmt.Sprintf("你关注的作者发布了新文章《%s》", article.Title),
+		Content:     &content,
+		RelatedType: &relatedType,
+		RelatedID:   &article.ID,
+	}
+}
+
+// updateArticle 按文章当前版本号保存，版本冲突时转换为携带最新版本号的冲突错误
+func (s *ArticleService) updateArticle(article *model.Article) error {
+	err := s.articleRepo.Update(article)
+	if !errors.Is(err, repository.ErrArticleVersionConflict) {
+		return err
+	}
+	current, verr := s.articleRepo.GetVersion(article.ID)
+	if verr != nil {
+		return verr
+	}
+	return &ArticleConflictError{ArticleID: article.ID, CurrentVersion: current}
+}
+
+// processContent 处理文章内容
+func (s *ArticleService) processContent(article *model.Article) error {
+	// Markdown 原文按原样保存，渲染出的HTML已清洗，可直接输出
+	rendered, err := markdown.Render(article.Content)
+	if err != nil {
+		return err
+	}
+	article.ContentHTML = rendered.HTML
+	article.RenderVersion = markdown.Version
+
+	// 目录随正文一起缓存，没有标题时不保存
+	article.TOC = nil
+	if len(rendered.TOC) > 0 {
+		toc, err := json.Marshal(rendered.TOC)
+		if err != nil {
+			return fmt.Errorf("序列化文章目录失败: %w", err)
+		}
+		article.TOC = toc
+	}
+
+	// 计算字数和阅读时间（中文逐字计数，英文按单词计数，代码按行单独计时）
+	article.WordCount = rendered.Stats.WordCount()
+	article.ReadingTime = rendered.Stats.ReadingMinutes()
+
 	// 如果没有摘要，从内容中提取
 	if article.Summary == "" {
-		article.Summary = s.extractSummary(article.Content, 200)
+		article.Summary = s.extractSummary(rendered.Text, 200)
 	}
 
 	return nil
@@ -670,20 +1939,70 @@ func (s *ArticleService) extractSummary(content string, maxLength int) string {
 	re := regexp.MustCompile(`<[^>]*>`)
 	text := re.ReplaceAllString(content, "")
 
-	// 限制长度
-	if len(text) > maxLength {
-		text = text[:maxLength] + "..."
+	// 限制长度（按字符截断，避免截断多字节字符）
+	if runes := []rune(text); len(runes) > maxLength {
+		text = string(runes[:maxLength]) + "..."
 	}
 
 	return text
 }
 
-// containsArticle 检查文章数组是否包含指定ID的文章
-func containsArticle(articles []*model.Article, id uint) bool {
-	for _, article := range articles {
-		if article.ID == id {
-			return true
+// saveRevision 保存文章当前内容为新修订，并按保留策略清理旧修订
+func (s *ArticleService) saveRevision(article *model.Article, editorID uint, note string) error {
+	revision := &model.ArticleRevision{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Summary:   article.Summary,
+		Content:   article.Content,
+		EditorID:  editorID,
+		Note:      note,
+	}
+	if err := s.revisionRepo.Create(revision); err != nil {
+		return err
+	}
+
+	// 保留期内的修订全部保留，更早的每天只保留最后一个
+	if keepDays := s.revisionKeepAllDays(); keepDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -keepDays)
+		if _, err := s.revisionRepo.PruneBefore(article.ID, cutoff); err != nil {
+			return err
 		}
 	}
-	return false
+	return nil
+}
+
+// revisionKeepAllDays 保留全部修订的天数，0 表示不清理；未加载配置时不清理
+func (s *ArticleService) revisionKeepAllDays() int {
+	if s.config != nil {
+		return s.config.Article.Revision.KeepAllDays
+	}
+	return 0
+}
+
+// ensureBaseRevision 文章还没有任何修订时，把当前内容保存为初始修订
+func (s *ArticleService) ensureBaseRevision(article *model.Article) error {
+	count, err := s.revisionRepo.CountByArticle(article.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return s.saveRevision(article, article.AuthorID, "初始版本")
+}
+
+// collaboratorRole 获取用户在文章中的协作权限
+func (s *ArticleService) collaboratorRole(articleID, userID uint) model.CollaboratorRole {
+	role, err := s.collaboratorRepo.GetRole(articleID, userID)
+	if err != nil {
+		return ""
+	}
+	return role
+}
+
+// loadCollaborators 为文章详情填充协作者列表
+func (s *ArticleService) loadCollaborators(article *model.Article) {
+	if collaborators, err := s.collaboratorRepo.ListByArticle(article.ID); err == nil {
+		article.Collaborators = collaborators
+	}
 }
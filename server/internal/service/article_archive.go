@@ -0,0 +1,221 @@
+package service
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"MyBlog/internal/cache"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// ArchiveMonth 某月已发布的文章数
+type ArchiveMonth struct {
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// ArchiveYear 某年已发布的文章数及各月明细（月份倒序，只含有文章的月份）
+type ArchiveYear struct {
+	Year   int             `json:"year"`
+	Count  int64           `json:"count"`
+	Months []*ArchiveMonth `json:"months"`
+}
+
+// ArchiveSummary 按年月归档的文章数
+type ArchiveSummary struct {
+	Years []*ArchiveYear `json:"years"`
+	Total int64          `json:"total"`
+}
+
+// ArchiveMonthRequest 某月归档文章列表请求
+type ArchiveMonthRequest struct {
+	Year     int `json:"year" binding:"required,min=1970,max=9999"`
+	Month    int `json:"month" binding:"required,min=1,max=12"`
+	Page     int `json:"page" binding:"omitempty,min=1"`
+	PageSize int `json:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// ArchiveCalendarRequest 发文日历请求
+type ArchiveCalendarRequest struct {
+	Year int `json:"year" binding:"required,min=1970,max=9999"`
+}
+
+// ArchiveCalendar 某年每天的发布数，用于发文热力图
+type ArchiveCalendar struct {
+	Year     int                    `json:"year"`
+	Days     []*repository.DayCount `json:"days"`     // 只含有文章的日期，按日期升序
+	Total    int64                  `json:"total"`    // 全年发布数
+	MaxCount int64                  `json:"maxCount"` // 单日最多发布数，用于热力图配色分级
+}
+
+// GetArchives 获取按年月归档的已发布文章数
+func (s *ArticleService) GetArchives() (*ArchiveSummary, error) {
+	var months []*repository.MonthCount
+	key := cache.GetCacheKey(cache.CacheKeyArchiveMonths, s.archiveVersion())
+	err := s.archiveCached(key, &months, func() (err error) {
+		months, err = s.articleRepo.ArchiveMonths()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ArchiveSummary{Years: []*ArchiveYear{}}
+	for _, item := range months {
+		year, month, ok := parseArchiveMonth(item.Month)
+		if !ok {
+			continue
+		}
+		// 月份已按倒序排列，同一年的月份相邻
+		if n := len(summary.Years); n == 0 || summary.Years[n-1].Year != year {
+			summary.Years = append(summary.Years, &ArchiveYear{Year: year, Months: []*ArchiveMonth{}})
+		}
+		current := summary.Years[len(summary.Years)-1]
+		current.Months = append(current.Months, &ArchiveMonth{Month: month, Count: item.Count})
+		current.Count += item.Count
+		summary.Total += item.Count
+	}
+	return summary, nil
+}
+
+// GetArchiveMonth 获取某月发布的文章列表，按发布时间倒序
+func (s *ArticleService) GetArchiveMonth(req *ArchiveMonthRequest, userID *uint) (*ArticleListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	from := time.Date(req.Year, time.Month(req.Month), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	// 缓存当月的文章ID，文章内容每次从数据库加载
+	var ids []uint
+	key := cache.GetCacheKey(cache.CacheKeyArchiveMonthIDs, s.archiveVersion(), from.Format("2006-01"))
+	err := s.archiveCached(key, &ids, func() (err error) {
+		ids, err = s.articleRepo.PublishedIDsBetween(from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	articles, total, err := s.articleRepo.ListByIDs(ids, &repository.ArticleListParams{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Status:   model.ArticleStatusPublished,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.protectArticles(articles, userID)
+
+	return &ArticleListResponse{
+		Articles: articles,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// GetArchiveCalendar 获取某年每天的发布数
+func (s *ArticleService) GetArchiveCalendar(req *ArchiveCalendarRequest) (*ArchiveCalendar, error) {
+	from := time.Date(req.Year, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(1, 0, 0)
+
+	var days []*repository.DayCount
+	key := cache.GetCacheKey(cache.CacheKeyArchiveCalendar, s.archiveVersion(), req.Year)
+	err := s.archiveCached(key, &days, func() (err error) {
+		days, err = s.articleRepo.ArchiveDays(from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ArchiveCalendar{Year: req.Year, Days: days}
+	if calendar.Days == nil {
+		calendar.Days = []*repository.DayCount{}
+	}
+	for _, day := range calendar.Days {
+		calendar.Total += day.Count
+		if day.Count > calendar.MaxCount {
+			calendar.MaxCount = day.Count
+		}
+	}
+	return calendar, nil
+}
+
+// archiveCached 读取归档统计缓存，未命中时调用 load 填充 dest 并写入缓存
+// 缓存读写失败不影响结果，只是每次都查询数据库
+func (s *ArticleService) archiveCached(key string, dest interface{}, load func() error) error {
+	if s.cache != nil && s.cache.Get(key, dest) == nil {
+		return nil
+	}
+	if err := load(); err != nil {
+		return err
+	}
+	if s.cache != nil {
+		if err := s.cache.Set(key, dest, s.archiveCacheTTL()); err != nil {
+			log.Printf("写入归档统计缓存失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// archiveVersion 当前归档缓存版本号，缓存键中带版本号，更新版本号即可使全部归档缓存失效
+func (s *ArticleService) archiveVersion() int64 {
+	if s.cache == nil {
+		return 0
+	}
+	var version int64
+	if err := s.cache.Get(cache.CacheKeyArchiveVersion, &version); err == nil {
+		return version
+	}
+	version = time.Now().UnixNano()
+	if err := s.cache.Set(cache.CacheKeyArchiveVersion, version, 0); err != nil {
+		log.Printf("写入归档缓存版本失败: %v", err)
+	}
+	return version
+}
+
+// invalidateArchives 使归档统计缓存失效，文章发布、下线、删除或修改发布时间后调用
+// 旧版本的缓存不再被读取，到期后自然清理
+func (s *ArticleService) invalidateArchives() {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Set(cache.CacheKeyArchiveVersion, time.Now().UnixNano(), 0); err != nil {
+		log.Printf("更新归档缓存版本失败: %v", err)
+	}
+}
+
+// archiveCacheTTL 归档统计缓存有效期
+// 缓存和版本号都在进程内，其他实例的修改不会使本实例的缓存失效，有效期即多实例间的最大延迟，默认较短
+func (s *ArticleService) archiveCacheTTL() time.Duration {
+	if s.config != nil && s.config.Article.Archive.CacheTTLSeconds > 0 {
+		return time.Duration(s.config.Article.Archive.CacheTTLSeconds) * time.Second
+	}
+	return time.Minute
+}
+
+// parseArchiveMonth 解析 YYYY-MM 格式的月份
+func parseArchiveMonth(value string) (int, int, bool) {
+	yearText, monthText, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, false
+	}
+	year, err := strconv.Atoi(yearText)
+	if err != nil {
+		return 0, 0, false
+	}
+	month, err := strconv.Atoi(monthText)
+	if err != nil || month < 1 || month > 12 {
+		return 0, 0, false
+	}
+	return year, month, true
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"MyBlog/internal/cache"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/pkg/related"
+)
+
+// relatedBatchSize 计算相关文章时每批读取的文章数
+const relatedBatchSize = 500
+
+// RelatedResult 相关文章计算结果
+type RelatedResult struct {
+	Articles   int   `json:"articles"`   // 参与计算的已发布文章数
+	Cached     int   `json:"cached"`     // 写入缓存的文章数
+	DurationMs int64 `json:"durationMs"` // 耗时（毫秒）
+}
+
+// GetRelatedArticles 获取相关文章
+// 优先使用后台任务预先计算的结果；文章尚未参与计算（如刚发布）时按同样的规则单独计算
+func (s *ArticleService) GetRelatedArticles(articleID uint, limit int, userID *uint) ([]*model.Article, error) {
+	ids, err := s.relatedIDs(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []*model.Article{}, nil
+	}
+
+	// 缓存中的文章可能已下线，多取的部分用于补足
+	articles, _, err := s.articleRepo.ListByIDs(ids, &repository.ArticleListParams{
+		Page:     1,
+		PageSize: len(ids),
+		Status:   model.ArticleStatusPublished,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) > limit {
+		articles = articles[:limit]
+	}
+	s.protectArticles(articles, userID)
+	return articles, nil
+}
+
+// relatedIDs 获取文章的相关文章ID，按相关度降序
+// 缓存中没有时单独计算并写入缓存，下次后台计算时被覆盖；未发布的文章没有相关文章，也不触发计算
+func (s *ArticleService) relatedIDs(articleID uint) ([]uint, error) {
+	key := cache.GetCacheKey(cache.CacheKeyRelatedArticles, articleID)
+	var ids []uint
+	if s.cache != nil && s.cache.Get(key, &ids) == nil {
+		return ids, nil
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != model.ArticleStatusPublished {
+		return nil, nil
+	}
+
+	// 标签和词的权重依赖全部文章，单篇计算同样需要读取全部已发布文章
+	items, err := s.relatedItems()
+	if err != nil {
+		return nil, err
+	}
+	matches := related.ComputeFor(items, articleID, s.relatedWeights(), s.relatedMaxPerArticle())
+	ids = make([]uint, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+
+	if s.cache != nil {
+		if err := s.cache.Set(key, ids, s.relatedCacheTTL()); err != nil {
+			log.Printf("写入相关文章缓存失败: %v", err)
+		}
+	}
+	return ids, nil
+}
+
+// RebuildRelated 重新计算全部已发布文章的相关文章并写入缓存
+// 相关度综合共同标签（按稀有度加权）、共同分类以及标题和摘要的 TF-IDF 相似度，见 pkg/related
+func (s *ArticleService) RebuildRelated() (*RelatedResult, error) {
+	start := time.Now()
+
+	items, err := s.relatedItems()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RelatedResult{Articles: len(items)}
+	if s.cache == nil {
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result, nil
+	}
+
+	matches := related.Compute(items, s.relatedWeights(), s.relatedMaxPerArticle())
+	entries := make(map[string]interface{}, len(matches))
+	for id, list := range matches {
+		ids := make([]uint, 0, len(list))
+		for _, match := range list {
+			ids = append(ids, match.ID)
+		}
+		entries[cache.GetCacheKey(cache.CacheKeyRelatedArticles, id)] = ids
+	}
+	// 缓存有效期长于计算间隔，下次计算前一直可用；已删除文章的缓存到期后自然清理
+	if err := s.cache.SetMany(entries, s.relatedCacheTTL()); err != nil {
+		log.Printf("写入相关文章缓存失败: %v", err)
+	} else {
+		result.Cached = len(entries)
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// relatedItems 分批读取全部已发布文章，转换为相关度计算的输入
+func (s *ArticleService) relatedItems() ([]related.Item, error) {
+	var items []related.Item
+	var lastID uint
+	for {
+		articles, err := s.articleRepo.ListForRelated(lastID, relatedBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, article := range articles {
+			items = append(items, relatedItem(article))
+		}
+		if len(articles) < relatedBatchSize {
+			return items, nil
+		}
+		lastID = articles[len(articles)-1].ID
+	}
+}
+
+// relatedItem 将文章转换为相关度计算的输入，主分类和附加分类一并计入
+func relatedItem(article *model.Article) related.Item {
+	item := related.Item{
+		ID:      article.ID,
+		Title:   article.Title,
+		Summary: article.Summary,
+	}
+	if article.CategoryID != nil {
+		item.Categories = append(item.Categories, *article.CategoryID)
+	}
+	for _, category := range article.Categories {
+		item.Categories = append(item.Categories, category.ID)
+	}
+	for _, tag := range article.Tags {
+		item.Tags = append(item.Tags, tag.ID)
+	}
+	return item
+}
+
+// relatedWeights 相关度各部分的权重，未配置时使用默认值
+func (s *ArticleService) relatedWeights() related.Weights {
+	if s.config != nil {
+		cfg := s.config.Article.Related
+		if cfg.TagWeight > 0 || cfg.CategoryWeight > 0 || cfg.TextWeight > 0 {
+			return related.Weights{Tag: cfg.TagWeight, Category: cfg.CategoryWeight, Text: cfg.TextWeight}
+		}
+	}
+	return related.Weights{Tag: 0.5, Category: 0.2, Text: 0.3}
+}
+
+// relatedMaxPerArticle 每篇文章缓存的相关文章数
+func (s *ArticleService) relatedMaxPerArticle() int {
+	if s.config != nil && s.config.Article.Related.MaxPerArticle > 0 {
+		return s.config.Article.Related.MaxPerArticle
+	}
+	return 20
+}
+
+// relatedCacheTTL 相关文章缓存有效期，为计算间隔的 3 倍，计算偶尔失败时仍使用上次的结果
+func (s *ArticleService) relatedCacheTTL() time.Duration {
+	interval := 60
+	if s.config != nil && s.config.Article.Related.IntervalMinutes > 0 {
+		interval = s.config.Article.Related.IntervalMinutes
+	}
+	return 3 * time.Duration(interval) * time.Minute
+}
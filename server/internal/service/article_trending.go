@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// TrendingRequest 趋势文章请求
+type TrendingRequest struct {
+	Window     string `json:"window" binding:"omitempty,max=10"` // 统计窗口，如 24h、7d、30d，默认取配置
+	CategoryID *uint  `json:"categoryId"`                        // 只看该分类的文章
+	TagID      *uint  `json:"tagId"`                             // 只看带该标签的文章
+	Limit      int    `json:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// TrendingArticle 趋势文章及其热度
+type TrendingArticle struct {
+	*model.Article
+	TrendingScore float64 `json:"trendingScore"`
+}
+
+// TrendingResponse 趋势文章响应
+type TrendingResponse struct {
+	Window   string             `json:"window"`
+	Articles []*TrendingArticle `json:"articles"`
+}
+
+// 未配置时的默认统计窗口及半衰期（小时）
+var defaultTrendingWindows = map[string]float64{"24h": 6, "7d": 48, "30d": 240}
+
+// GetTrendingArticles 获取统计窗口内热度最高的文章
+// 热度由日统计中的浏览和点赞按时间衰减计算，越近的互动权重越高，不会像累计浏览量那样被老文章长期占据
+func (s *ArticleService) GetTrendingArticles(req *TrendingRequest, userID *uint) (*TrendingResponse, error) {
+	window := strings.ToLower(req.Window)
+	if window == "" {
+		window = s.trendingDefaultWindow()
+	}
+	halfLife, ok := s.trendingWindows()[window]
+	if !ok || halfLife <= 0 {
+		return nil, fmt.Errorf("%w: 不支持的统计窗口 %s", ErrInvalidListFilter, req.Window)
+	}
+	length, ok := parseTrendingWindow(window)
+	if !ok {
+		return nil, fmt.Errorf("%w: 统计窗口格式错误 %s", ErrInvalidListFilter, req.Window)
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	now := time.Now()
+	scores, err := s.statsRepo.Trending(&repository.TrendingParams{
+		Since:         now.Add(-length),
+		Now:           now,
+		HalfLifeHours: halfLife,
+		LikeWeight:    s.trendingLikeWeight(),
+		CategoryID:    req.CategoryID,
+		TagID:         req.TagID,
+		Limit:         req.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TrendingResponse{Window: window, Articles: []*TrendingArticle{}}
+	if len(scores) == 0 {
+		return result, nil
+	}
+	ids := make([]uint, 0, len(scores))
+	for _, score := range scores {
+		ids = append(ids, score.ArticleID)
+	}
+	articles, _, err := s.articleRepo.ListByIDs(ids, &repository.ArticleListParams{
+		Page:     1,
+		PageSize: len(ids),
+		Status:   model.ArticleStatusPublished,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.protectArticles(articles, userID)
+
+	// ListByIDs 按 ids 顺序返回，即热度降序
+	byID := make(map[uint]float64, len(scores))
+	for _, score := range scores {
+		byID[score.ArticleID] = score.Score
+	}
+	for _, article := range articles {
+		result.Articles = append(result.Articles, &TrendingArticle{Article: article, TrendingScore: byID[article.ID]})
+	}
+	return result, nil
+}
+
+// RollupEngagement 将今天和昨天的浏览和点赞汇总为日统计，并清理超过保留天数的统计
+// 同时汇总昨天，保证跨过零点前最后一段时间的互动也被计入
+func (s *ArticleService) RollupEngagement() (int64, error) {
+	now := time.Now()
+	var total int64
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		affected, err := s.statsRepo.RollupArticleDay(day)
+		if err != nil {
+			return total, err
+		}
+		total += affected
+	}
+
+	if s.config != nil && s.config.Article.Trending.StatsRetentionDays > 0 {
+		if _, err := s.statsRepo.DeleteBefore(now.AddDate(0, 0, -s.config.Article.Trending.StatsRetentionDays)); err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// parseTrendingWindow 解析统计窗口长度，支持 h（小时）和 d（天）
+func parseTrendingWindow(window string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(window, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// trendingWindows 可用的统计窗口及半衰期
+func (s *ArticleService) trendingWindows() map[string]float64 {
+	if s.config != nil && len(s.config.Article.Trending.Windows) > 0 {
+		return s.config.Article.Trending.Windows
+	}
+	return defaultTrendingWindows
+}
+
+// trendingDefaultWindow 未指定时使用的统计窗口
+func (s *ArticleService) trendingDefaultWindow() string {
+	if s.config != nil && s.config.Article.Trending.DefaultWindow != "" {
+		return strings.ToLower(s.config.Article.Trending.DefaultWindow)
+	}
+	return "7d"
+}
+
+// trendingLikeWeight 一次点赞相当于多少次浏览
+func (s *ArticleService) trendingLikeWeight() float64 {
+	if s.config != nil && s.config.Article.Trending.LikeWeight > 0 {
+		return s.config.Article.Trending.LikeWeight
+	}
+	return 5
+}
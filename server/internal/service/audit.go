@@ -0,0 +1,358 @@
+// Package service 审计日志服务
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/pkg/datetime"
+)
+
+// 审计日志相关常量
+const (
+	// 单个字段快照保留的最大字符数，避免文章正文等大字段撑爆日志
+	auditMaxValueLength = 500
+	// 单次导出的最大行数
+	auditMaxExportRows = 10000
+	// 导出时的分批查询大小
+	auditExportBatchSize = 500
+	// 敏感字段替换值
+	auditRedacted = "******"
+)
+
+// auditSensitiveKeys 快照中需要脱敏的字段关键字（小写匹配）
+var auditSensitiveKeys = []string{"password", "secret", "token"}
+
+// AuditEntry 一条待写入的审计记录
+type AuditEntry struct {
+	UserID       *uint
+	Action       string
+	ResourceType string
+	ResourceID   *uint
+	IPAddress    string
+	UserAgent    string
+	Before       interface{}            // 变更前快照，为空表示创建
+	After        interface{}            // 变更后快照，为空表示删除
+	Extra        map[string]interface{} // 附加信息（请求路径、请求ID等）
+}
+
+// AuditLogQueryRequest 审计日志查询请求
+type AuditLogQueryRequest struct {
+	UserID       uint   `json:"userId" binding:"omitempty,min=1"`
+	Action       string `json:"action" binding:"omitempty,max=100"`
+	ResourceType string `json:"resourceType" binding:"omitempty,max=50"`
+	ResourceID   uint   `json:"resourceId" binding:"omitempty,min=1"`
+	StartTime    string `json:"startTime"` // YYYY-MM-DD 或 YYYY-MM-DD HH:mm:ss
+	EndTime      string `json:"endTime"`   // YYYY-MM-DD（含当天）或 YYYY-MM-DD HH:mm:ss
+	Page         int    `json:"page" binding:"omitempty,min=1"`
+	PageSize     int    `json:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// AuditLogListResponse 审计日志列表响应
+type AuditLogListResponse struct {
+	Logs     []*model.OperationLog `json:"logs"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+	Pages    int64                 `json:"pages"`
+}
+
+// AuditService 审计日志服务接口
+type AuditService interface {
+	Record(entry *AuditEntry) error
+	ListLogs(req *AuditLogQueryRequest) (*AuditLogListResponse, error)
+	// ExportCSV 按查询条件导出CSV，返回导出行数
+	ExportCSV(req *AuditLogQueryRequest, w io.Writer) (int, error)
+}
+
+// auditService 审计日志服务实现
+type auditService struct {
+	logRepo repository.OperationLogRepository
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService(logRepo repository.OperationLogRepository) AuditService {
+	return &auditService{logRepo: logRepo}
+}
+
+// Record 写入一条审计记录
+func (s *auditService) Record(entry *AuditEntry) error {
+	if entry == nil || entry.Action == "" {
+		return fmt.Errorf("审计记录缺少操作类型")
+	}
+
+	details, err := json.Marshal(buildAuditDetails(entry))
+	if err != nil {
+		return fmt.Errorf("序列化审计详情失败: %w", err)
+	}
+
+	log := &model.OperationLog{
+		UserID:     entry.UserID,
+		Action:     entry.Action,
+		ResourceID: entry.ResourceID,
+		Details:    details,
+	}
+	if entry.ResourceType != "" {
+		log.ResourceType = &entry.ResourceType
+	}
+	if entry.IPAddress != "" {
+		log.IPAddress = &entry.IPAddress
+	}
+	if entry.UserAgent != "" {
+		log.UserAgent = &entry.UserAgent
+	}
+
+	return s.logRepo.Create(log)
+}
+
+// ListLogs 分页查询审计日志
+func (s *auditService) ListLogs(req *AuditLogQueryRequest) (*AuditLogListResponse, error) {
+	filter, err := req.toFilter()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	logs, total, err := s.logRepo.List(filter, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLogListResponse{
+		Logs:     logs,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Pages:    (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+	}, nil
+}
+
+// ExportCSV 按查询条件导出CSV
+func (s *auditService) ExportCSV(req *AuditLogQueryRequest, w io.Writer) (int, error) {
+	filter, err := req.toFilter()
+	if err != nil {
+		return 0, err
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"ID", "时间", "用户ID", "用户名", "操作", "资源类型", "资源ID", "IP地址", "用户代理", "详情"}
+	if err := writer.Write(header); err != nil {
+		return 0, fmt.Errorf("写入CSV失败: %w", err)
+	}
+
+	rows := 0
+	for rows < auditMaxExportRows {
+		logs, _, err := s.logRepo.List(filter, rows, auditExportBatchSize)
+		if err != nil {
+			return rows, err
+		}
+		for _, log := range logs {
+			if err := writer.Write(auditCSVRecord(log)); err != nil {
+				return rows, fmt.Errorf("写入CSV失败: %w", err)
+			}
+			rows++
+			if rows >= auditMaxExportRows {
+				break
+			}
+		}
+		if len(logs) < auditExportBatchSize {
+			break
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return rows, fmt.Errorf("写入CSV失败: %w", err)
+	}
+	return rows, nil
+}
+
+// toFilter 将查询请求转换为仓库层过滤条件
+func (req *AuditLogQueryRequest) toFilter() (*repository.OperationLogFilter, error) {
+	filter := &repository.OperationLogFilter{
+		UserID:       req.UserID,
+		Action:       req.Action,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+	}
+
+	if req.StartTime != "" {
+		t, _, err := parseQueryTime(req.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("开始时间格式错误: %w", err)
+		}
+		filter.StartTime = &t
+	}
+	if req.EndTime != "" {
+		t, dateOnly, err := parseQueryTime(req.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("结束时间格式错误: %w", err)
+		}
+		// 只给日期时包含当天全部记录
+		if dateOnly {
+			t = t.Add(24*time.Hour - time.Millisecond)
+		}
+		filter.EndTime = &t
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && filter.EndTime.Before(*filter.StartTime) {
+		return nil, fmt.Errorf("结束时间不能早于开始时间")
+	}
+
+	return filter, nil
+}
+
+// parseQueryTime 解析查询时间，返回是否只包含日期
+func parseQueryTime(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(datetime.JSONDateFormat, value, time.Local); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// auditCSVRecord 将日志转换为CSV行
+func auditCSVRecord(log *model.OperationLog) []string {
+	record := make([]string, 10)
+	record[0] = strconv.FormatUint(uint64(log.ID), 10)
+	record[1] = log.CreatedAt.Format(datetime.JSONDateFormat)
+	if log.UserID != nil {
+		record[2] = strconv.FormatUint(uint64(*log.UserID), 10)
+	}
+	if log.User != nil {
+		record[3] = csvSafe(log.User.Username)
+	}
+	record[4] = csvSafe(log.Action)
+	if log.ResourceType != nil {
+		record[5] = csvSafe(*log.ResourceType)
+	}
+	if log.ResourceID != nil {
+		record[6] = strconv.FormatUint(uint64(*log.ResourceID), 10)
+	}
+	if log.IPAddress != nil {
+		record[7] = csvSafe(*log.IPAddress)
+	}
+	if log.UserAgent != nil {
+		record[8] = csvSafe(*log.UserAgent)
+	}
+	record[9] = csvSafe(string(log.Details))
+	return record
+}
+
+// csvSafe 防止CSV公式注入：以 = + - @ 或制表符、回车开头的单元格在表格软件中会被当作公式执行，加单引号前缀按文本显示
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// buildAuditDetails 组装审计详情：有前后快照时记录字段级差异，否则记录单边快照
+func buildAuditDetails(entry *AuditEntry) map[string]interface{} {
+	details := make(map[string]interface{}, len(entry.Extra)+1)
+	for k, v := range entry.Extra {
+		details[k] = sanitizeAuditValue(k, v)
+	}
+
+	before := toAuditMap(entry.Before)
+	after := toAuditMap(entry.After)
+	switch {
+	case before != nil && after != nil:
+		details["changes"] = diffAuditMaps(before, after)
+	case after != nil:
+		details["after"] = after
+	case before != nil:
+		details["before"] = before
+	}
+	return details
+}
+
+// toAuditMap 将任意快照转换为脱敏后的字段映射
+func toAuditMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil || m == nil {
+		return nil
+	}
+	for k, val := range m {
+		m[k] = sanitizeAuditValue(k, val)
+	}
+	return m
+}
+
+// diffAuditMaps 比较前后快照，返回发生变化的字段
+// 以变更后快照的字段为准，响应中省略的字段（如 omitempty 关联数据）不视为被清空
+func diffAuditMaps(before, after map[string]interface{}) map[string]interface{} {
+	keys := make([]string, 0, len(after))
+	for k := range after {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	changes := make(map[string]interface{})
+	for _, k := range keys {
+		// 更新时间每次都会变化，没有审计价值
+		if k == "updatedAt" {
+			continue
+		}
+		oldVal, newVal := before[k], after[k]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		changes[k] = map[string]interface{}{"old": oldVal, "new": newVal}
+	}
+	return changes
+}
+
+// sanitizeAuditValue 对敏感字段脱敏并截断过长内容
+func sanitizeAuditValue(key string, v interface{}) interface{} {
+	lower := strings.ToLower(key)
+	for _, sensitive := range auditSensitiveKeys {
+		if strings.Contains(lower, sensitive) {
+			return auditRedacted
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		if len([]rune(val)) > auditMaxValueLength {
+			return truncate(val, auditMaxValueLength) + "..."
+		}
+		return val
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = sanitizeAuditValue(k, item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = sanitizeAuditValue("", item)
+		}
+		return val
+	default:
+		return v
+	}
+}
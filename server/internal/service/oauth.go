@@ -0,0 +1,686 @@
+// Package service 第三方登录（OAuth2 / OIDC）服务
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MyBlog/internal/config"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// 提供方类型
+const (
+	OAuthProviderOIDC   = "oidc"
+	OAuthProviderGitHub = "github"
+)
+
+// GitHub 默认端点
+const (
+	githubAuthURL     = "https://github.com/login/oauth/authorize"
+	githubTokenURL    = "https://github.com/login/oauth/access_token"
+	githubUserInfoURL = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
+// OAuthProviderInfo 对外展示的提供方信息
+type OAuthProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Type        string `json:"type"`
+}
+
+// OAuthAuthorizeResult 授权地址生成结果
+type OAuthAuthorizeResult struct {
+	AuthorizationURL string    `json:"authorizationUrl"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// OAuthCallbackResult 授权回调处理结果
+type OAuthCallbackResult struct {
+	*LoginResponse
+	Provider string `json:"provider"`
+	Linked   bool   `json:"linked"`  // 本次回调新建了身份绑定
+	Created  bool   `json:"created"` // 本次回调自动注册了新用户
+}
+
+// OAuthProfile 归一化后的提供方用户信息
+type OAuthProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Name          string
+	AvatarURL     string
+	Raw           string
+}
+
+// OAuthService 第三方登录服务接口
+type OAuthService interface {
+	ListProviders() []*OAuthProviderInfo
+	// BuildAuthorizationURL 生成授权地址，linkUserID 非0时表示为已登录用户绑定新身份
+	BuildAuthorizationURL(provider string, linkUserID uint) (*OAuthAuthorizeResult, error)
+	// HandleCallback 处理授权回调，browserState 为发起授权的浏览器 Cookie 中保存的 state，必须与回调的 state 一致
+	HandleCallback(provider, code, state, browserState string) (*OAuthCallbackResult, error)
+	ListIdentities(userID uint) ([]*model.UserIdentity, error)
+	UnlinkIdentity(userID uint, provider string) error
+}
+
+// oidcDiscovery OIDC 发现文档中用到的字段
+type oidcDiscovery struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// oauthService 第三方登录服务实现
+type oauthService struct {
+	config       *config.OAuthConfig
+	userRepo     repository.UserRepository
+	identityRepo repository.IdentityRepository
+	stateRepo    repository.OAuthStateRepository
+	jwtService   JWTService
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	endpoints map[string]*oidcDiscovery
+}
+
+// NewOAuthService 创建第三方登录服务实例
+func NewOAuthService(cfg *config.Config, userRepo repository.UserRepository,
+	identityRepo repository.IdentityRepository, stateRepo repository.OAuthStateRepository, jwtService JWTService) OAuthService {
+	return &oauthService{
+		config:       &cfg.OAuth,
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		jwtService:   jwtService,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+		endpoints:    make(map[string]*oidcDiscovery),
+	}
+}
+
+// ListProviders 获取已启用的提供方列表
+func (s *oauthService) ListProviders() []*OAuthProviderInfo {
+	providers := make([]*OAuthProviderInfo, 0, len(s.config.Providers))
+	for name, p := range s.config.Providers {
+		if !p.Enabled {
+			continue
+		}
+		displayName := p.DisplayName
+		if displayName == "" {
+			displayName = name
+		}
+		providers = append(providers, &OAuthProviderInfo{
+			Name:        name,
+			DisplayName: displayName,
+			Type:        providerType(p),
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Name < providers[j].Name })
+	return providers
+}
+
+// BuildAuthorizationURL 生成带 state 和 PKCE 参数的授权地址
+func (s *oauthService) BuildAuthorizationURL(provider string, linkUserID uint) (*OAuthAuthorizeResult, error) {
+	p, err := s.getProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := s.resolveEndpoints(provider, p)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("生成授权状态失败: %w", err)
+	}
+	verifier, err := randomString(48)
+	if err != nil {
+		return nil, fmt.Errorf("生成PKCE校验码失败: %w", err)
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	expiresAt := time.Now().Add(s.stateTTL())
+	if err := s.saveState(state, &model.OAuthState{
+		Provider:     provider,
+		CodeVerifier: verifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.ClientID)
+	query.Set("redirect_uri", p.RedirectURL)
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	if len(p.Scopes) > 0 {
+		query.Set("scope", strings.Join(p.Scopes, " "))
+	}
+
+	authURL := endpoints.AuthorizationEndpoint
+	if strings.Contains(authURL, "?") {
+		authURL += "&" + query.Encode()
+	} else {
+		authURL += "?" + query.Encode()
+	}
+
+	return &OAuthAuthorizeResult{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// HandleCallback 处理授权回调：换取令牌、获取用户信息并完成登录或绑定
+func (s *oauthService) HandleCallback(provider, code, state, browserState string) (*OAuthCallbackResult, error) {
+	p, err := s.getProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// state 必须来自当前浏览器发起的授权，防止把攻击者的授权结果注入到受害者的浏览器中
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, errors.New("授权状态与当前浏览器不匹配，请重新发起登录")
+	}
+	st := s.consumeState(state)
+	if st == nil || st.Provider != provider {
+		return nil, errors.New("授权状态无效或已过期，请重新发起登录")
+	}
+
+	endpoints, err := s.resolveEndpoints(provider, p)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.exchangeCode(p, endpoints, code, st.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.fetchProfile(p, endpoints, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OAuthCallbackResult{Provider: provider}
+	var user *repository.User
+
+	identity, _ := s.identityRepo.GetByProviderSubject(provider, profile.Subject)
+	switch {
+	case st.LinkUserID != 0:
+		// 已登录用户主动绑定
+		if identity != nil && identity.UserID != st.LinkUserID {
+			return nil, errors.New("该第三方账号已绑定其他用户")
+		}
+		if identity == nil {
+			linked, err := s.identityRepo.ListByUser(st.LinkUserID)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range linked {
+				if item.Provider == provider {
+					return nil, errors.New("当前用户已绑定该登录方式的其他账号，请先解除绑定")
+				}
+			}
+		}
+		user, err = s.userRepo.GetByID(st.LinkUserID)
+		if err != nil {
+			return nil, err
+		}
+	case identity != nil:
+		// 已绑定身份直接登录
+		user, err = s.userRepo.GetByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		user, result.Created, err = s.resolveUnlinkedUser(p, profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if user.Status != 1 {
+		return nil, errors.New("用户已被禁用")
+	}
+
+	if identity == nil {
+		identity = &model.UserIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  profile.Subject,
+		}
+		applyProfile(identity, profile)
+		now := time.Now()
+		identity.LastLoginAt = &now
+		if err := s.identityRepo.Create(identity); err != nil {
+			return nil, err
+		}
+		result.Linked = true
+	} else {
+		applyProfile(identity, profile)
+		if err := s.identityRepo.UpdateLoginInfo(identity); err != nil {
+			return nil, err
+		}
+	}
+
+	tokenPair, err := s.jwtService.GenerateTokenPair(user)
+	if err != nil {
+		return nil, fmt.Errorf("生成token失败: %w", err)
+	}
+
+	result.LoginResponse = &LoginResponse{
+		User:         user,
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}
+	return result, nil
+}
+
+// resolveUnlinkedUser 为尚未绑定的第三方身份查找或创建本地用户
+func (s *oauthService) resolveUnlinkedUser(p *config.OAuthProviderConfig, profile *OAuthProfile) (*repository.User, bool, error) {
+	// 只有第三方与本地两侧都验证过该邮箱时才自动关联，否则可以通过第三方账号接管本地账号
+	if profile.Email != "" {
+		if existing, _ := s.userRepo.GetByEmail(profile.Email); existing != nil {
+			if profile.EmailVerified && existing.EmailVerifiedAt != nil {
+				return existing, false, nil
+			}
+			return nil, false, errors.New("该邮箱已被本地账号使用，请先登录后在个人设置中绑定")
+		}
+	}
+
+	if !p.AutoRegister {
+		return nil, false, errors.New("该第三方账号未绑定本地用户")
+	}
+	if profile.Email == "" {
+		return nil, false, errors.New("第三方账号未提供邮箱，无法自动注册")
+	}
+
+	username, err := s.uniqueUsername(profile)
+	if err != nil {
+		return nil, false, err
+	}
+
+	nickname := profile.Name
+	if nickname == "" {
+		nickname = username
+	}
+	nickname = truncate(nickname, 50)
+
+	user := &repository.User{
+		Username: username,
+		Email:    profile.Email,
+		Password: "", // 仅通过第三方登录，未设置本地密码
+		Nickname: nickname,
+		Avatar:   truncate(profile.AvatarURL, 255),
+		Role:     string(RoleUser),
+		Status:   1,
+	}
+	if profile.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// usernameSanitizer 用户名中不允许的字符
+var usernameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9_\-.]+`)
+
+// uniqueUsername 根据提供方资料生成不重复的用户名
+func (s *oauthService) uniqueUsername(profile *OAuthProfile) (string, error) {
+	base := profile.Username
+	if base == "" && profile.Email != "" {
+		base = strings.SplitN(profile.Email, "@", 2)[0]
+	}
+	base = usernameSanitizer.ReplaceAllString(base, "")
+	if base == "" {
+		base = "user"
+	}
+	base = truncate(base, 40)
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if existing, _ := s.userRepo.GetByUsername(candidate); existing == nil {
+			return candidate, nil
+		}
+		suffix, err := randomString(3)
+		if err != nil {
+			return "", fmt.Errorf("生成用户名失败: %w", err)
+		}
+		candidate = base + "_" + suffix
+	}
+	return "", errors.New("生成用户名失败，请稍后重试")
+}
+
+// ListIdentities 获取用户已绑定的第三方身份
+func (s *oauthService) ListIdentities(userID uint) ([]*model.UserIdentity, error) {
+	return s.identityRepo.ListByUser(userID)
+}
+
+// UnlinkIdentity 解除第三方身份绑定
+func (s *oauthService) UnlinkIdentity(userID uint, provider string) error {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// 未设置本地密码的用户至少保留一个第三方身份，避免无法登录
+	if user.Password == "" {
+		count, err := s.identityRepo.CountByUser(userID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return errors.New("当前账号未设置密码，不能解除唯一的第三方登录方式")
+		}
+	}
+
+	return s.identityRepo.Delete(userID, provider)
+}
+
+// getProvider 获取已启用的提供方配置
+func (s *oauthService) getProvider(name string) (*config.OAuthProviderConfig, error) {
+	p, ok := s.config.Providers[name]
+	if !ok || !p.Enabled {
+		return nil, fmt.Errorf("不支持的登录方式: %s", name)
+	}
+	return &p, nil
+}
+
+// resolveEndpoints 解析提供方端点，OIDC 提供方优先使用发现文档
+func (s *oauthService) resolveEndpoints(name string, p *config.OAuthProviderConfig) (*oidcDiscovery, error) {
+	endpoints := &oidcDiscovery{
+		AuthorizationEndpoint: p.AuthURL,
+		TokenEndpoint:         p.TokenURL,
+		UserinfoEndpoint:      p.UserInfoURL,
+	}
+
+	if providerType(*p) == OAuthProviderGitHub {
+		if endpoints.AuthorizationEndpoint == "" {
+			endpoints.AuthorizationEndpoint = githubAuthURL
+		}
+		if endpoints.TokenEndpoint == "" {
+			endpoints.TokenEndpoint = githubTokenURL
+		}
+		if endpoints.UserinfoEndpoint == "" {
+			endpoints.UserinfoEndpoint = githubUserInfoURL
+		}
+		return endpoints, nil
+	}
+
+	if endpoints.AuthorizationEndpoint != "" && endpoints.TokenEndpoint != "" && endpoints.UserinfoEndpoint != "" {
+		return endpoints, nil
+	}
+
+	s.mu.Lock()
+	cached, ok := s.endpoints[name]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	discoveryURL := strings.TrimRight(p.Issuer, "/") + "/.well-known/openid-configuration"
+	var discovered oidcDiscovery
+	if err := s.getJSON(discoveryURL, "", &discovered); err != nil {
+		return nil, fmt.Errorf("获取OIDC发现文档失败: %w", err)
+	}
+
+	// 显式配置的端点优先于发现文档
+	if endpoints.AuthorizationEndpoint == "" {
+		endpoints.AuthorizationEndpoint = discovered.AuthorizationEndpoint
+	}
+	if endpoints.TokenEndpoint == "" {
+		endpoints.TokenEndpoint = discovered.TokenEndpoint
+	}
+	if endpoints.UserinfoEndpoint == "" {
+		endpoints.UserinfoEndpoint = discovered.UserinfoEndpoint
+	}
+	if endpoints.AuthorizationEndpoint == "" || endpoints.TokenEndpoint == "" || endpoints.UserinfoEndpoint == "" {
+		return nil, errors.New("OIDC发现文档缺少必要的端点")
+	}
+
+	s.mu.Lock()
+	s.endpoints[name] = endpoints
+	s.mu.Unlock()
+	return endpoints, nil
+}
+
+// exchangeCode 使用授权码和 PKCE 校验码换取访问令牌
+func (s *oauthService) exchangeCode(p *config.OAuthProviderConfig, endpoints *oidcDiscovery, code, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", verifier)
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoints.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("构建令牌请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求令牌失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("解析令牌响应失败: %w", err)
+	}
+	if token.Error != "" {
+		return "", fmt.Errorf("授权码换取令牌失败: %s %s", token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		return "", fmt.Errorf("授权码换取令牌失败: HTTP %d", resp.StatusCode)
+	}
+	return token.AccessToken, nil
+}
+
+// fetchProfile 获取并归一化提供方用户信息
+func (s *oauthService) fetchProfile(p *config.OAuthProviderConfig, endpoints *oidcDiscovery, accessToken string) (*OAuthProfile, error) {
+	var raw map[string]interface{}
+	if err := s.getJSON(endpoints.UserinfoEndpoint, accessToken, &raw); err != nil {
+		return nil, fmt.Errorf("获取第三方用户信息失败: %w", err)
+	}
+	rawBytes, _ := json.Marshal(raw)
+
+	profile := &OAuthProfile{Raw: string(rawBytes)}
+	if providerType(*p) == OAuthProviderGitHub {
+		profile.Subject = claimString(raw, "id")
+		profile.Username = claimString(raw, "login")
+		profile.Name = claimString(raw, "name")
+		profile.AvatarURL = claimString(raw, "avatar_url")
+
+		// GitHub 用户接口不返回邮箱验证状态，需要单独查询邮箱列表
+		emailsURL := p.EmailsURL
+		if emailsURL == "" {
+			emailsURL = githubEmailsURL
+		}
+		var emails []struct {
+			Email    string `json:"email"`
+			Primary  bool   `json:"primary"`
+			Verified bool   `json:"verified"`
+		}
+		if err := s.getJSON(emailsURL, accessToken, &emails); err == nil {
+			for _, e := range emails {
+				if e.Primary {
+					profile.Email = e.Email
+					profile.EmailVerified = e.Verified
+					break
+				}
+			}
+		}
+		if profile.Email == "" {
+			profile.Email = claimString(raw, "email")
+		}
+	} else {
+		profile.Subject = claimString(raw, "sub")
+		profile.Email = claimString(raw, "email")
+		profile.EmailVerified = claimBool(raw, "email_verified")
+		profile.Username = claimString(raw, "preferred_username")
+		profile.Name = claimString(raw, "name")
+		profile.AvatarURL = claimString(raw, "picture")
+	}
+
+	if profile.Subject == "" {
+		return nil, errors.New("第三方用户信息缺少唯一标识")
+	}
+	profile.Email = strings.ToLower(strings.TrimSpace(profile.Email))
+	return profile, nil
+}
+
+// getJSON 发起 GET 请求并解析 JSON 响应
+func (s *oauthService) getJSON(target, accessToken string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// saveState 保存授权状态（只保存 state 的哈希），同时清理过期记录
+// 授权状态保存在数据库中，服务重启或多实例部署时回调仍能找到发起时的状态
+func (s *oauthService) saveState(state string, st *model.OAuthState) error {
+	if _, err := s.stateRepo.DeleteExpired(time.Now()); err != nil {
+		log.Printf("清理过期授权状态失败: %v", err)
+	}
+	st.StateHash = hashState(state)
+	return s.stateRepo.Create(st)
+}
+
+// consumeState 取出并删除授权状态，每个 state 只能使用一次
+func (s *oauthService) consumeState(state string) *model.OAuthState {
+	st, err := s.stateRepo.Consume(hashState(state))
+	if err != nil || time.Now().After(st.ExpiresAt) {
+		return nil
+	}
+	return st
+}
+
+// stateTTL 授权状态有效期
+func (s *oauthService) stateTTL() time.Duration {
+	if s.config.StateExpire > 0 {
+		return time.Duration(s.config.StateExpire) * time.Minute
+	}
+	return 10 * time.Minute
+}
+
+// hashState 计算 state 的 SHA-256 哈希值
+func hashState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// providerType 获取提供方类型，未配置时默认为 OIDC
+func providerType(p config.OAuthProviderConfig) string {
+	if p.Type == "" {
+		return OAuthProviderOIDC
+	}
+	return p.Type
+}
+
+// applyProfile 将提供方资料写入身份记录
+func applyProfile(identity *model.UserIdentity, profile *OAuthProfile) {
+	identity.Email = profile.Email
+	identity.EmailVerified = profile.EmailVerified
+	identity.Username = truncate(profile.Username, 100)
+	identity.AvatarURL = truncate(profile.AvatarURL, 500)
+	identity.RawProfile = profile.Raw
+}
+
+// claimString 读取字符串声明，数字类型的 ID 会转换为字符串
+func claimString(raw map[string]interface{}, key string) string {
+	switch v := raw[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// claimBool 读取布尔声明，兼容部分提供方返回字符串 "true"
+func claimBool(raw map[string]interface{}, key string) bool {
+	switch v := raw[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// randomString 生成指定字节数的随机十六进制字符串
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"MyBlog/internal/config"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// mockIdP 模拟的身份提供方，授权码与 PKCE challenge 及用户资料一一对应
+type mockIdP struct {
+	server *httptest.Server
+
+	mu    sync.Mutex
+	codes map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	profile   map[string]interface{}
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	idp := &mockIdP{codes: make(map[string]mockGrant)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		idp.mu.Lock()
+		grant, ok := idp.codes[r.PostForm.Get("code")]
+		delete(idp.codes, r.PostForm.Get("code"))
+		idp.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token, _ := json.Marshal(grant.profile)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": base64.RawURLEncoding.EncodeToString(token),
+			"token_type":   "Bearer",
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		token, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if err != nil {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(token)
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize 模拟用户在提供方完成授权，返回回调收到的 code 和 state
+func (idp *mockIdP) authorize(t *testing.T, authURL string, profile map[string]interface{}) (string, string) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("解析授权地址失败: %v", err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("授权地址缺少 PKCE 参数: %s", authURL)
+	}
+	code := "code-" + query.Get("state")[:8]
+	idp.mu.Lock()
+	idp.codes[code] = mockGrant{challenge: query.Get("code_challenge"), profile: profile}
+	idp.mu.Unlock()
+	return code, query.Get("state")
+}
+
+type fakeUserRepo struct {
+	users []*repository.User
+}
+
+func (r *fakeUserRepo) Create(user *repository.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *fakeUserRepo) GetByID(id uint) (*repository.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, errors.New("用户不存在")
+}
+
+func (r *fakeUserRepo) GetByUsername(username string) (*repository.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, errors.New("用户不存在")
+}
+
+func (r *fakeUserRepo) GetByEmail(email string) (*repository.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, errors.New("用户不存在")
+}
+
+func (r *fakeUserRepo) Update(user *repository.User) error { return nil }
+func (r *fakeUserRepo) Delete(id uint) error               { return nil }
+func (r *fakeUserRepo) List(offset, limit int) ([]*repository.User, int64, error) {
+	return r.users, int64(len(r.users)), nil
+}
+
+type fakeIdentityRepo struct {
+	identities []*model.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(identity *model.UserIdentity) error {
+	identity.ID = uint(len(r.identities) + 1)
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) GetByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, errors.New("身份绑定不存在")
+}
+
+func (r *fakeIdentityRepo) ListByUser(userID uint) ([]*model.UserIdentity, error) {
+	var list []*model.UserIdentity
+	for _, i := range r.identities {
+		if i.UserID == userID {
+			list = append(list, i)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeIdentityRepo) CountByUser(userID uint) (int64, error) {
+	list, _ := r.ListByUser(userID)
+	return int64(len(list)), nil
+}
+
+func (r *fakeIdentityRepo) Delete(userID uint, provider string) error          { return nil }
+func (r *fakeIdentityRepo) UpdateLoginInfo(identity *model.UserIdentity) error { return nil }
+
+type fakeStateRepo struct {
+	states map[string]*model.OAuthState
+}
+
+func (r *fakeStateRepo) Create(state *model.OAuthState) error {
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *fakeStateRepo) Consume(stateHash string) (*model.OAuthState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, errors.New("授权状态不存在")
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+func (r *fakeStateRepo) DeleteExpired(before time.Time) (int64, error) { return 0, nil }
+
+type fakeJWTService struct{}
+
+func (fakeJWTService) GenerateTokenPair(user *repository.User) (*TokenPair, error) {
+	return &TokenPair{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 3600}, nil
+}
+func (fakeJWTService) ValidateAccessToken(string) (*JWTClaims, error)  { return nil, nil }
+func (fakeJWTService) ValidateRefreshToken(string) (*JWTClaims, error) { return nil, nil }
+func (fakeJWTService) RefreshAccessToken(string) (*TokenPair, error)   { return nil, nil }
+func (fakeJWTService) RevokeToken(string) error                        { return nil }
+func (fakeJWTService) IsTokenRevoked(string) bool                      { return false }
+func (fakeJWTService) ReconstructFullToken(string, TokenType) (string, error) {
+	return "", nil
+}
+
+type oauthFixture struct {
+	idp        *mockIdP
+	service    OAuthService
+	users      *fakeUserRepo
+	identities *fakeIdentityRepo
+}
+
+func newOAuthFixture(t *testing.T) *oauthFixture {
+	idp := newMockIdP(t)
+	cfg := &config.Config{OAuth: config.OAuthConfig{
+		StateExpire: 10,
+		Providers: map[string]config.OAuthProviderConfig{
+			"mock": {
+				Enabled:      true,
+				Type:         OAuthProviderOIDC,
+				ClientID:     "myblog",
+				AuthURL:      idp.server.URL + "/authorize",
+				TokenURL:     idp.server.URL + "/token",
+				UserInfoURL:  idp.server.URL + "/userinfo",
+				RedirectURL:  "http://localhost:5173/oauth/callback/mock",
+				AutoRegister: true,
+			},
+		},
+	}}
+	f := &oauthFixture{
+		idp: idp,
+		users: &fakeUserRepo{users: []*repository.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com", Password: "hashed", Role: string(RoleUser), Status: 1},
+		}},
+		identities: &fakeIdentityRepo{},
+	}
+	f.service = NewOAuthService(cfg, f.users, f.identities,
+		&fakeStateRepo{states: make(map[string]*model.OAuthState)}, fakeJWTService{})
+	return f
+}
+
+// start 发起授权并在模拟提供方完成授权
+func (f *oauthFixture) start(t *testing.T, linkUserID uint, profile map[string]interface{}) (string, string) {
+	result, err := f.service.BuildAuthorizationURL("mock", linkUserID)
+	if err != nil {
+		t.Fatalf("生成授权地址失败: %v", err)
+	}
+	code, state := f.idp.authorize(t, result.AuthorizationURL, profile)
+	if state != result.State {
+		t.Fatalf("授权地址中的 state 与返回值不一致")
+	}
+	return code, state
+}
+
+func TestOAuthLoginAutoRegistersAndReusesIdentity(t *testing.T) {
+	f := newOAuthFixture(t)
+	profile := map[string]interface{}{
+		"sub": "idp-100", "email": "Bob@Example.com", "email_verified": true, "preferred_username": "bob",
+	}
+
+	code, state := f.start(t, 0, profile)
+	result, err := f.service.HandleCallback("mock", code, state, state)
+	if err != nil {
+		t.Fatalf("首次登录失败: %v", err)
+	}
+	if !result.Created || !result.Linked {
+		t.Fatalf("首次登录应自动注册并绑定, got created=%v linked=%v", result.Created, result.Linked)
+	}
+	if result.User.Username != "bob" || result.User.Email != "bob@example.com" || result.User.EmailVerifiedAt == nil {
+		t.Fatalf("自动注册的用户信息不正确: %+v", result.User)
+	}
+
+	code, state = f.start(t, 0, profile)
+	again, err := f.service.HandleCallback("mock", code, state, state)
+	if err != nil {
+		t.Fatalf("再次登录失败: %v", err)
+	}
+	if again.Created || again.Linked || again.User.ID != result.User.ID {
+		t.Fatalf("再次登录应复用已绑定的用户, got %+v", again)
+	}
+}
+
+func TestOAuthLinkIdentityToCurrentUser(t *testing.T) {
+	f := newOAuthFixture(t)
+	// 提供方邮箱与本地用户相同，但本地邮箱未验证，只有显式绑定流程才能关联
+	profile := map[string]interface{}{"sub": "idp-200", "email": "alice@example.com", "email_verified": true}
+
+	code, state := f.start(t, 1, profile)
+	result, err := f.service.HandleCallback("mock", code, state, state)
+	if err != nil {
+		t.Fatalf("绑定失败: %v", err)
+	}
+	if result.User.ID != 1 || !result.Linked || result.Created {
+		t.Fatalf("应绑定到当前用户, got user=%d linked=%v created=%v", result.User.ID, result.Linked, result.Created)
+	}
+	if identity, _ := f.identities.GetByProviderSubject("mock", "idp-200"); identity == nil || identity.UserID != 1 {
+		t.Fatalf("身份绑定未保存到当前用户: %+v", identity)
+	}
+}
+
+func TestOAuthLoginLinksEmailOnlyWhenBothVerified(t *testing.T) {
+	verifiedAt := time.Now()
+	cases := []struct {
+		name          string
+		localVerified bool
+		idpVerified   bool
+		wantLinked    bool
+	}{
+		{name: "两侧均已验证", localVerified: true, idpVerified: true, wantLinked: true},
+		{name: "本地邮箱未验证", localVerified: false, idpVerified: true},
+		{name: "提供方邮箱未验证", localVerified: true, idpVerified: false},
+		{name: "两侧均未验证", localVerified: false, idpVerified: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			if tc.localVerified {
+				f.users.users[0].EmailVerifiedAt = &verifiedAt
+			}
+			profile := map[string]interface{}{"sub": "idp-300", "email": "alice@example.com", "email_verified": tc.idpVerified}
+
+			code, state := f.start(t, 0, profile)
+			result, err := f.service.HandleCallback("mock", code, state, state)
+			if !tc.wantLinked {
+				if err == nil {
+					t.Fatal("邮箱未在两侧验证时不应自动关联")
+				}
+				if len(f.identities.identities) != 0 {
+					t.Fatalf("不应创建身份绑定: %+v", f.identities.identities)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("两侧邮箱均已验证时应自动关联: %v", err)
+			}
+			if result.User.ID != 1 || !result.Linked || result.Created {
+				t.Fatalf("应关联到本地用户, got user=%d linked=%v created=%v", result.User.ID, result.Linked, result.Created)
+			}
+		})
+	}
+}
+
+func TestOAuthCallbackRejectsStateMismatch(t *testing.T) {
+	f := newOAuthFixture(t)
+	profile := map[string]interface{}{"sub": "idp-400", "email": "eve@example.com", "email_verified": true}
+
+	// 攻击者发起授权得到的 code 和 state，被注入到没有对应 Cookie 的受害者浏览器
+	code, state := f.start(t, 0, profile)
+	_, victimState := f.start(t, 0, profile)
+	for _, browserState := range []string{"", victimState} {
+		if _, err := f.service.HandleCallback("mock", code, state, browserState); err == nil {
+			t.Fatalf("浏览器 state %q 与回调 state 不一致时应拒绝", browserState)
+		}
+	}
+
+	// 不匹配的请求不会消费 state，发起授权的浏览器仍可完成登录，但只能使用一次
+	if _, err := f.service.HandleCallback("mock", code, state, state); err != nil {
+		t.Fatalf("发起授权的浏览器应能完成登录: %v", err)
+	}
+	if _, err := f.service.HandleCallback("mock", code, state, state); err == nil {
+		t.Fatal("state 重复使用时应拒绝")
+	}
+}
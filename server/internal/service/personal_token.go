@@ -0,0 +1,216 @@
+// Package service 个人访问令牌服务
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// 个人访问令牌相关常量
+const (
+	// PersonalTokenPrefix 个人访问令牌前缀，用于和JWT区分
+	PersonalTokenPrefix = "mbp_"
+	// 默认有效期（天）
+	defaultPersonalTokenExpireDays = 90
+	// 最后使用时间的写入间隔，避免每次请求都更新数据库
+	personalTokenTouchInterval = time.Minute
+)
+
+// CreatePersonalTokenRequest 创建个人访问令牌请求
+type CreatePersonalTokenRequest struct {
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expiresInDays" binding:"omitempty,min=1,max=365"` // 留空默认90天
+}
+
+// PersonalTokenResponse 个人访问令牌响应
+type PersonalTokenResponse struct {
+	ID          uint         `json:"id"`
+	Name        string       `json:"name"`
+	TokenPrefix string       `json:"tokenPrefix"`
+	Scopes      []Permission `json:"scopes"`
+	ExpiresAt   *time.Time   `json:"expiresAt"`
+	LastUsedAt  *time.Time   `json:"lastUsedAt"`
+	LastUsedIP  string       `json:"lastUsedIp"`
+	RevokedAt   *time.Time   `json:"revokedAt"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	Token       string       `json:"token,omitempty"` // 明文令牌，仅在创建时返回一次
+}
+
+// PersonalTokenService 个人访问令牌服务接口
+type PersonalTokenService interface {
+	CreateToken(userID uint, req *CreatePersonalTokenRequest) (*PersonalTokenResponse, error)
+	ListTokens(userID uint) ([]*PersonalTokenResponse, error)
+	RevokeToken(userID, id uint) error
+	// Authenticate 校验令牌并返回令牌所属用户和授权范围
+	Authenticate(rawToken, clientIP string) (*repository.User, []Permission, error)
+}
+
+// personalTokenService 个人访问令牌服务实现
+type personalTokenService struct {
+	tokenRepo   repository.TokenRepository
+	userRepo    repository.UserRepository
+	rbacService RBACService
+}
+
+// NewPersonalTokenService 创建个人访问令牌服务实例
+func NewPersonalTokenService(tokenRepo repository.TokenRepository, userRepo repository.UserRepository,
+	rbacService RBACService) PersonalTokenService {
+	return &personalTokenService{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		rbacService: rbacService,
+	}
+}
+
+// IsPersonalToken 判断令牌是否为个人访问令牌
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
+
+// CreateToken 创建个人访问令牌
+func (s *personalTokenService) CreateToken(userID uint, req *CreatePersonalTokenRequest) (*PersonalTokenResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 授权范围必须是已定义的权限，且不能超出用户当前角色的权限
+	scopes := make([]Permission, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		if !IsValidPermission(scope) {
+			return nil, fmt.Errorf("无效的授权范围: %s", scope)
+		}
+		if !s.rbacService.HasPermission(user.Role, Permission(scope)) {
+			return nil, fmt.Errorf("当前角色没有该权限，不能授予令牌: %s", scope)
+		}
+		scopes = append(scopes, Permission(scope))
+	}
+
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, fmt.Errorf("序列化授权范围失败: %w", err)
+	}
+
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("生成访问令牌失败: %w", err)
+	}
+	rawToken := PersonalTokenPrefix + secret
+
+	expireDays := req.ExpiresInDays
+	if expireDays == 0 {
+		expireDays = defaultPersonalTokenExpireDays
+	}
+	expiresAt := time.Now().AddDate(0, 0, expireDays)
+
+	token := &model.PersonalAccessToken{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenHash:   hashPersonalToken(rawToken),
+		TokenPrefix: rawToken[:len(PersonalTokenPrefix)+8],
+		Scopes:      string(scopesJSON),
+		ExpiresAt:   &expiresAt,
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	resp := toPersonalTokenResponse(token)
+	resp.Token = rawToken
+	return resp, nil
+}
+
+// ListTokens 获取用户的令牌列表
+func (s *personalTokenService) ListTokens(userID uint) ([]*PersonalTokenResponse, error) {
+	tokens, err := s.tokenRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*PersonalTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, toPersonalTokenResponse(token))
+	}
+	return result, nil
+}
+
+// RevokeToken 撤销令牌
+func (s *personalTokenService) RevokeToken(userID, id uint) error {
+	return s.tokenRepo.Revoke(userID, id)
+}
+
+// Authenticate 校验个人访问令牌
+func (s *personalTokenService) Authenticate(rawToken, clientIP string) (*repository.User, []Permission, error) {
+	token, err := s.tokenRepo.GetByHash(hashPersonalToken(rawToken))
+	if err != nil {
+		return nil, nil, errors.New("无效的访问令牌")
+	}
+
+	now := time.Now()
+	if token.RevokedAt != nil {
+		return nil, nil, errors.New("访问令牌已撤销")
+	}
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, nil, errors.New("访问令牌已过期")
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Status != 1 {
+		return nil, nil, errors.New("用户已被禁用")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalTokenTouchInterval || token.LastUsedIP != clientIP {
+		// 使用记录仅用于展示，写入失败不影响本次认证
+		_ = s.tokenRepo.UpdateLastUsed(token.ID, clientIP, now)
+	}
+
+	return user, parsePersonalTokenScopes(token.Scopes), nil
+}
+
+// hashPersonalToken 计算令牌的SHA-256哈希，数据库中只保存哈希值
+func hashPersonalToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// parsePersonalTokenScopes 解析令牌授权范围
+func parsePersonalTokenScopes(scopesJSON string) []Permission {
+	var scopes []Permission
+	if scopesJSON == "" {
+		return scopes
+	}
+	_ = json.Unmarshal([]byte(scopesJSON), &scopes)
+	return scopes
+}
+
+// toPersonalTokenResponse 转换为令牌响应
+func toPersonalTokenResponse(token *model.PersonalAccessToken) *PersonalTokenResponse {
+	return &PersonalTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      parsePersonalTokenScopes(token.Scopes),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		LastUsedIP:  token.LastUsedIP,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -0,0 +1,287 @@
+// Package service 文章预览链接服务
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"MyBlog/internal/config"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// 预览链接相关常量
+const (
+	// PreviewTokenPrefix 预览令牌前缀
+	PreviewTokenPrefix = "mbv_"
+	// 默认有效期（小时）
+	defaultPreviewHours = 72
+	// 最长有效期（小时）
+	defaultPreviewMaxHours = 720
+	// 每篇文章最多同时有效的预览链接数
+	defaultPreviewMaxPerArticle = 20
+)
+
+// errInvalidPreviewToken 预览令牌无效（格式错误、签名不符、已过期或已撤销统一返回，避免泄露细节）
+var errInvalidPreviewToken = errors.New("预览链接无效或已过期")
+
+// CreatePreviewRequest 创建预览链接请求
+type CreatePreviewRequest struct {
+	ArticleID    uint   `json:"articleId" binding:"required,min=1"`
+	ExpiresHours int    `json:"expiresHours" binding:"omitempty,min=1"` // 有效期（小时），留空使用默认值
+	Note         string `json:"note" binding:"max=100"`                 // 备注（如分享对象）
+}
+
+// PreviewTokenResponse 预览链接响应
+type PreviewTokenResponse struct {
+	*model.ArticlePreviewToken
+	Active bool   `json:"active"`          // 是否仍然有效
+	Token  string `json:"token,omitempty"` // 预览令牌，仅在创建时返回
+}
+
+// PreviewAccess 通过预览链接访问时的请求信息
+type PreviewAccess struct {
+	UserID    *uint
+	IPAddress string
+	UserAgent string
+	Path      string
+}
+
+// PreviewService 文章预览链接服务接口
+type PreviewService interface {
+	CreatePreview(req *CreatePreviewRequest, userID uint) (*PreviewTokenResponse, error)
+	ListPreviews(articleID uint, userID uint) ([]*PreviewTokenResponse, error)
+	RevokePreview(articleID, id uint, userID uint) error
+	// GetArticleByPreview 校验预览令牌并返回对应文章（不做登录和状态检查），每次访问都会记录审计日志
+	GetArticleByPreview(rawToken string, access *PreviewAccess) (*model.Article, error)
+	// WithTokenScopes 返回按个人访问令牌授权范围校验文章权限的服务副本
+	WithTokenScopes(scopes []Permission) PreviewService
+}
+
+// previewService 文章预览链接服务实现
+type previewService struct {
+	previewRepo    repository.PreviewTokenRepository
+	articleRepo    repository.ArticleRepositoryInterface
+	articleService ArticleServiceInterface
+	auditService   AuditService
+	config         *config.Config
+}
+
+// NewPreviewService 创建文章预览链接服务实例
+func NewPreviewService(
+	previewRepo repository.PreviewTokenRepository,
+	articleRepo repository.ArticleRepositoryInterface,
+	articleService ArticleServiceInterface,
+	auditService AuditService,
+	cfg *config.Config,
+) PreviewService {
+	return &previewService{
+		previewRepo:    previewRepo,
+		articleRepo:    articleRepo,
+		articleService: articleService,
+		auditService:   auditService,
+		config:         cfg,
+	}
+}
+
+// WithTokenScopes 返回按个人访问令牌授权范围校验文章权限的服务副本
+func (s *previewService) WithTokenScopes(scopes []Permission) PreviewService {
+	scoped := *s
+	scoped.articleService = s.articleService.WithTokenScopes(scopes)
+	return &scoped
+}
+
+// IsPreviewToken 判断令牌是否为文章预览令牌
+func IsPreviewToken(token string) bool {
+	return strings.HasPrefix(token, PreviewTokenPrefix)
+}
+
+// CreatePreview 为文章创建预览链接（作者和管理员）
+func (s *previewService) CreatePreview(req *CreatePreviewRequest, userID uint) (*PreviewTokenResponse, error) {
+	article, err := s.articleRepo.GetByID(req.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.articleService.CanManageCollaborators(article, userID) {
+		return nil, errors.New("没有分享此文章的权限")
+	}
+
+	cfg := s.previewConfig()
+	hours := req.ExpiresHours
+	if hours == 0 {
+		hours = cfg.DefaultHours
+	}
+	if hours > cfg.MaxHours {
+		return nil, fmt.Errorf("预览链接有效期不能超过 %d 小时", cfg.MaxHours)
+	}
+
+	now := time.Now()
+	active, err := s.previewRepo.CountActive(article.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= int64(cfg.MaxPerArticle) {
+		return nil, fmt.Errorf("每篇文章最多同时有 %d 个有效的预览链接，请先撤销不用的链接", cfg.MaxPerArticle)
+	}
+
+	token := &model.ArticlePreviewToken{
+		ArticleID: article.ID,
+		CreatedBy: userID,
+		Note:      req.Note,
+		// 签名中的过期时间精确到秒，数据库记录保持一致
+		ExpiresAt: now.Add(time.Duration(hours) * time.Hour).Truncate(time.Second),
+	}
+	if err := s.previewRepo.Create(token); err != nil {
+		return nil, err
+	}
+
+	return &PreviewTokenResponse{
+		ArticlePreviewToken: token,
+		Active:              true,
+		Token:               s.sign(token),
+	}, nil
+}
+
+// ListPreviews 获取文章的预览链接列表（不含令牌）
+func (s *previewService) ListPreviews(articleID uint, userID uint) ([]*PreviewTokenResponse, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.articleService.CanManageCollaborators(article, userID) {
+		return nil, errors.New("没有管理此文章预览链接的权限")
+	}
+
+	tokens, err := s.previewRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]*PreviewTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, &PreviewTokenResponse{ArticlePreviewToken: token, Active: token.IsActive(now)})
+	}
+	return result, nil
+}
+
+// RevokePreview 撤销预览链接
+func (s *previewService) RevokePreview(articleID, id uint, userID uint) error {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return err
+	}
+	if !s.articleService.CanManageCollaborators(article, userID) {
+		return errors.New("没有管理此文章预览链接的权限")
+	}
+	return s.previewRepo.Revoke(articleID, id)
+}
+
+// GetArticleByPreview 校验预览令牌并返回文章
+func (s *previewService) GetArticleByPreview(rawToken string, access *PreviewAccess) (*model.Article, error) {
+	articleID, tokenID, expiresAt, ok := s.verify(rawToken)
+	if !ok {
+		return nil, errInvalidPreviewToken
+	}
+
+	// 签名有效后再查库确认未被撤销
+	now := time.Now()
+	if !now.Before(expiresAt) {
+		return nil, errInvalidPreviewToken
+	}
+	token, err := s.previewRepo.GetByID(tokenID)
+	if err != nil || token.ArticleID != articleID || !token.IsActive(now) {
+		return nil, errInvalidPreviewToken
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次访问都记录，审计失败不影响预览
+	_ = s.previewRepo.RecordAccess(token.ID, now)
+	if access == nil {
+		access = &PreviewAccess{}
+	}
+	_ = s.auditService.Record(&AuditEntry{
+		UserID:       access.UserID,
+		Action:       model.ActionPreviewArticle,
+		ResourceType: model.ResourceArticle,
+		ResourceID:   &article.ID,
+		IPAddress:    access.IPAddress,
+		UserAgent:    access.UserAgent,
+		Extra: map[string]interface{}{
+			"previewId": token.ID,
+			"note":      token.Note,
+			"status":    article.Status,
+			"path":      access.Path,
+		},
+	})
+
+	return article, nil
+}
+
+// sign 生成预览令牌：前缀 + 文章ID.令牌ID.过期时间戳.签名
+func (s *previewService) sign(token *model.ArticlePreviewToken) string {
+	payload := fmt.Sprintf("%d.%d.%d", token.ArticleID, token.ID, token.ExpiresAt.Unix())
+	return PreviewTokenPrefix + payload + "." + s.signature(payload)
+}
+
+// verify 校验预览令牌签名并解析内容
+func (s *previewService) verify(rawToken string) (articleID, tokenID uint, expiresAt time.Time, ok bool) {
+	if !IsPreviewToken(rawToken) {
+		return 0, 0, time.Time{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(rawToken, PreviewTokenPrefix), ".")
+	if len(parts) != 4 {
+		return 0, 0, time.Time{}, false
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.signature(payload))) {
+		return 0, 0, time.Time{}, false
+	}
+
+	aid, err1 := strconv.ParseUint(parts[0], 10, 64)
+	tid, err2 := strconv.ParseUint(parts[1], 10, 64)
+	exp, err3 := strconv.ParseInt(parts[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, 0, time.Time{}, false
+	}
+	return uint(aid), uint(tid), time.Unix(exp, 0), true
+}
+
+// signature 计算 HMAC-SHA256 签名（URL安全的Base64编码）
+func (s *previewService) signature(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.previewConfig().Secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// previewConfig 预览链接配置，未配置的项使用默认值
+func (s *previewService) previewConfig() config.PreviewConfig {
+	var cfg config.PreviewConfig
+	if s.config != nil {
+		cfg = s.config.Article.Preview
+	}
+	if cfg.DefaultHours <= 0 {
+		cfg.DefaultHours = defaultPreviewHours
+	}
+	if cfg.MaxHours <= 0 {
+		cfg.MaxHours = defaultPreviewMaxHours
+	}
+	if cfg.MaxPerArticle <= 0 {
+		cfg.MaxPerArticle = defaultPreviewMaxPerArticle
+	}
+	if cfg.Secret == "" && s.config != nil {
+		cfg.Secret = s.config.JWT.AccessSecret
+	}
+	return cfg
+}
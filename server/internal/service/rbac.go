@@ -1,18 +1,32 @@
 // Package service RBAC权限管理服务
 package service
 
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
 // Role 角色定义
 type Role string
 
+// 内置角色，取值与 model.UserRole 保持一致
 const (
 	// RoleSuperAdmin 超级管理员 - 拥有系统最高权限
-	RoleSuperAdmin Role = "superadmin"
+	RoleSuperAdmin = Role(model.RoleSuperAdmin)
 	// RoleAdmin 管理员 - 可管理内容和普通用户
-	RoleAdmin Role = "admin"
+	RoleAdmin = Role(model.RoleAdmin)
 	// RoleEditor 编辑者 - 可发布和管理文章
-	RoleEditor Role = "editor"
+	RoleEditor = Role(model.RoleEditor)
 	// RoleUser 普通用户 - 只读权限，评论
-	RoleUser Role = "user"
+	RoleUser = Role(model.RoleUser)
 )
 
 // Permission 权限定义
@@ -57,8 +71,30 @@ const (
 	PermissionFileDelete Permission = "file:delete" // 文件删除
 )
 
-// RolePermissions 角色权限映射
-var RolePermissions = map[Role][]Permission{
+// AllPermissions 获取系统定义的全部权限
+func AllPermissions() []Permission {
+	return []Permission{
+		PermissionSystemConfig, PermissionSystemLogs, PermissionSystemStats,
+		PermissionUserCreate, PermissionUserRead, PermissionUserUpdate, PermissionUserDelete, PermissionUserList,
+		PermissionArticleCreate, PermissionArticleRead, PermissionArticleUpdate, PermissionArticleDelete, PermissionArticleList, PermissionArticlePublish, PermissionArticleManage,
+		PermissionCategoryManage, PermissionTagManage,
+		PermissionCommentCreate, PermissionCommentRead, PermissionCommentUpdate, PermissionCommentDelete, PermissionCommentModerate,
+		PermissionFileUpload, PermissionFileRead, PermissionFileDelete,
+	}
+}
+
+// IsValidPermission 检查是否为系统定义的权限
+func IsValidPermission(permission string) bool {
+	for _, p := range AllPermissions() {
+		if string(p) == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltinRolePermissions 内置角色的默认权限，仅用于初始化角色表和数据库不可用时的兜底
+var BuiltinRolePermissions = map[Role][]Permission{
 	RoleSuperAdmin: {
 		// 超级管理员拥有所有权限
 		PermissionSystemConfig, PermissionSystemLogs, PermissionSystemStats,
@@ -91,14 +127,45 @@ var RolePermissions = map[Role][]Permission{
 	},
 }
 
-// RoleHierarchy 角色层级定义（数字越大权限越高）
-var RoleHierarchy = map[Role]int{
+// BuiltinRoleHierarchy 内置角色的默认层级（数字越大权限越高）
+var BuiltinRoleHierarchy = map[Role]int{
 	RoleUser:       1,
 	RoleEditor:     2,
 	RoleAdmin:      3,
 	RoleSuperAdmin: 4,
 }
 
+// rbacCacheTTL 角色缓存有效期，多实例部署时其他实例的修改最迟在此时间后生效
+const rbacCacheTTL = 5 * time.Minute
+
+// roleNamePattern 自定义角色标识格式
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+// RoleInfo 角色信息
+type RoleInfo struct {
+	Name        string       `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Description string       `json:"description"`
+	Level       int          `json:"level"`
+	IsBuiltin   bool         `json:"isBuiltin"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// SaveRoleRequest 创建/更新角色请求
+type SaveRoleRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=20"`
+	DisplayName string   `json:"displayName" binding:"required,min=1,max=50"`
+	Description string   `json:"description" binding:"max=255"`
+	Level       int      `json:"level" binding:"required,min=1,max=3"` // 层级不能与超级管理员相同
+	Permissions []string `json:"permissions"`
+}
+
+// cachedRole 缓存中的角色
+type cachedRole struct {
+	info        *RoleInfo
+	permissions map[Permission]bool
+}
+
 // RBACService RBAC权限管理服务接口
 type RBACService interface {
 	// HasPermission 检查用户是否有指定权限
@@ -115,29 +182,145 @@ type RBACService interface {
 	IsValidRole(role string) bool
 	// CanManageUser 检查是否可以管理指定角色的用户
 	CanManageUser(managerRole, targetRole string) bool
+
+	// 角色管理
+	ListRoles() ([]*RoleInfo, error)
+	CreateRole(req *SaveRoleRequest) (*RoleInfo, error)
+	UpdateRole(req *SaveRoleRequest) (*RoleInfo, error)
+	DeleteRole(name string) error
+	// SeedBuiltinRoles 初始化缺失的内置角色
+	SeedBuiltinRoles() error
+	// InvalidateCache 使角色缓存失效，下次访问时重新加载
+	InvalidateCache()
+}
+
+type rbacService struct {
+	roleRepo repository.RoleRepository
+
+	mu       sync.RWMutex
+	roles    map[string]*cachedRole
+	loadedAt time.Time
+}
+
+// NewRBACService 创建RBAC权限管理服务实例，roleRepo 为空时只使用内置角色
+func NewRBACService(roleRepo repository.RoleRepository) RBACService {
+	return &rbacService{roleRepo: roleRepo}
 }
 
-type rbacService struct{}
+// getRole 从缓存获取角色，缓存过期时重新加载
+func (s *rbacService) getRole(name string) (*cachedRole, bool) {
+	s.mu.RLock()
+	roles, loadedAt := s.roles, s.loadedAt
+	s.mu.RUnlock()
 
-// NewRBACService 创建RBAC权限管理服务实例
-func NewRBACService() RBACService {
-	return &rbacService{}
+	if roles == nil || time.Since(loadedAt) > rbacCacheTTL {
+		roles = s.reload()
+	}
+
+	role, exists := roles[name]
+	return role, exists
+}
+
+// reload 从数据库重新加载全部角色，失败时保留旧缓存或使用内置角色
+func (s *rbacService) reload() map[string]*cachedRole {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 其他协程可能已经完成加载
+	if s.roles != nil && time.Since(s.loadedAt) <= rbacCacheTTL {
+		return s.roles
+	}
+
+	roles, err := s.loadRoles()
+	if err != nil {
+		log.Printf("加载角色权限失败，继续使用缓存或内置角色: %v", err)
+		if s.roles == nil {
+			s.roles = builtinRoles()
+		}
+		// 失败后推迟重试，避免每次请求都访问数据库
+		s.loadedAt = time.Now()
+		return s.roles
+	}
+
+	s.roles = roles
+	s.loadedAt = time.Now()
+	return s.roles
+}
+
+// loadRoles 从数据库加载角色
+func (s *rbacService) loadRoles() (map[string]*cachedRole, error) {
+	if s.roleRepo == nil {
+		return builtinRoles(), nil
+	}
+
+	records, err := s.roleRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("角色表为空")
+	}
+
+	roles := make(map[string]*cachedRole, len(records))
+	for _, record := range records {
+		permissions := make([]Permission, 0, len(record.Permissions))
+		for _, p := range record.Permissions {
+			permissions = append(permissions, Permission(p.Permission))
+		}
+		// 超级管理员始终拥有全部权限
+		if record.Name == string(RoleSuperAdmin) {
+			permissions = AllPermissions()
+		}
+		roles[record.Name] = newCachedRole(&RoleInfo{
+			Name:        record.Name,
+			DisplayName: record.DisplayName,
+			Description: record.Description,
+			Level:       record.Level,
+			IsBuiltin:   record.IsBuiltin,
+			Permissions: permissions,
+		})
+	}
+	return roles, nil
+}
+
+// builtinRoles 构建内置角色缓存
+func builtinRoles() map[string]*cachedRole {
+	roles := make(map[string]*cachedRole, len(BuiltinRolePermissions))
+	for role, permissions := range BuiltinRolePermissions {
+		roles[string(role)] = newCachedRole(&RoleInfo{
+			Name:        string(role),
+			DisplayName: GetRoleDisplayName(string(role)),
+			Level:       BuiltinRoleHierarchy[role],
+			IsBuiltin:   true,
+			Permissions: permissions,
+		})
+	}
+	return roles
+}
+
+// newCachedRole 创建缓存角色
+func newCachedRole(info *RoleInfo) *cachedRole {
+	permissions := make(map[Permission]bool, len(info.Permissions))
+	for _, p := range info.Permissions {
+		permissions[p] = true
+	}
+	return &cachedRole{info: info, permissions: permissions}
+}
+
+// InvalidateCache 使角色缓存失效
+func (s *rbacService) InvalidateCache() {
+	s.mu.Lock()
+	s.roles = nil
+	s.mu.Unlock()
 }
 
 // HasPermission 检查用户是否有指定权限
 func (s *rbacService) HasPermission(userRole string, permission Permission) bool {
-	role := Role(userRole)
-	permissions, exists := RolePermissions[role]
+	role, exists := s.getRole(userRole)
 	if !exists {
 		return false
 	}
-
-	for _, p := range permissions {
-		if p == permission {
-			return true
-		}
-	}
-	return false
+	return role.permissions[permission]
 }
 
 // HasAnyPermission 检查用户是否有任意一个权限
@@ -162,29 +345,28 @@ func (s *rbacService) HasAllPermissions(userRole string, permissions ...Permissi
 
 // GetUserPermissions 获取用户的所有权限
 func (s *rbacService) GetUserPermissions(userRole string) []Permission {
-	role := Role(userRole)
-	permissions, exists := RolePermissions[role]
+	role, exists := s.getRole(userRole)
 	if !exists {
 		return []Permission{}
 	}
-	return permissions
+	return role.info.Permissions
 }
 
 // IsRoleHigherThan 检查角色A是否比角色B权限更高
 func (s *rbacService) IsRoleHigherThan(roleA, roleB string) bool {
-	levelA, existsA := RoleHierarchy[Role(roleA)]
-	levelB, existsB := RoleHierarchy[Role(roleB)]
+	a, existsA := s.getRole(roleA)
+	b, existsB := s.getRole(roleB)
 
 	if !existsA || !existsB {
 		return false
 	}
 
-	return levelA > levelB
+	return a.info.Level > b.info.Level
 }
 
 // IsValidRole 检查是否为有效角色
 func (s *rbacService) IsValidRole(role string) bool {
-	_, exists := RoleHierarchy[Role(role)]
+	_, exists := s.getRole(role)
 	return exists
 }
 
@@ -195,15 +377,186 @@ func (s *rbacService) CanManageUser(managerRole, targetRole string) bool {
 		return true
 	}
 
-	// 管理员可以管理编辑者和普通用户，但不能管理超级管理员和其他管理员
+	// 管理员可以管理层级低于自己的用户（编辑者、普通用户及低层级的自定义角色），
+	// 但不能管理超级管理员和其他管理员
 	if managerRole == string(RoleAdmin) {
-		return targetRole == string(RoleEditor) || targetRole == string(RoleUser)
+		return s.IsRoleHigherThan(managerRole, targetRole)
 	}
 
 	// 其他角色不能管理用户
 	return false
 }
 
+// ListRoles 获取全部角色
+func (s *rbacService) ListRoles() ([]*RoleInfo, error) {
+	roles, err := s.loadRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*RoleInfo, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, role.info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Level != result[j].Level {
+			return result[i].Level > result[j].Level
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// CreateRole 创建自定义角色
+func (s *rbacService) CreateRole(req *SaveRoleRequest) (*RoleInfo, error) {
+	if s.roleRepo == nil {
+		return nil, errors.New("未启用角色管理")
+	}
+	if !roleNamePattern.MatchString(req.Name) {
+		return nil, errors.New("角色标识只能包含小写字母、数字和下划线，且以字母开头")
+	}
+	if existing, _ := s.roleRepo.GetByName(req.Name); existing != nil {
+		return nil, fmt.Errorf("角色已存在: %s", req.Name)
+	}
+
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		Level:       req.Level,
+	}
+	if err := s.roleRepo.Create(role, permissions); err != nil {
+		return nil, err
+	}
+	s.InvalidateCache()
+
+	return s.roleInfo(req.Name)
+}
+
+// UpdateRole 更新角色信息和权限
+func (s *rbacService) UpdateRole(req *SaveRoleRequest) (*RoleInfo, error) {
+	if s.roleRepo == nil {
+		return nil, errors.New("未启用角色管理")
+	}
+	if req.Name == string(RoleSuperAdmin) {
+		return nil, errors.New("超级管理员角色不能修改")
+	}
+
+	role, err := s.roleRepo.GetByName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := normalizePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.DisplayName = req.DisplayName
+	role.Description = req.Description
+	// 内置角色的层级决定了既有的管理关系，不允许调整
+	if !role.IsBuiltin {
+		role.Level = req.Level
+	}
+	if err := s.roleRepo.Update(role, permissions); err != nil {
+		return nil, err
+	}
+	s.InvalidateCache()
+
+	return s.roleInfo(req.Name)
+}
+
+// DeleteRole 删除自定义角色
+func (s *rbacService) DeleteRole(name string) error {
+	if s.roleRepo == nil {
+		return errors.New("未启用角色管理")
+	}
+
+	role, err := s.roleRepo.GetByName(name)
+	if err != nil {
+		return err
+	}
+	if role.IsBuiltin {
+		return errors.New("内置角色不能删除")
+	}
+
+	count, err := s.roleRepo.CountUsers(name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("仍有%d个用户使用该角色，请先调整这些用户的角色", count)
+	}
+
+	if err := s.roleRepo.Delete(role.ID); err != nil {
+		return err
+	}
+	s.InvalidateCache()
+	return nil
+}
+
+// SeedBuiltinRoles 初始化缺失的内置角色，已存在的角色不会被覆盖
+func (s *rbacService) SeedBuiltinRoles() error {
+	if s.roleRepo == nil {
+		return nil
+	}
+
+	for _, role := range GetAllRoles() {
+		if existing, _ := s.roleRepo.GetByName(string(role)); existing != nil {
+			continue
+		}
+
+		permissions := make([]string, 0, len(BuiltinRolePermissions[role]))
+		for _, p := range BuiltinRolePermissions[role] {
+			permissions = append(permissions, string(p))
+		}
+
+		record := &model.Role{
+			Name:        string(role),
+			DisplayName: GetRoleDisplayName(string(role)),
+			Level:       BuiltinRoleHierarchy[role],
+			IsBuiltin:   true,
+		}
+		if err := s.roleRepo.Create(record, permissions); err != nil {
+			return fmt.Errorf("初始化内置角色 %s 失败: %w", role, err)
+		}
+	}
+
+	s.InvalidateCache()
+	return nil
+}
+
+// roleInfo 获取最新的角色信息
+func (s *rbacService) roleInfo(name string) (*RoleInfo, error) {
+	role, exists := s.getRole(name)
+	if !exists {
+		return nil, errors.New("角色不存在")
+	}
+	return role.info, nil
+}
+
+// normalizePermissions 校验并去重权限列表
+func normalizePermissions(permissions []string) ([]string, error) {
+	result := make([]string, 0, len(permissions))
+	seen := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		if seen[p] {
+			continue
+		}
+		if !IsValidPermission(p) {
+			return nil, fmt.Errorf("无效的权限: %s", p)
+		}
+		seen[p] = true
+		result = append(result, p)
+	}
+	return result, nil
+}
+
 // GetRoleDisplayName 获取角色显示名称
 func GetRoleDisplayName(role string) string {
 	switch Role(role) {
@@ -230,11 +583,11 @@ func IsAdminRole(role string) bool {
 	return role == string(RoleAdmin) || role == string(RoleSuperAdmin)
 }
 
-// IsEditorOrAbove 检查是否为编辑者及以上角色
+// IsEditorOrAbove 检查是否为编辑者及以上的内置角色
 func IsEditorOrAbove(role string) bool {
-	level, exists := RoleHierarchy[Role(role)]
+	level, exists := BuiltinRoleHierarchy[Role(role)]
 	if !exists {
 		return false
 	}
-	return level >= RoleHierarchy[RoleEditor]
+	return level >= BuiltinRoleHierarchy[RoleEditor]
 }
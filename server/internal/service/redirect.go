@@ -0,0 +1,235 @@
+// Package service 路径跳转规则服务
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+)
+
+// maxRedirectChain 保存规则时检查跳转链的最大深度
+const maxRedirectChain = 10
+
+// SaveRedirectRequest 创建或更新跳转规则请求
+type SaveRedirectRequest struct {
+	ID         uint   `json:"id"` // 更新时必填
+	FromPath   string `json:"fromPath" binding:"required,max=500"`
+	ToPath     string `json:"toPath" binding:"required,max=1000"`
+	StatusCode int    `json:"statusCode" binding:"omitempty,oneof=301 302"` // 默认 301
+	Note       string `json:"note" binding:"max=200"`
+}
+
+// RedirectListRequest 跳转规则列表请求
+type RedirectListRequest struct {
+	Keyword  string `json:"keyword" binding:"max=100"`
+	Page     int    `json:"page" binding:"omitempty,min=1"`
+	PageSize int    `json:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// RedirectListResponse 跳转规则列表响应
+type RedirectListResponse struct {
+	Redirects []*model.Redirect `json:"redirects"`
+	Total     int64             `json:"total"`
+	Page      int               `json:"page"`
+	PageSize  int               `json:"pageSize"`
+}
+
+// RedirectService 跳转规则服务接口
+type RedirectService interface {
+	ListRedirects(req *RedirectListRequest) (*RedirectListResponse, error)
+	GetRedirect(id uint) (*model.Redirect, error)
+	CreateRedirect(req *SaveRedirectRequest, userID uint) (*model.Redirect, error)
+	UpdateRedirect(req *SaveRedirectRequest) (*model.Redirect, error)
+	DeleteRedirect(id uint) error
+	// Resolve 查找路径对应的跳转规则并记录命中，没有规则时返回 nil
+	Resolve(path string) (*model.Redirect, error)
+}
+
+// redirectService 跳转规则服务实现
+type redirectService struct {
+	redirectRepo repository.RedirectRepository
+}
+
+// NewRedirectService 创建跳转规则服务实例
+func NewRedirectService(redirectRepo repository.RedirectRepository) RedirectService {
+	return &redirectService{redirectRepo: redirectRepo}
+}
+
+// ListRedirects 分页获取跳转规则
+func (s *redirectService) ListRedirects(req *RedirectListRequest) (*RedirectListResponse, error) {
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	redirects, total, err := s.redirectRepo.List(strings.TrimSpace(req.Keyword), (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedirectListResponse{
+		Redirects: redirects,
+		Total:     total,
+		Page:      req.Page,
+		PageSize:  req.PageSize,
+	}, nil
+}
+
+// GetRedirect 获取跳转规则
+func (s *redirectService) GetRedirect(id uint) (*model.Redirect, error) {
+	return s.redirectRepo.GetByID(id)
+}
+
+// CreateRedirect 创建跳转规则
+func (s *redirectService) CreateRedirect(req *SaveRedirectRequest, userID uint) (*model.Redirect, error) {
+	redirect := &model.Redirect{CreatedBy: userID}
+	if err := s.apply(redirect, req); err != nil {
+		return nil, err
+	}
+	if err := s.redirectRepo.Create(redirect); err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
+
+// UpdateRedirect 更新跳转规则
+func (s *redirectService) UpdateRedirect(req *SaveRedirectRequest) (*model.Redirect, error) {
+	if req.ID == 0 {
+		return nil, errors.New("缺少跳转规则ID")
+	}
+	redirect, err := s.redirectRepo.GetByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(redirect, req); err != nil {
+		return nil, err
+	}
+	if err := s.redirectRepo.Update(redirect); err != nil {
+		return nil, err
+	}
+	return redirect, nil
+}
+
+// DeleteRedirect 删除跳转规则
+func (s *redirectService) DeleteRedirect(id uint) error {
+	return s.redirectRepo.Delete(id)
+}
+
+// Resolve 查找路径对应的跳转规则
+func (s *redirectService) Resolve(path string) (*model.Redirect, error) {
+	from, err := normalizeRedirectPath(path)
+	if err != nil {
+		return nil, nil
+	}
+	redirect, err := s.redirectRepo.GetByFromPath(from)
+	if err != nil || redirect == nil {
+		return nil, err
+	}
+
+	// 命中统计失败不影响跳转
+	_ = s.redirectRepo.RecordHit(redirect.ID, time.Now())
+	return redirect, nil
+}
+
+// apply 校验请求并写入规则字段
+func (s *redirectService) apply(redirect *model.Redirect, req *SaveRedirectRequest) error {
+	from, err := normalizeRedirectPath(req.FromPath)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(from, "/api/") || from == "/api" {
+		return errors.New("不能为接口路径设置跳转")
+	}
+	to, err := normalizeRedirectTarget(req.ToPath)
+	if err != nil {
+		return err
+	}
+	if sameRedirectPath(to, from) {
+		return errors.New("源路径和目标路径不能相同")
+	}
+
+	// 源路径唯一
+	existing, err := s.redirectRepo.GetByFromPath(from)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != redirect.ID {
+		return fmt.Errorf("源路径 %s 已存在跳转规则", from)
+	}
+
+	// 沿目标路径检查跳转链，避免形成循环
+	next := to
+	for i := 0; i < maxRedirectChain && strings.HasPrefix(next, "/"); i++ {
+		nextPath, _ := normalizeRedirectPath(next)
+		rule, err := s.redirectRepo.GetByFromPath(nextPath)
+		if err != nil {
+			return err
+		}
+		if rule == nil || rule.ID == redirect.ID {
+			break
+		}
+		if sameRedirectPath(rule.ToPath, from) {
+			return fmt.Errorf("跳转规则会形成循环：%s → %s → %s", from, nextPath, rule.ToPath)
+		}
+		next = rule.ToPath
+	}
+
+	redirect.FromPath = from
+	redirect.ToPath = to
+	redirect.StatusCode = req.StatusCode
+	if redirect.StatusCode == 0 {
+		redirect.StatusCode = http.StatusMovedPermanently
+	}
+	redirect.Note = req.Note
+	return nil
+}
+
+// normalizeRedirectPath 规范化源路径：必须以 / 开头，去掉查询参数、锚点和末尾的 /
+func normalizeRedirectPath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return "", errors.New("源路径必须以 / 开头")
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path, nil
+}
+
+// sameRedirectPath 判断站内目标是否与源路径相同
+func sameRedirectPath(target, from string) bool {
+	if !strings.HasPrefix(target, "/") {
+		return false
+	}
+	path, err := normalizeRedirectPath(target)
+	return err == nil && path == from
+}
+
+// normalizeRedirectTarget 规范化目标：站内路径（以 / 开头，保留查询参数）或 http(s) 完整地址
+// 浏览器会把 \ 当作 / 处理，/\evil.com 与 //evil.com 一样会跳到站外，站内路径中不允许出现反斜杠
+func normalizeRedirectTarget(target string) (string, error) {
+	target = strings.TrimSpace(target)
+	if strings.HasPrefix(target, "/") {
+		u, err := url.Parse(target)
+		if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(target, "//") || strings.Contains(target, "\\") {
+			return "", errors.New("站内路径不能指向其他站点")
+		}
+		return target, nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("目标必须是以 / 开头的站内路径或 http(s) 地址")
+	}
+	return target, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"MyBlog/internal/config"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/pkg/search"
+)
+
+// maxLoggedKeywordLength 搜索记录中关键词的最大长度（字符数），与 search_logs.keyword 列宽一致
+const maxLoggedKeywordLength = 255
+
+// SearchLogEntry 一次搜索的记录内容
+type SearchLogEntry struct {
+	Keyword   string
+	Results   int64
+	UserID    *uint
+	IPAddress string
+	UserAgent string
+}
+
+// SearchSuggestRequest 搜索建议请求
+type SearchSuggestRequest struct {
+	Prefix string `json:"prefix" binding:"required,max=100"`
+	Limit  int    `json:"limit" binding:"omitempty,min=1,max=20"` // 每类建议的数量，默认 8
+}
+
+// ArticleSuggestion 标题匹配的文章
+type ArticleSuggestion struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// SearchSuggestResponse 搜索建议响应
+type SearchSuggestResponse struct {
+	Keywords []string             `json:"keywords"` // 以输入开头的热门搜索词
+	Articles []*ArticleSuggestion `json:"articles"` // 标题以输入开头的文章
+}
+
+// HotSearchRequest 热门搜索请求
+type HotSearchRequest struct {
+	Hours int `json:"hours" binding:"omitempty,min=1,max=720"` // 统计最近多少小时，默认取配置
+	Limit int `json:"limit" binding:"omitempty,min=1,max=50"`  // 返回数量，默认 10
+}
+
+// HotSearchResponse 热门搜索响应
+type HotSearchResponse struct {
+	Hours    int                       `json:"hours"`
+	Keywords []*repository.KeywordStat `json:"keywords"`
+}
+
+// ZeroResultReportRequest 无结果搜索报告请求
+type ZeroResultReportRequest struct {
+	Days     int `json:"days" binding:"omitempty,min=1,max=365"` // 统计最近多少天，默认 30
+	Page     int `json:"page" binding:"omitempty,min=1"`
+	PageSize int `json:"pageSize" binding:"omitempty,min=1,max=100"`
+}
+
+// ZeroResultReportResponse 无结果搜索报告响应
+type ZeroResultReportResponse struct {
+	Days     int                       `json:"days"`
+	Keywords []*repository.KeywordStat `json:"keywords"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"pageSize"`
+}
+
+// SearchService 搜索记录与统计服务接口
+type SearchService interface {
+	// LogSearch 记录一次搜索，写入失败只记录日志，不影响搜索请求
+	LogSearch(entry *SearchLogEntry)
+	// Suggest 按输入前缀给出搜索建议
+	Suggest(req *SearchSuggestRequest) (*SearchSuggestResponse, error)
+	// HotSearches 统计最近一段时间的热门搜索
+	HotSearches(req *HotSearchRequest) (*HotSearchResponse, error)
+	// ZeroResultReport 统计没有搜索结果的关键词
+	ZeroResultReport(req *ZeroResultReportRequest) (*ZeroResultReportResponse, error)
+	// CleanupLogs 删除超过保留天数的搜索记录
+	CleanupLogs() (int64, error)
+}
+
+// searchService 搜索记录与统计服务实现
+type searchService struct {
+	searchLogRepo repository.SearchLogRepository
+	articleRepo   repository.ArticleRepositoryInterface
+	config        *config.Config
+}
+
+// NewSearchService 创建搜索记录与统计服务实例
+func NewSearchService(searchLogRepo repository.SearchLogRepository, articleRepo repository.ArticleRepositoryInterface, cfg *config.Config) SearchService {
+	return &searchService{
+		searchLogRepo: searchLogRepo,
+		articleRepo:   articleRepo,
+		config:        cfg,
+	}
+}
+
+// LogSearch 记录一次搜索
+func (s *searchService) LogSearch(entry *SearchLogEntry) {
+	keyword := []rune(entry.Keyword)
+	if len(keyword) > maxLoggedKeywordLength {
+		keyword = keyword[:maxLoggedKeywordLength]
+	}
+	record := &model.SearchLog{
+		UserID:       entry.UserID,
+		Keyword:      string(keyword),
+		Normalized:   search.Normalize(entry.Keyword, maxLoggedKeywordLength),
+		ResultsCount: int(entry.Results),
+	}
+	if entry.IPAddress != "" {
+		record.IPAddress = &entry.IPAddress
+	}
+	if entry.UserAgent != "" {
+		record.UserAgent = &entry.UserAgent
+	}
+	if err := s.searchLogRepo.Create(record); err != nil {
+		log.Printf("记录搜索失败: %v", err)
+	}
+}
+
+// Suggest 搜索建议：最近有结果的热门搜索词和标题匹配的已发布文章
+func (s *searchService) Suggest(req *SearchSuggestRequest) (*SearchSuggestResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 8
+	}
+	prefix := search.Normalize(req.Prefix, 0)
+	result := &SearchSuggestResponse{
+		Keywords: []string{},
+		Articles: []*ArticleSuggestion{},
+	}
+	if prefix == "" {
+		return result, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -s.suggestWindowDays())
+	stats, err := s.searchLogRepo.Popular(prefix, since, s.minSearchers(), limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, stat := range stats {
+		result.Keywords = append(result.Keywords, stat.Keyword)
+	}
+
+	// 标题按用户原样输入匹配，数据库排序规则不区分大小写
+	articles, err := s.articleRepo.SuggestTitles(req.Prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, article := range articles {
+		result.Articles = append(result.Articles, &ArticleSuggestion{
+			ID:    article.ID,
+			Title: article.Title,
+			Slug:  article.Slug,
+		})
+	}
+
+	return result, nil
+}
+
+// HotSearches 统计最近一段时间有结果的搜索词，按搜索人数排序
+func (s *searchService) HotSearches(req *HotSearchRequest) (*HotSearchResponse, error) {
+	hours := req.Hours
+	if hours <= 0 {
+		hours = s.hotWindowHours()
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	stats, err := s.searchLogRepo.Popular("", since, s.minSearchers(), limit)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []*repository.KeywordStat{}
+	}
+	return &HotSearchResponse{Hours: hours, Keywords: stats}, nil
+}
+
+// ZeroResultReport 统计最近一段时间没有结果的搜索词
+func (s *searchService) ZeroResultReport(req *ZeroResultReportRequest) (*ZeroResultReportResponse, error) {
+	if req.Days <= 0 {
+		req.Days = 30
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+
+	since := time.Now().AddDate(0, 0, -req.Days)
+	stats, total, err := s.searchLogRepo.ZeroResults(since, (req.Page-1)*req.PageSize, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []*repository.KeywordStat{}
+	}
+	return &ZeroResultReportResponse{
+		Days:     req.Days,
+		Keywords: stats,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// CleanupLogs 删除超过保留天数的搜索记录
+func (s *searchService) CleanupLogs() (int64, error) {
+	if s.config == nil || s.config.Article.Search.LogRetentionDays <= 0 {
+		return 0, nil
+	}
+	return s.searchLogRepo.DeleteBefore(time.Now().AddDate(0, 0, -s.config.Article.Search.LogRetentionDays))
+}
+
+// hotWindowHours 热门搜索默认统计窗口（小时）
+func (s *searchService) hotWindowHours() int {
+	if s.config != nil && s.config.Article.Search.HotWindowHours > 0 {
+		return s.config.Article.Search.HotWindowHours
+	}
+	return 24
+}
+
+// minSearchers 公开的搜索建议和热门搜索要求的最少搜索人数，避免个别人搜索的词（可能含隐私或不当内容）被公开展示
+func (s *searchService) minSearchers() int {
+	if s.config != nil && s.config.Article.Search.MinSearchers > 0 {
+		return s.config.Article.Search.MinSearchers
+	}
+	return 3
+}
+
+// suggestWindowDays 搜索建议使用的搜索记录天数
+func (s *searchService) suggestWindowDays() int {
+	if s.config != nil && s.config.Article.Search.SuggestWindowDays > 0 {
+		return s.config.Article.Search.SuggestWindowDays
+	}
+	return 30
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"MyBlog/internal/config"
+	"MyBlog/internal/model"
+	"MyBlog/internal/repository"
+	"MyBlog/pkg/markdown"
+	"MyBlog/pkg/search"
+)
+
+// 搜索后端
+const (
+	SearchBackendMySQL    = "mysql"    // MySQL FULLTEXT 索引
+	SearchBackendEmbedded = "embedded" // 本地磁盘上的倒排索引
+)
+
+// 本地索引中的字段
+// 会员文章的正文单独建字段，只有登录用户的搜索会匹配该字段
+const (
+	searchFieldTitle         = "title"
+	searchFieldSummary       = "summary"
+	searchFieldContent       = "content"
+	searchFieldMemberContent = "member_content"
+)
+
+// 本地索引中用于过滤的属性
+const (
+	searchAttrStatus   = "status"
+	searchAttrAuthor   = "author"
+	searchAttrCategory = "category"
+	searchAttrTag      = "tag"
+)
+
+// maxSearchHits 本地索引单次搜索最多取回的命中数，更靠后的结果不参与分页
+const maxSearchHits = 1000
+
+// SearchBackend 文章搜索后端
+type SearchBackend interface {
+	// Name 后端名称
+	Name() string
+	// Search 按关键词搜索文章，返回当前页的文章和命中总数
+	Search(keyword string, params *repository.ArticleListParams) ([]*model.Article, int64, error)
+	// Index 新增或更新文章的索引，文章须包含分类和标签
+	Index(article *model.Article) error
+	// Remove 删除文章的索引
+	Remove(articleID uint) error
+	// Rebuild 重建全部索引，返回已索引的文章数
+	Rebuild() (int, error)
+	// NeedsRebuild 索引是否为空，需要重建（如首次启用）
+	NeedsRebuild() bool
+	// Close 关闭后端
+	Close() error
+}
+
+// NewSearchBackend 按配置创建搜索后端
+func NewSearchBackend(cfg *config.Config, articleRepo repository.ArticleRepositoryInterface) (SearchBackend, error) {
+	switch cfg.Article.Search.Backend {
+	case "", SearchBackendMySQL:
+		// 全文索引只在选用 mysql 后端时创建
+		if err := articleRepo.EnsureFulltextIndexes(); err != nil {
+			return nil, err
+		}
+		return NewMySQLSearchBackend(articleRepo), nil
+	case SearchBackendEmbedded:
+		return NewEmbeddedSearchBackend(cfg.Article.Search.IndexDir, articleRepo)
+	default:
+		return nil, fmt.Errorf("不支持的搜索后端: %s", cfg.Article.Search.Backend)
+	}
+}
+
+// mysqlSearchBackend 使用 MySQL FULLTEXT 索引搜索，索引在创建后端时补齐，之后由数据库自动维护
+type mysqlSearchBackend struct {
+	articleRepo repository.ArticleRepositoryInterface
+}
+
+// NewMySQLSearchBackend 创建 MySQL 搜索后端
+func NewMySQLSearchBackend(articleRepo repository.ArticleRepositoryInterface) SearchBackend {
+	return &mysqlSearchBackend{articleRepo: articleRepo}
+}
+
+func (b *mysqlSearchBackend) Name() string {
+	return SearchBackendMySQL
+}
+
+func (b *mysqlSearchBackend) Search(keyword string, params *repository.ArticleListParams) ([]*model.Article, int64, error) {
+	return b.articleRepo.Search(keyword, params)
+}
+
+func (b *mysqlSearchBackend) Index(article *model.Article) error {
+	return nil
+}
+
+func (b *mysqlSearchBackend) Remove(articleID uint) error {
+	return nil
+}
+
+func (b *mysqlSearchBackend) Rebuild() (int, error) {
+	return 0, nil
+}
+
+func (b *mysqlSearchBackend) NeedsRebuild() bool {
+	return false
+}
+
+func (b *mysqlSearchBackend) Close() error {
+	return nil
+}
+
+// embeddedSearchBackend 使用本地倒排索引搜索
+// 索引只负责匹配和相关度排序，命中的文章从数据库加载，并再次按筛选条件校验
+// 索引只随当前进程内的修改更新，只支持单实例部署（启动时以数据库实例锁保证）
+type embeddedSearchBackend struct {
+	index       *search.Index
+	articleRepo repository.ArticleRepositoryInterface
+}
+
+// NewEmbeddedSearchBackend 打开指定目录下的本地索引
+func NewEmbeddedSearchBackend(dir string, articleRepo repository.ArticleRepositoryInterface) (SearchBackend, error) {
+	index, err := search.Open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("打开搜索索引失败: %w", err)
+	}
+	return &embeddedSearchBackend{index: index, articleRepo: articleRepo}, nil
+}
+
+func (b *embeddedSearchBackend) Name() string {
+	return SearchBackendEmbedded
+}
+
+func (b *embeddedSearchBackend) Search(keyword string, params *repository.ArticleListParams) ([]*model.Article, int64, error) {
+	if strings.TrimSpace(keyword) == "" {
+		return b.articleRepo.List(params)
+	}
+
+	weights := params.Weights.WithDefaults()
+	fields := map[string]float64{
+		searchFieldTitle:   weights.Title,
+		searchFieldSummary: weights.Summary,
+		searchFieldContent: weights.Content,
+	}
+	if params.Member {
+		fields[searchFieldMemberContent] = weights.Content
+	}
+
+	filters := make(map[string][]string)
+	if params.Status != "" {
+		filters[searchAttrStatus] = []string{string(params.Status)}
+	}
+	if params.AuthorID != 0 {
+		filters[searchAttrAuthor] = []string{formatID(params.AuthorID)}
+	}
+	if params.CategoryID != 0 {
+		filters[searchAttrCategory] = []string{formatID(params.CategoryID)}
+	}
+	if params.TagID != 0 {
+		filters[searchAttrTag] = []string{formatID(params.TagID)}
+	}
+
+	hits, _ := b.index.Search(search.ParseQuery(keyword), search.SearchOptions{
+		Fields:  fields,
+		Filters: filters,
+		Limit:   maxSearchHits,
+	})
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return b.articleRepo.ListByIDs(ids, params)
+}
+
+func (b *embeddedSearchBackend) Index(article *model.Article) error {
+	return b.index.Put(articleDocument(article))
+}
+
+func (b *embeddedSearchBackend) Remove(articleID uint) error {
+	return b.index.Delete(articleID)
+}
+
+func (b *embeddedSearchBackend) Rebuild() (int, error) {
+	const batchSize = 100
+
+	var (
+		docs   []search.Document
+		lastID uint
+	)
+	for {
+		articles, err := b.articleRepo.ListForIndex(lastID, batchSize)
+		if err != nil {
+			return 0, err
+		}
+		for _, article := range articles {
+			lastID = article.ID
+			docs = append(docs, articleDocument(article))
+		}
+		if len(articles) < batchSize {
+			break
+		}
+	}
+
+	if err := b.index.Rebuild(docs); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
+
+func (b *embeddedSearchBackend) NeedsRebuild() bool {
+	return b.index.Len() == 0
+}
+
+func (b *embeddedSearchBackend) Close() error {
+	return b.index.Close()
+}
+
+// articleDocument 将文章转换为索引文档
+// 与 MySQL 搜索相同的可见范围规则：密码文章只索引标题，会员文章的正文单独建字段
+func articleDocument(article *model.Article) search.Document {
+	fields := map[string]string{searchFieldTitle: article.Title}
+	switch article.Visibility {
+	case model.VisibilityPassword:
+		// 摘要和正文都不公开，不参与检索
+	case model.VisibilityMembers:
+		fields[searchFieldSummary] = article.Summary
+		fields[searchFieldMemberContent] = markdown.PlainText(article.Content)
+	default:
+		fields[searchFieldSummary] = article.Summary
+		fields[searchFieldContent] = markdown.PlainText(article.Content)
+	}
+
+	attrs := map[string][]string{
+		searchAttrStatus: {string(article.Status)},
+		searchAttrAuthor: {formatID(article.AuthorID)},
+	}
+	if article.CategoryID != nil {
+		attrs[searchAttrCategory] = append(attrs[searchAttrCategory], formatID(*article.CategoryID))
+	}
+	for _, category := range article.Categories {
+		attrs[searchAttrCategory] = append(attrs[searchAttrCategory], formatID(category.ID))
+	}
+	for _, tag := range article.Tags {
+		attrs[searchAttrTag] = append(attrs[searchAttrTag], formatID(tag.ID))
+	}
+
+	return search.Document{ID: article.ID, Fields: fields, Attrs: attrs}
+}
+
+// formatID 将ID格式化为索引属性值
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
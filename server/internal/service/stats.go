@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"MyBlog/internal/cache"
+	"MyBlog/internal/config"
+	"MyBlog/internal/repository"
+)
+
+// ErrInvalidStatsRange 统计日期范围无效（格式错误、起止颠倒或超过最大天数）
+var ErrInvalidStatsRange = errors.New("统计日期范围无效")
+
+// StatsRangeRequest 统计日期范围，起止日期均包含在内，默认最近30天
+type StatsRangeRequest struct {
+	From string `json:"from" binding:"omitempty,datetime=2006-01-02"` // 开始日期 YYYY-MM-DD
+	To   string `json:"to" binding:"omitempty,datetime=2006-01-02"`   // 结束日期 YYYY-MM-DD，默认今天
+}
+
+// StatsTopRequest 排行请求
+type StatsTopRequest struct {
+	StatsRangeRequest
+	Limit int `json:"limit" binding:"omitempty,min=1,max=50"` // 每个排行的数量，默认10
+}
+
+// StatsDay 某天的各项数据
+type StatsDay struct {
+	Date     string `json:"date,omitempty"`
+	Views    int64  `json:"views"`    // 浏览量
+	Visitors int64  `json:"visitors"` // 访客数
+	Comments int64  `json:"comments"` // 新增评论数
+	Users    int64  `json:"users"`    // 新注册用户数
+}
+
+// StatsTimeSeries 每日趋势，包含范围内的每一天
+type StatsTimeSeries struct {
+	From   string      `json:"from"`
+	To     string      `json:"to"`
+	Days   []*StatsDay `json:"days"`
+	Totals *StatsDay   `json:"totals"` // 范围合计，visitors 为范围内去重后的访客数
+}
+
+// StatsTop 时间范围内的排行
+type StatsTop struct {
+	From      string                        `json:"from"`
+	To        string                        `json:"to"`
+	Articles  []*repository.ArticleViewStat `json:"articles"`  // 浏览量最高的文章
+	Referrers []*repository.ReferrerStat    `json:"referrers"` // 来源站点
+	Searches  []*repository.KeywordStat     `json:"searches"`  // 热门搜索词
+}
+
+// StatsService 后台统计服务接口
+type StatsService interface {
+	// Overview 站点总量
+	Overview() (*repository.SiteTotals, error)
+	// TimeSeries 每日浏览量、访客数、新增评论和新用户
+	TimeSeries(req *StatsRangeRequest) (*StatsTimeSeries, error)
+	// Top 热门文章、来源站点和热门搜索词
+	Top(req *StatsTopRequest) (*StatsTop, error)
+}
+
+// statsService 后台统计服务实现
+type statsService struct {
+	statsRepo repository.StatsRepository
+	cache     cache.CacheService
+	config    *config.Config
+}
+
+// NewStatsService 创建后台统计服务实例
+func NewStatsService(statsRepo repository.StatsRepository, cacheService cache.CacheService, cfg *config.Config) StatsService {
+	return &statsService{
+		statsRepo: statsRepo,
+		cache:     cacheService,
+		config:    cfg,
+	}
+}
+
+// Overview 站点总量
+func (s *statsService) Overview() (*repository.SiteTotals, error) {
+	var totals *repository.SiteTotals
+	err := s.cached(cache.CacheKeyStats, &totals, func() (err error) {
+		totals, err = s.statsRepo.SiteTotals()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// TimeSeries 每日趋势，没有数据的日期补0
+func (s *statsService) TimeSeries(req *StatsRangeRequest) (*StatsTimeSeries, error) {
+	from, to, err := s.parseRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var series *StatsTimeSeries
+	key := cache.GetCacheKey(cache.CacheKeyStatsTimeSeries, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	err = s.cached(key, &series, func() (err error) {
+		series, err = s.loadTimeSeries(from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// loadTimeSeries 查询每日趋势
+func (s *statsService) loadTimeSeries(from, to time.Time) (*StatsTimeSeries, error) {
+	end := to.AddDate(0, 0, 1)
+	series := &StatsTimeSeries{
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Days:   []*StatsDay{},
+		Totals: &StatsDay{},
+	}
+	byDate := make(map[string]*StatsDay)
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		item := &StatsDay{Date: day.Format("2006-01-02")}
+		series.Days = append(series.Days, item)
+		byDate[item.Date] = item
+	}
+
+	views, err := s.statsRepo.ViewsByDay(from, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		if item, ok := byDate[view.Date]; ok {
+			item.Views, item.Visitors = view.Views, view.Visitors
+			series.Totals.Views += view.Views
+		}
+	}
+	if series.Totals.Visitors, err = s.statsRepo.UniqueVisitors(from, end); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.statsRepo.CommentsByDay(from, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		if item, ok := byDate[comment.Date]; ok {
+			item.Comments = comment.Count
+			series.Totals.Comments += comment.Count
+		}
+	}
+
+	users, err := s.statsRepo.UsersByDay(from, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if item, ok := byDate[user.Date]; ok {
+			item.Users = user.Count
+			series.Totals.Users += user.Count
+		}
+	}
+
+	return series, nil
+}
+
+// Top 时间范围内的排行
+func (s *statsService) Top(req *StatsTopRequest) (*StatsTop, error) {
+	from, to, err := s.parseRange(&req.StatsRangeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	var top *StatsTop
+	key := cache.GetCacheKey(cache.CacheKeyStatsTop, from.Format("2006-01-02"), to.Format("2006-01-02"), req.Limit)
+	err = s.cached(key, &top, func() error {
+		end := to.AddDate(0, 0, 1)
+		top = &StatsTop{From: from.Format("2006-01-02"), To: to.Format("2006-01-02")}
+
+		var err error
+		if top.Articles, err = s.statsRepo.TopArticles(from, end, req.Limit); err != nil {
+			return err
+		}
+		if top.Referrers, err = s.statsRepo.TopReferrers(from, end, req.Limit); err != nil {
+			return err
+		}
+		if top.Searches, err = s.statsRepo.TopSearches(from, end, req.Limit); err != nil {
+			return err
+		}
+		if top.Articles == nil {
+			top.Articles = []*repository.ArticleViewStat{}
+		}
+		if top.Referrers == nil {
+			top.Referrers = []*repository.ReferrerStat{}
+		}
+		if top.Searches == nil {
+			top.Searches = []*repository.KeywordStat{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return top, nil
+}
+
+// parseRange 解析日期范围，返回开始日期和结束日期（均为当天零点，结束日期包含在内）
+func (s *statsService) parseRange(req *StatsRangeRequest) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if req.To != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.To, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: 结束日期格式错误", ErrInvalidStatsRange)
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.From != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.From, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: 开始日期格式错误", ErrInvalidStatsRange)
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 开始日期晚于结束日期", ErrInvalidStatsRange)
+	}
+	if maxDays := s.maxRangeDays(); !from.AddDate(0, 0, maxDays).After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: 最多查询 %d 天", ErrInvalidStatsRange, maxDays)
+	}
+	return from, to, nil
+}
+
+// cached 读取统计缓存，未命中时调用 load 填充 dest 并写入缓存
+// 缓存读写失败不影响结果，只是每次都查询数据库
+func (s *statsService) cached(key string, dest interface{}, load func() error) error {
+	if s.cache != nil && s.cache.Get(key, dest) == nil {
+		return nil
+	}
+	if err := load(); err != nil {
+		return err
+	}
+	if s.cache != nil {
+		if err := s.cache.Set(key, dest, s.cacheTTL()); err != nil {
+			log.Printf("写入统计缓存失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// cacheTTL 统计缓存有效期
+func (s *statsService) cacheTTL() time.Duration {
+	if s.config != nil && s.config.Stats.CacheTTLSeconds > 0 {
+		return time.Duration(s.config.Stats.CacheTTLSeconds) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+// maxRangeDays 可查询的最大日期范围（天）
+func (s *statsService) maxRangeDays() int {
+	if s.config != nil && s.config.Stats.MaxRangeDays > 0 {
+		return s.config.Stats.MaxRangeDays
+	}
+	return 366
+}
@@ -46,11 +46,11 @@ type userService struct {
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(userRepo repository.UserRepository, jwtService JWTService) UserService {
+func NewUserService(userRepo repository.UserRepository, jwtService JWTService, rbacService RBACService) UserService {
 	return &userService{
 		userRepo:    userRepo,
 		jwtService:  jwtService,
-		rbacService: NewRBACService(),
+		rbacService: rbacService,
 	}
 }
 
@@ -0,0 +1,295 @@
+// Package diff 提供基于 Myers 算法的文本差异比较
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// OpType 差异操作类型
+type OpType string
+
+// 差异操作类型常量
+const (
+	OpEqual  OpType = "equal"  // 相同
+	OpInsert OpType = "insert" // 新增
+	OpDelete OpType = "delete" // 删除
+)
+
+// Edit 一段差异
+type Edit struct {
+	Type OpType `json:"type"`
+	Text string `json:"text"`
+}
+
+// Words 按词比较两段文本，中日韩文字逐字比较，相邻同类操作合并
+func Words(a, b string) []Edit {
+	ops := myers(tokenize(a), tokenize(b))
+	var edits []Edit
+	for _, op := range ops {
+		if n := len(edits); n > 0 && edits[n-1].Type == op.typ {
+			edits[n-1].Text += op.text
+			continue
+		}
+		edits = append(edits, Edit{Type: op.typ, Text: op.text})
+	}
+	return edits
+}
+
+// Unified 按行比较两段文本，输出统一格式（unified diff），context 为上下文行数
+// 两段文本相同时返回空字符串
+func Unified(a, b, fromName, toName string, context int) string {
+	ops := myers(splitLines(a), splitLines(b))
+
+	// 找出所有变更位置，按上下文合并为区块
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.typ == OpEqual {
+			continue
+		}
+		start, end := max(i-context, 0), min(i+context+1, len(ops))
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// 预先计算每个操作对应的原文和新文行号
+	aLine, bLine := make([]int, len(ops)), make([]int, len(ops))
+	x, y := 1, 1
+	for i, op := range ops {
+		aLine[i], bLine[i] = x, y
+		if op.typ != OpInsert {
+			x++
+		}
+		if op.typ != OpDelete {
+			y++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks {
+		var aCount, bCount int
+		var body strings.Builder
+		for _, op := range ops[h[0]:h[1]] {
+			switch op.typ {
+			case OpEqual:
+				aCount++
+				bCount++
+				body.WriteString(" ")
+			case OpDelete:
+				aCount++
+				body.WriteString("-")
+			case OpInsert:
+				bCount++
+				body.WriteString("+")
+			}
+			body.WriteString(strings.TrimSuffix(op.text, "\n"))
+			body.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[h[0]], aCount), hunkRange(bLine[h[0]], bCount))
+		sb.WriteString(body.String())
+	}
+	return sb.String()
+}
+
+// hunkRange 格式化区块行号范围，空范围按惯例从前一行开始
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// op 单个 token 的差异操作
+type op struct {
+	typ  OpType
+	text string
+}
+
+// maxEditDistance 编辑距离上限，超过后不再寻找最短脚本，直接整体替换以限制内存和耗时
+const maxEditDistance = 2000
+
+// myers 计算两个序列的编辑脚本：先去掉公共前后缀，再用 Myers O(ND) 算法比较中间部分
+func myers(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, t := range a[:prefix] {
+		ops = append(ops, op{OpEqual, t})
+	}
+	ops = append(ops, shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, t := range a[len(a)-suffix:] {
+		ops = append(ops, op{OpEqual, t})
+	}
+	return ops
+}
+
+// shortestEdit Myers 算法主体，trace 只保存每一步 [-d, d] 范围内的前沿
+func shortestEdit(a, b []string) []op {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceAll(a, b)
+	}
+
+	maxD := min(n+m, maxEditDistance)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // 向下：插入
+			} else {
+				x = v[offset+k-1] + 1 // 向右：删除
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				return backtrack(trace, a, b)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+	return replaceAll(a, b)
+}
+
+// backtrack 根据每一步的前沿回溯出编辑脚本，trace[d][k+d] 为第 d 步对角线 k 的最远 x
+func backtrack(trace [][]int, a, b []string) []op {
+	x, y := len(a), len(b)
+	var ops []op
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{OpEqual, a[x]})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, op{OpInsert, b[y]})
+		} else {
+			x--
+			ops = append(ops, op{OpDelete, a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, op{OpEqual, a[x]})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// replaceAll 整体删除原序列并插入新序列
+func replaceAll(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+	for _, t := range a {
+		ops = append(ops, op{OpDelete, t})
+	}
+	for _, t := range b {
+		ops = append(ops, op{OpInsert, t})
+	}
+	return ops
+}
+
+// splitLines 按行切分，保留换行符以便还原
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tokenize 切分为词、空白、标点；中日韩文字每个字单独成词
+func tokenize(s string) []string {
+	var tokens []string
+	var cur []rune
+	curKind := 0
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range s {
+		kind := tokenKind(r)
+		if kind == kindSingle {
+			flush()
+			tokens = append(tokens, string(r))
+			curKind = 0
+			continue
+		}
+		if kind != curKind {
+			flush()
+			curKind = kind
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return tokens
+}
+
+// 词法分类
+const (
+	kindWord   = iota + 1 // 字母数字
+	kindSpace             // 空白
+	kindSingle            // 单独成词（中日韩文字、标点）
+)
+
+// tokenKind 判断字符所属的词法分类
+func tokenKind(r rune) int {
+	switch {
+	case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+		return kindSingle
+	case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_':
+		return kindWord
+	case unicode.IsSpace(r):
+		return kindSpace
+	default:
+		return kindSingle
+	}
+}
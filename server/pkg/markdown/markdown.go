@@ -0,0 +1,219 @@
+// Package markdown 提供文章 Markdown 到安全 HTML 的渲染
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version 渲染器版本号
+// 修改渲染规则（扩展、清洗策略、输出结构）时递增，已保存文章可据此判断是否需要重新渲染
+const Version = 3
+
+// HighlightStyle 代码高亮使用的 chroma 主题，输出为 CSS 类名，由前端主题提供样式
+const HighlightStyle = "github"
+
+// Result 渲染结果
+type Result struct {
+	HTML  string     // 清洗后的HTML
+	Text  string     // 纯文本（不含代码块），用于生成摘要
+	TOC   []*TOCItem // 目录树
+	Stats Stats      // 字数统计
+}
+
+// TOCItem 目录节点
+type TOCItem struct {
+	Level    int        `json:"level"`              // 标题级别 1-6
+	Text     string     `json:"text"`               // 标题文本
+	ID       string     `json:"id"`                 // 锚点ID
+	Children []*TOCItem `json:"children,omitempty"` // 子标题
+}
+
+var (
+	engine = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,      // 表格、任务列表、删除线、自动链接
+			extension.Footnote, // 脚注
+			highlighting.NewHighlighting(
+				highlighting.WithStyle(HighlightStyle),
+				highlighting.WithFormatOptions(
+					chromahtml.WithClasses(true), // 输出类名而不是内联样式
+				),
+			),
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+		goldmark.WithRendererOptions(
+			// 允许文中嵌入原始HTML，统一交给 bluemonday 清洗
+			html.WithUnsafe(),
+		),
+	)
+	policy = newPolicy()
+)
+
+// Render 将 Markdown 渲染为清洗后的 HTML
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := engine.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := engine.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, fmt.Errorf("渲染Markdown失败: %w", err)
+	}
+
+	return &Result{
+		HTML:  policy.Sanitize(buf.String()),
+		Text:  plainText(doc, src),
+		TOC:   buildTOC(doc, src),
+		Stats: countStats(doc, src),
+	}, nil
+}
+
+// PlainText 提取 Markdown 中的纯文本（不含代码块和原始HTML），不做HTML渲染
+func PlainText(source string) string {
+	src := []byte(source)
+	doc := engine.Parser().Parse(text.NewReader(src))
+	return plainText(doc, src)
+}
+
+// newPolicy 创建HTML清洗策略：在UGC策略基础上放行标题锚点、脚注、代码高亮和任务列表复选框
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	// 脚注引用与回链
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^fn(ref)?(-\d+)?:\d+$`)).OnElements("sup", "li")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(footnote-ref|footnote-backref|footnotes)$`)).OnElements("a", "div")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(noteref|backlink|endnotes)$`)).OnElements("a", "div")
+	// 代码高亮使用 chroma 类名
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[\w\s-]+$`)).OnElements("pre", "span")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("style").OnElements("th", "td")
+	p.AllowStyles("text-align").MatchingEnum("left", "center", "right").OnElements("th", "td")
+	return p
+}
+
+// plainText 提取文档中的纯文本，跳过代码块和原始HTML
+func plainText(doc ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering {
+				sb.Write(node.Segment.Value(src))
+				if node.SoftLineBreak() || node.HardLineBreak() {
+					sb.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering {
+				sb.Write(node.Value)
+			}
+		default:
+			// 块级元素之间用空格分隔
+			if !entering && n.Type() == ast.TypeBlock {
+				sb.WriteByte(' ')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// buildTOC 按标题层级构建目录树，跳级的标题挂到最近的上级标题下
+func buildTOC(doc ast.Node, src []byte) []*TOCItem {
+	var roots []*TOCItem
+	var stack []*TOCItem
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		item := &TOCItem{Level: heading.Level, Text: plainText(heading, src)}
+		if id, found := heading.AttributeString("id"); found {
+			if b, ok := id.([]byte); ok {
+				item.ID = string(b)
+			}
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+		return ast.WalkSkipChildren, nil
+	})
+	return roots
+}
+
+// headingIDs 生成标题锚点ID，保留中文等非ASCII字符，重复时追加序号
+type headingIDs struct {
+	used map[string]bool
+}
+
+// newHeadingIDs 创建单篇文档使用的锚点ID生成器
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]bool)}
+}
+
+// Generate 根据标题文本生成唯一ID
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := slugifyHeading(string(value))
+	if base == "" {
+		base = "heading"
+	}
+	id := base
+	for i := 1; h.used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+// Put 登记手动指定的ID
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// slugifyHeading 标题转锚点：小写，保留字母数字（含中日韩文字），空白转为连字符
+func slugifyHeading(s string) string {
+	var sb strings.Builder
+	lastDash := true
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_':
+			sb.WriteRune(r)
+			lastDash = false
+		case unicode.IsSpace(r) || r == '-':
+			if !lastDash {
+				sb.WriteByte('-')
+				lastDash = true
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+)
+
+// 阅读速度
+const (
+	CJKCharsPerMinute   = 300 // 中日韩文字：每分钟字数
+	LatinWordsPerMinute = 200 // 拉丁文字：每分钟单词数
+	CodeLinesPerMinute  = 60  // 代码块：每分钟行数
+)
+
+// Stats 正文统计结果
+type Stats struct {
+	CJKChars   uint `json:"cjkChars"`   // 中日韩文字数（逐字计数）
+	LatinWords uint `json:"latinWords"` // 拉丁文字单词数（按空白和标点分词）
+	CodeLines  uint `json:"codeLines"`  // 代码块行数
+}
+
+// WordCount 字数：中日韩文字数 + 拉丁单词数，不含代码
+func (s Stats) WordCount() uint {
+	return s.CJKChars + s.LatinWords
+}
+
+// ReadingMinutes 按不同阅读速度估算阅读时间（分钟，向上取整，最少1分钟）
+func (s Stats) ReadingMinutes() uint {
+	seconds := float64(s.CJKChars)*60/CJKCharsPerMinute +
+		float64(s.LatinWords)*60/LatinWordsPerMinute +
+		float64(s.CodeLines)*60/CodeLinesPerMinute
+	minutes := uint((seconds + 59) / 60)
+	if minutes == 0 {
+		return 1
+	}
+	return minutes
+}
+
+// countStats 统计文档正文，跳过代码块、原始HTML、图片和自动链接，Markdown 标记本身不计入
+func countStats(doc ast.Node, src []byte) Stats {
+	var stats Stats
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			stats.CodeLines += uint(countCodeLines(node, src))
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock, *ast.RawHTML, *ast.Image, *ast.AutoLink:
+			return ast.WalkSkipChildren, nil
+		case *east.TaskCheckBox:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			countText(string(node.Segment.Value(src)), &stats)
+		case *ast.String:
+			countText(string(node.Value), &stats)
+		}
+		return ast.WalkContinue, nil
+	})
+	return stats
+}
+
+// countCodeLines 统计代码块的非空行数
+func countCodeLines(n ast.Node, src []byte) int {
+	count := 0
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		if strings.TrimSpace(string(line.Value(src))) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// countText 统计一段文本：中日韩文字逐字计数，其余按单词计数，URL 不计入
+func countText(text string, stats *Stats) {
+	for _, field := range strings.Fields(text) {
+		if isURL(field) {
+			continue
+		}
+		inWord := false
+		for _, r := range field {
+			switch {
+			case isCJK(r):
+				stats.CJKChars++
+				inWord = false
+			case unicode.IsLetter(r) || unicode.IsNumber(r):
+				if !inWord {
+					stats.LatinWords++
+					inWord = true
+				}
+			case inWord && (r == '\'' || r == '’' || r == '-' || r == '_'):
+				// 单词内部的撇号、连字符不拆分单词
+			default:
+				inWord = false
+			}
+		}
+	}
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// isURL 判断文本是否为链接地址
+func isURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "www.") ||
+		strings.HasPrefix(lower, "mailto:")
+}
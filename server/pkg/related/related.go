@@ -0,0 +1,278 @@
+// Package related 计算文章之间的相关度
+//
+// 相关度由三部分加权组成，每部分都归一化到 [0, 1]：
+//   - 共同标签：按标签稀有度（IDF）加权的余弦相似度，冷门标签相同比热门标签相同更能说明相关
+//   - 共同分类：分类集合的 Jaccard 系数
+//   - 文本：标题和摘要的 TF-IDF 余弦相似度，标题中的词权重加倍
+//
+// 计算时按标签、分类和词建立倒排表，只比较至少有一项共同特征的文章，避免两两比较全部文章。
+package related
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"MyBlog/pkg/search"
+)
+
+// titleTermBoost 标题中的词相对摘要的词频倍数
+const titleTermBoost = 2
+
+// maxTermDocRatio 出现在超过该比例文章中的词视为常用词，不参与文本相似度
+const maxTermDocRatio = 0.5
+
+// Item 参与计算的文章
+type Item struct {
+	ID         uint
+	Title      string
+	Summary    string
+	Tags       []uint
+	Categories []uint // 主分类和附加分类
+}
+
+// Weights 各部分的权重，为 0 时该部分不参与计算
+type Weights struct {
+	Tag      float64
+	Category float64
+	Text     float64
+}
+
+// Match 相关文章及其相关度
+type Match struct {
+	ID    uint    `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// posting 倒排表项：文章下标及其在该特征上的权重
+type posting struct {
+	doc    int
+	weight float64
+}
+
+// Compute 计算每篇文章最相关的至多 limit 篇文章，按相关度降序，相关度相同时ID大（较新）的在前
+// 没有任何共同特征的文章不会出现在结果中
+func Compute(items []Item, weights Weights, limit int) map[uint][]Match {
+	result := make(map[uint][]Match, len(items))
+	if len(items) == 0 || limit <= 0 {
+		return result
+	}
+
+	c := newCorpus(items)
+	scores := make(map[int]float64)
+	for i, item := range items {
+		result[item.ID] = c.matches(i, weights, limit, scores)
+	}
+	return result
+}
+
+// ComputeFor 只计算指定文章的相关文章，规则与 Compute 相同
+// 标签和词的权重依赖全部文章，items 仍须包含参与计算的全部文章；文章不在 items 中时返回 nil
+func ComputeFor(items []Item, id uint, weights Weights, limit int) []Match {
+	if limit <= 0 {
+		return nil
+	}
+	for i, item := range items {
+		if item.ID == id {
+			return newCorpus(items).matches(i, weights, limit, make(map[int]float64))
+		}
+	}
+	return nil
+}
+
+// corpus 全部文章的特征向量及倒排表
+type corpus struct {
+	items            []Item
+	tagVectors       []map[string]float64
+	tagPostings      map[string][]posting
+	categorySets     []map[uint]bool
+	categoryPostings map[uint][]posting
+	termVectors      []map[string]float64
+	termPostings     map[string][]posting
+}
+
+// newCorpus 构建全部文章的特征向量及倒排表
+func newCorpus(items []Item) *corpus {
+	c := &corpus{items: items}
+	c.tagVectors, c.tagPostings = tagIndex(items)
+	c.categorySets, c.categoryPostings = categoryIndex(items)
+	c.termVectors, c.termPostings = termIndex(items)
+	return c
+}
+
+// matches 计算第 i 篇文章最相关的至多 limit 篇文章，scores 为可复用的临时空间
+func (c *corpus) matches(i int, weights Weights, limit int, scores map[int]float64) []Match {
+	for k := range scores {
+		delete(scores, k)
+	}
+
+	// 向量均已归一化，累加共同特征的权重乘积即为余弦相似度
+	if weights.Tag > 0 {
+		accumulate(scores, c.tagVectors[i], c.tagPostings, weights.Tag)
+	}
+	if weights.Text > 0 {
+		accumulate(scores, c.termVectors[i], c.termPostings, weights.Text)
+	}
+	if weights.Category > 0 {
+		shared := make(map[int]int)
+		for category := range c.categorySets[i] {
+			for _, p := range c.categoryPostings[category] {
+				shared[p.doc]++
+			}
+		}
+		for doc, n := range shared {
+			union := len(c.categorySets[i]) + len(c.categorySets[doc]) - n
+			scores[doc] += weights.Category * float64(n) / float64(union)
+		}
+	}
+	delete(scores, i)
+
+	matches := make([]Match, 0, len(scores))
+	for doc, score := range scores {
+		if score > 0 {
+			matches = append(matches, Match{ID: c.items[doc].ID, Score: score})
+		}
+	}
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].ID > matches[b].ID
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// accumulate 将文章向量与倒排表中其他文章的向量做点积，结果乘以权重累加到 scores
+func accumulate(scores map[int]float64, vector map[string]float64, postings map[string][]posting, weight float64) {
+	for key, w := range vector {
+		for _, p := range postings[key] {
+			scores[p.doc] += weight * w * p.weight
+		}
+	}
+}
+
+// tagIndex 构建按标签 IDF 加权并归一化的标签向量及倒排表
+func tagIndex(items []Item) ([]map[string]float64, map[string][]posting) {
+	docs := make([]map[string]bool, len(items))
+	for i, item := range items {
+		docs[i] = make(map[string]bool, len(item.Tags))
+		for _, tag := range item.Tags {
+			docs[i][idKey(tag)] = true
+		}
+	}
+
+	df := documentFrequency(docs)
+	vectors := make([]map[string]float64, len(items))
+	for i, doc := range docs {
+		vector := make(map[string]float64, len(doc))
+		for key := range doc {
+			vector[key] = idf(len(items), df[key])
+		}
+		vectors[i] = normalize(vector)
+	}
+	return vectors, invert(vectors)
+}
+
+// categoryIndex 构建分类集合及倒排表
+func categoryIndex(items []Item) ([]map[uint]bool, map[uint][]posting) {
+	sets := make([]map[uint]bool, len(items))
+	postings := make(map[uint][]posting)
+	for i, item := range items {
+		sets[i] = make(map[uint]bool, len(item.Categories))
+		for _, category := range item.Categories {
+			if sets[i][category] {
+				continue
+			}
+			sets[i][category] = true
+			postings[category] = append(postings[category], posting{doc: i, weight: 1})
+		}
+	}
+	return sets, postings
+}
+
+// termIndex 构建标题和摘要的 TF-IDF 向量（已归一化）及倒排表，忽略常用词
+func termIndex(items []Item) ([]map[string]float64, map[string][]posting) {
+	tfs := make([]map[string]float64, len(items))
+	docs := make([]map[string]bool, len(items))
+	for i, item := range items {
+		tf := make(map[string]float64)
+		for _, token := range search.Tokenize(item.Title) {
+			tf[token.Text] += titleTermBoost
+		}
+		for _, token := range search.Tokenize(item.Summary) {
+			tf[token.Text]++
+		}
+		tfs[i] = tf
+		docs[i] = make(map[string]bool, len(tf))
+		for term := range tf {
+			docs[i][term] = true
+		}
+	}
+
+	df := documentFrequency(docs)
+	maxDF := int(math.Max(2, maxTermDocRatio*float64(len(items))))
+	vectors := make([]map[string]float64, len(items))
+	for i, tf := range tfs {
+		vector := make(map[string]float64, len(tf))
+		for term, count := range tf {
+			// 只出现在本文中的词不会带来相似度，常用词区分度低
+			if df[term] < 2 || df[term] > maxDF {
+				continue
+			}
+			vector[term] = (1 + math.Log(count)) * idf(len(items), df[term])
+		}
+		vectors[i] = normalize(vector)
+	}
+	return vectors, invert(vectors)
+}
+
+// documentFrequency 统计每个特征出现在多少篇文章中
+func documentFrequency(docs []map[string]bool) map[string]int {
+	df := make(map[string]int)
+	for _, doc := range docs {
+		for key := range doc {
+			df[key]++
+		}
+	}
+	return df
+}
+
+// idf 平滑的逆文档频率，出现在全部文章中的特征权重仍大于 0
+func idf(n, df int) float64 {
+	return math.Log(1 + float64(n)/float64(df))
+}
+
+// normalize 将向量归一化为单位长度
+func normalize(vector map[string]float64) map[string]float64 {
+	var sum float64
+	for _, w := range vector {
+		sum += w * w
+	}
+	if sum == 0 {
+		return vector
+	}
+	norm := math.Sqrt(sum)
+	for key, w := range vector {
+		vector[key] = w / norm
+	}
+	return vector
+}
+
+// invert 由文章向量构建倒排表
+func invert(vectors []map[string]float64) map[string][]posting {
+	postings := make(map[string][]posting)
+	for i, vector := range vectors {
+		for key, w := range vector {
+			postings[key] = append(postings[key], posting{doc: i, weight: w})
+		}
+	}
+	return postings
+}
+
+// idKey 将ID转换为向量的键
+func idKey(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -22,6 +22,7 @@ const (
 	CodeAuth     = 401 // 认证失败
 	CodeForbid   = 403 // 权限不足
 	CodeNotFound = 404 // 资源不存在
+	CodeConflict = 409 // 资源已被修改（版本冲突）
 )
 
 // Success 成功响应
@@ -74,3 +75,12 @@ func Unauthorized(c *gin.Context, message string) {
 func Forbidden(c *gin.Context, message string) {
 	Error(c, CodeForbid, message)
 }
+
+// Conflict 版本冲突，data 携带服务端当前状态供客户端合并
+func Conflict(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    CodeConflict,
+		Message: message,
+		Data:    data,
+	})
+}
@@ -0,0 +1,116 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 高亮标记
+const (
+	MarkOpen  = "<mark>"
+	MarkClose = "</mark>"
+	ellipsis  = "…"
+)
+
+// Highlight 对整段文本高亮匹配的词，返回转义后的HTML；没有匹配时返回空字符串
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	ranges := findMatches(runes, terms)
+	if len(ranges) == 0 {
+		return ""
+	}
+	return render(runes, ranges, 0, len(runes))
+}
+
+// Snippet 截取第一个匹配附近不超过 maxRunes 个字符的片段并高亮，返回转义后的HTML
+// 没有匹配时返回空字符串
+func Snippet(text string, terms []string, maxRunes int) string {
+	runes := []rune(text)
+	ranges := findMatches(runes, terms)
+	if len(ranges) == 0 {
+		return ""
+	}
+
+	// 匹配位置前留出约四分之一的上下文
+	start := max(ranges[0][0]-maxRunes/4, 0)
+	end := min(start+maxRunes, len(runes))
+	start = max(end-maxRunes, 0)
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString(ellipsis)
+	}
+	sb.WriteString(render(runes, ranges, start, end))
+	if end < len(runes) {
+		sb.WriteString(ellipsis)
+	}
+	return sb.String()
+}
+
+// findMatches 查找所有词的出现位置（忽略大小写），返回按起点排序并合并重叠后的区间
+func findMatches(text []rune, terms []string) [][2]int {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var ranges [][2]int
+	for _, term := range terms {
+		needle := []rune(strings.ToLower(term))
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if equalRunes(lower[i:i+len(needle)], needle) {
+				ranges = append(ranges, [2]int{i, i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// render 输出 [start, end) 范围内的文本，匹配区间包裹高亮标记，其余内容转义
+func render(text []rune, ranges [][2]int, start, end int) string {
+	var sb strings.Builder
+	pos := start
+	for _, r := range ranges {
+		from, to := max(r[0], start), min(r[1], end)
+		if from >= to {
+			continue
+		}
+		sb.WriteString(html.EscapeString(string(text[pos:from])))
+		sb.WriteString(MarkOpen)
+		sb.WriteString(html.EscapeString(string(text[from:to])))
+		sb.WriteString(MarkClose)
+		pos = to
+	}
+	sb.WriteString(html.EscapeString(string(text[pos:end])))
+	return sb.String()
+}
+
+// equalRunes 比较两个字符切片是否相同
+func equalRunes(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		terms []string
+		want  string
+	}{
+		{name: "忽略大小写并保留原文大小写", text: "Go and GO", terms: []string{"go"}, want: "<mark>Go</mark> and <mark>GO</mark>"},
+		{name: "中文匹配", text: "全文搜索引擎", terms: []string{"搜索"}, want: "全文<mark>搜索</mark>引擎"},
+		{name: "重叠的匹配合并", text: "abcd", terms: []string{"abc", "bcd"}, want: "<mark>abcd</mark>"},
+		{name: "匹配外的文本转义", text: `<script>alert("go")</script>`, terms: []string{"alert"}, want: "&lt;script&gt;<mark>alert</mark>(&#34;go&#34;)&lt;/script&gt;"},
+		{name: "匹配内的文本转义", text: "a<b>c", terms: []string{"<b>"}, want: "a<mark>&lt;b&gt;</mark>c"},
+		{name: "没有匹配返回空", text: "hello", terms: []string{"world"}, want: ""},
+		{name: "空词忽略", text: "hello", terms: []string{""}, want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Highlight(tc.text, tc.terms); got != tc.want {
+				t.Fatalf("Highlight(%q, %q) = %q, want %q", tc.text, tc.terms, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		terms    []string
+		maxRunes int
+		want     string
+	}{
+		{name: "短文本不加省略号", text: "hello world", terms: []string{"world"}, maxRunes: 20, want: "hello <mark>world</mark>"},
+		{name: "匹配前保留约四分之一上下文", text: "0123456789abcdefghij", terms: []string{"a"}, maxRunes: 8, want: "…89<mark>a</mark>bcdef…"},
+		{name: "匹配靠近结尾时向前取满长度", text: "0123456789", terms: []string{"9"}, maxRunes: 4, want: "…678<mark>9</mark>"},
+		{name: "截断的片段同样转义", text: "<<<<target>>>>", terms: []string{"target"}, maxRunes: 8, want: "…&lt;&lt;<mark>target</mark>…"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Snippet(tc.text, tc.terms, tc.maxRunes); got != tc.want {
+				t.Fatalf("Snippet(%q) = %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Document 待索引的文档
+type Document struct {
+	ID     uint                `json:"id"`
+	Fields map[string]string   `json:"fields"` // 字段名 -> 文本
+	Attrs  map[string][]string `json:"attrs"`  // 用于过滤的属性，如 status、tag
+}
+
+// Hit 搜索命中
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// SearchOptions 搜索选项
+type SearchOptions struct {
+	Fields  map[string]float64  // 参与匹配的字段及其权重，未列出的字段不参与匹配
+	Filters map[string][]string // 属性过滤：文档的每个属性须包含列出的任一值
+	Limit   int                 // 最多返回的命中数，<=0 表示不限制
+}
+
+// docEntry 已索引文档的元数据
+type docEntry struct {
+	Lengths map[string]int      // 各字段词数
+	Terms   map[string][]string // 各字段包含的词，删除文档时用于清理倒排表
+	Attrs   map[string][]string
+}
+
+// indexData 倒排索引数据，整体序列化为快照
+type indexData struct {
+	Docs     map[uint]*docEntry
+	Postings map[string]map[string]map[uint][]int // 字段 -> 词 -> 文档 -> 出现位置
+	Totals   map[string]int                       // 各字段总词数，用于计算平均长度
+}
+
+// Index 嵌入式倒排索引
+// 所有数据常驻内存，变更先追加到磁盘日志，日志累积到一定数量后合并为快照
+type Index struct {
+	mu    sync.RWMutex
+	data  *indexData
+	store *store
+}
+
+// Open 打开（或创建）指定目录下的索引
+func Open(dir string) (*Index, error) {
+	st, data, err := openStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Index{data: data, store: st}, nil
+}
+
+// Len 已索引的文档数
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.data.Docs)
+}
+
+// Put 新增或替换文档
+func (idx *Index) Put(doc Document) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.data.remove(doc.ID)
+	idx.data.add(doc)
+	return idx.store.append(logRecord{Op: opPut, ID: doc.ID, Doc: &doc}, idx.data)
+}
+
+// Delete 删除文档，文档不存在时不做任何操作
+func (idx *Index) Delete(id uint) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.data.Docs[id]; !ok {
+		return nil
+	}
+	idx.data.remove(id)
+	return idx.store.append(logRecord{Op: opDelete, ID: id}, idx.data)
+}
+
+// Rebuild 用给定的全部文档重建索引并立即写入快照
+// 新索引构建完成前，搜索仍使用旧数据
+func (idx *Index) Rebuild(docs []Document) error {
+	data := newIndexData()
+	for _, doc := range docs {
+		data.add(doc)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.data = data
+	return idx.store.snapshot(data)
+}
+
+// Close 写入快照并关闭索引
+func (idx *Index) Close() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.store.close(idx.data)
+}
+
+// Search 搜索文档，返回按相关度降序排列的命中和命中总数
+// 所有非排除词都必须出现（在任一参与匹配的字段中），包含排除词的文档被过滤
+func (idx *Index) Search(q Query, opts SearchOptions) ([]Hit, int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	d := idx.data
+
+	var scores map[uint]float64
+	for _, term := range q.Positive() {
+		tokens := tokenTexts(term.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		termScores := make(map[uint]float64)
+		for field, weight := range opts.Fields {
+			d.score(field, weight, d.match(field, tokens, term.Prefix), termScores)
+		}
+		if scores == nil {
+			scores = termScores
+		} else {
+			for id, score := range scores {
+				if s, ok := termScores[id]; ok {
+					scores[id] = score + s
+				} else {
+					delete(scores, id)
+				}
+			}
+		}
+		if len(scores) == 0 {
+			return nil, 0
+		}
+	}
+	if len(scores) == 0 {
+		return nil, 0
+	}
+
+	for _, term := range q.Terms {
+		if !term.Exclude {
+			continue
+		}
+		tokens := tokenTexts(term.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		for field := range opts.Fields {
+			for id := range d.match(field, tokens, term.Prefix) {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		if d.Docs[id].matches(opts.Filters) {
+			hits = append(hits, Hit{ID: id, Score: score})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	total := len(hits)
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits, total
+}
+
+// newIndexData 创建空索引数据
+func newIndexData() *indexData {
+	return &indexData{
+		Docs:     make(map[uint]*docEntry),
+		Postings: make(map[string]map[string]map[uint][]int),
+		Totals:   make(map[string]int),
+	}
+}
+
+// add 将文档加入倒排表，调用前须确保文档不在索引中
+func (d *indexData) add(doc Document) {
+	entry := &docEntry{
+		Lengths: make(map[string]int, len(doc.Fields)),
+		Terms:   make(map[string][]string, len(doc.Fields)),
+		Attrs:   doc.Attrs,
+	}
+	for field, text := range doc.Fields {
+		tokens := Tokenize(text)
+		if len(tokens) == 0 {
+			continue
+		}
+		postings := d.Postings[field]
+		if postings == nil {
+			postings = make(map[string]map[uint][]int)
+			d.Postings[field] = postings
+		}
+		for _, token := range tokens {
+			docs := postings[token.Text]
+			if docs == nil {
+				docs = make(map[uint][]int)
+				postings[token.Text] = docs
+			}
+			if _, seen := docs[doc.ID]; !seen {
+				entry.Terms[field] = append(entry.Terms[field], token.Text)
+			}
+			docs[doc.ID] = append(docs[doc.ID], token.Pos)
+		}
+		entry.Lengths[field] = len(tokens)
+		d.Totals[field] += len(tokens)
+	}
+	d.Docs[doc.ID] = entry
+}
+
+// remove 从倒排表中删除文档
+func (d *indexData) remove(id uint) {
+	entry, ok := d.Docs[id]
+	if !ok {
+		return
+	}
+	for field, terms := range entry.Terms {
+		postings := d.Postings[field]
+		for _, term := range terms {
+			delete(postings[term], id)
+			if len(postings[term]) == 0 {
+				delete(postings, term)
+			}
+		}
+		d.Totals[field] -= entry.Lengths[field]
+	}
+	delete(d.Docs, id)
+}
+
+// match 查找字段中连续出现给定词序列的文档，返回每个文档的出现次数
+// prefix 为 true 时最后一个词按前缀匹配
+func (d *indexData) match(field string, tokens []string, prefix bool) map[uint]int {
+	postings := d.Postings[field]
+	if len(postings) == 0 {
+		return nil
+	}
+
+	// 每个位置上可接受的索引词
+	expansions := make([][]string, len(tokens))
+	for i, token := range tokens {
+		expansions[i] = expand(postings, token, i, len(tokens), prefix && i == len(tokens)-1)
+		if len(expansions[i]) == 0 {
+			return nil
+		}
+	}
+
+	// 首词的出现位置
+	starts := make(map[uint][]int)
+	for _, term := range expansions[0] {
+		for id, positions := range postings[term] {
+			starts[id] = append(starts[id], positions...)
+		}
+	}
+	if len(tokens) == 1 {
+		counts := make(map[uint]int, len(starts))
+		for id, positions := range starts {
+			counts[id] = len(positions)
+		}
+		return counts
+	}
+
+	// 其余词的出现位置，用于检查是否紧跟在前一个词之后
+	following := make([]map[uint]map[int]struct{}, len(tokens))
+	for i := 1; i < len(tokens); i++ {
+		following[i] = make(map[uint]map[int]struct{})
+		for _, term := range expansions[i] {
+			for id, positions := range postings[term] {
+				if _, ok := starts[id]; !ok {
+					continue
+				}
+				set := following[i][id]
+				if set == nil {
+					set = make(map[int]struct{})
+					following[i][id] = set
+				}
+				for _, pos := range positions {
+					set[pos] = struct{}{}
+				}
+			}
+		}
+	}
+
+	counts := make(map[uint]int)
+	for id, positions := range starts {
+		for _, start := range positions {
+			matched := true
+			for i := 1; i < len(tokens); i++ {
+				if _, ok := following[i][id][start+i]; !ok {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				counts[id]++
+			}
+		}
+	}
+	return counts
+}
+
+// score 按 BM25 计算字段命中得分并累加到 scores
+func (d *indexData) score(field string, weight float64, counts map[uint]int, scores map[uint]float64) {
+	if len(counts) == 0 || weight <= 0 {
+		return
+	}
+	n := float64(len(d.Docs))
+	df := float64(len(counts))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+	avg := float64(d.Totals[field]) / n
+	if avg <= 0 {
+		avg = 1
+	}
+
+	for id, count := range counts {
+		tf := float64(count)
+		length := float64(d.Docs[id].Lengths[field])
+		scores[id] += weight * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*length/avg))
+	}
+}
+
+// matches 判断文档属性是否满足过滤条件
+func (e *docEntry) matches(filters map[string][]string) bool {
+	for name, wanted := range filters {
+		if len(wanted) == 0 {
+			continue
+		}
+		found := false
+		for _, value := range e.Attrs[name] {
+			for _, w := range wanted {
+				if value == w {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// expand 返回查询词在倒排表中对应的索引词
+// 单个汉字在 bigram 切分下可能是某个词的首字或尾字：
+// 位于短语开头时须为词的尾字，位于短语末尾时须为词的首字，单独出现时两者皆可
+func expand(postings map[string]map[uint][]int, token string, i, n int, prefix bool) []string {
+	single := isSingleCJK(token)
+	if !prefix && !single {
+		if _, ok := postings[token]; ok {
+			return []string{token}
+		}
+		return nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(token)
+	var terms []string
+	for term := range postings {
+		if term == token || (prefix && strings.HasPrefix(term, token)) {
+			terms = append(terms, term)
+			continue
+		}
+		if !single || utf8.RuneCountInString(term) != 2 {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(term)
+		last, _ := utf8.DecodeLastRuneInString(term)
+		switch {
+		case n == 1:
+			if first == r || last == r {
+				terms = append(terms, term)
+			}
+		case i == 0:
+			if last == r {
+				terms = append(terms, term)
+			}
+		case i == n-1:
+			if first == r {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// tokenTexts 对查询词分词，只保留词文本
+func tokenTexts(text string) []string {
+	tokens := Tokenize(text)
+	texts := make([]string, len(tokens))
+	for i, t := range tokens {
+		texts[i] = t.Text
+	}
+	return texts
+}
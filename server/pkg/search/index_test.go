@@ -0,0 +1,120 @@
+package search
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// newTestIndex 在临时目录中创建索引并写入文档
+func newTestIndex(t *testing.T, docs ...Document) *Index {
+	t.Helper()
+	idx, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("打开索引失败: %v", err)
+	}
+	t.Cleanup(func() { idx.Close() })
+	for _, doc := range docs {
+		if err := idx.Put(doc); err != nil {
+			t.Fatalf("写入文档失败: %v", err)
+		}
+	}
+	return idx
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := newTestIndex(t,
+		Document{ID: 1, Fields: map[string]string{"title": "全文搜索引擎入门"}, Attrs: map[string][]string{"status": {"published"}}},
+		Document{ID: 2, Fields: map[string]string{"title": "搜索建议与引擎优化"}, Attrs: map[string][]string{"status": {"published"}}},
+		Document{ID: 3, Fields: map[string]string{"title": "Go语言搜索实践", "content": "使用 golang 实现倒排索引"}, Attrs: map[string][]string{"status": {"draft"}}},
+		Document{ID: 4, Fields: map[string]string{"title": "MySQL full text search"}, Attrs: map[string][]string{"status": {"published"}}},
+	)
+
+	cases := []struct {
+		name    string
+		keyword string
+		fields  []string
+		filters map[string][]string
+		want    []uint
+	}{
+		{name: "中文按两字切分匹配", keyword: "搜索", want: []uint{1, 2, 3}},
+		{name: "多字词须连续出现", keyword: "搜索引擎", want: []uint{1}},
+		{name: "多个词须全部出现", keyword: "搜索 引擎", want: []uint{1, 2}},
+		{name: "单个汉字匹配词的首字或尾字", keyword: "擎", want: []uint{1, 2}},
+		{name: "单个汉字在短语末尾须为词的首字", keyword: "go语", want: []uint{3}},
+		{name: "英文短语须连续出现", keyword: `"full text"`, want: []uint{4}},
+		{name: "英文短语顺序不同不匹配", keyword: `"text full"`, want: nil},
+		{name: "前缀匹配", keyword: "gol*", fields: []string{"title", "content"}, want: []uint{3}},
+		{name: "未列出的字段不参与匹配", keyword: "golang", want: nil},
+		{name: "排除词", keyword: "搜索 -建议", want: []uint{1, 3}},
+		{name: "属性过滤", keyword: "搜索", filters: map[string][]string{"status": {"published"}}, want: []uint{1, 2}},
+		{name: "不区分大小写", keyword: "MYSQL", want: []uint{4}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := map[string]float64{"title": 1}
+			for _, f := range tc.fields {
+				fields[f] = 1
+			}
+			hits, total := idx.Search(ParseQuery(tc.keyword), SearchOptions{Fields: fields, Filters: tc.filters})
+			var got []uint
+			for _, hit := range hits {
+				got = append(got, hit.ID)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("搜索 %q 命中 %v, want %v", tc.keyword, got, tc.want)
+			}
+			if total != len(tc.want) {
+				t.Fatalf("搜索 %q 命中总数 %d, want %d", tc.keyword, total, len(tc.want))
+			}
+		})
+	}
+}
+
+func TestIndexPutReplacesAndDeleteRemoves(t *testing.T) {
+	idx := newTestIndex(t, Document{ID: 1, Fields: map[string]string{"title": "旧标题"}})
+	fields := map[string]float64{"title": 1}
+
+	if err := idx.Put(Document{ID: 1, Fields: map[string]string{"title": "新标题"}}); err != nil {
+		t.Fatalf("更新文档失败: %v", err)
+	}
+	if hits, _ := idx.Search(ParseQuery("旧标"), SearchOptions{Fields: fields}); len(hits) != 0 {
+		t.Fatalf("更新后不应再匹配旧内容: %v", hits)
+	}
+	if hits, _ := idx.Search(ParseQuery("新标"), SearchOptions{Fields: fields}); len(hits) != 1 {
+		t.Fatalf("更新后应匹配新内容: %v", hits)
+	}
+
+	if err := idx.Delete(1); err != nil {
+		t.Fatalf("删除文档失败: %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Fatalf("删除后文档数应为 0, got %d", idx.Len())
+	}
+}
+
+func TestIndexReopenRestoresData(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := Open(dir)
+	if err != nil {
+		t.Fatalf("打开索引失败: %v", err)
+	}
+	if err := idx.Put(Document{ID: 7, Fields: map[string]string{"title": "持久化索引"}}); err != nil {
+		t.Fatalf("写入文档失败: %v", err)
+	}
+	if err := idx.Close(); err != nil {
+		t.Fatalf("关闭索引失败: %v", err)
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatalf("重新打开索引失败: %v", err)
+	}
+	defer reopened.Close()
+	hits, _ := reopened.Search(ParseQuery("持久化"), SearchOptions{Fields: map[string]float64{"title": 1}})
+	if len(hits) != 1 || hits[0].ID != 7 {
+		t.Fatalf("重新打开后应能搜索到已写入的文档: %v", hits)
+	}
+}
@@ -0,0 +1,142 @@
+// Package search 提供搜索关键词解析和结果高亮
+package search
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MinTermLength 全文索引可匹配的最短词长（字符数），与 MySQL ngram_token_size 默认值一致
+const MinTermLength = 2
+
+// booleanOperators MySQL 布尔模式中有特殊含义的字符
+const booleanOperators = `+-<>()~*"@`
+
+// Term 搜索词
+type Term struct {
+	Text    string // 小写后的搜索词
+	Phrase  bool   // 短语：必须连续出现
+	Prefix  bool   // 前缀匹配（以 * 结尾）
+	Exclude bool   // 排除（以 - 开头）
+}
+
+// Query 解析后的搜索条件
+type Query struct {
+	Terms []Term
+}
+
+// ParseQuery 解析搜索关键词
+// 支持 "短语"、前缀*、-排除；词内的布尔运算符被视为分隔符，拆出多个词时按短语处理
+func ParseQuery(keyword string) Query {
+	var q Query
+	rest := strings.TrimSpace(keyword)
+	for rest != "" {
+		exclude := false
+		if strings.HasPrefix(rest, "-") {
+			exclude = true
+			rest = rest[1:]
+		}
+
+		var raw string
+		phrase := false
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				raw, rest = rest[1:], ""
+			} else {
+				raw, rest = rest[1:end+1], rest[end+2:]
+			}
+			phrase = true
+		} else {
+			end := strings.IndexAny(rest, " \t\r\n　")
+			if end < 0 {
+				raw, rest = rest, ""
+			} else {
+				raw, rest = rest[:end], rest[end:]
+			}
+		}
+		rest = strings.TrimSpace(rest)
+
+		prefix := !phrase && strings.HasSuffix(raw, "*")
+		words := strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool {
+			return strings.ContainsRune(booleanOperators, r) || r == ' ' || r == '　'
+		})
+		if len(words) == 0 {
+			continue
+		}
+		q.Terms = append(q.Terms, Term{
+			Text:    strings.Join(words, " "),
+			Phrase:  phrase || len(words) > 1,
+			Prefix:  prefix && len(words) == 1,
+			Exclude: exclude,
+		})
+	}
+	return q
+}
+
+// Positive 返回非排除的搜索词
+func (q Query) Positive() []Term {
+	var terms []Term
+	for _, t := range q.Terms {
+		if !t.Exclude {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// FulltextReady 是否可以使用全文索引：至少有一个搜索词，且每个搜索词都不短于 MinTermLength
+func (q Query) FulltextReady() bool {
+	positive := q.Positive()
+	if len(positive) == 0 {
+		return false
+	}
+	for _, t := range q.Terms {
+		for _, word := range strings.Fields(t.Text) {
+			if utf8.RuneCountInString(word) < MinTermLength {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// BooleanMode 生成 MySQL 布尔模式查询串，所有搜索词都必须出现
+func (q Query) BooleanMode() string {
+	parts := make([]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		op := "+"
+		if t.Exclude {
+			op = "-"
+		}
+		switch {
+		case t.Phrase:
+			parts = append(parts, op+`"`+t.Text+`"`)
+		case t.Prefix:
+			parts = append(parts, op+t.Text+"*")
+		default:
+			parts = append(parts, op+t.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Normalize 规范化关键词：转小写、合并连续空白，结果最多保留 maxRunes 个字符（<=0 不限制）
+// 用于搜索记录的统计，大小写和空白不同的同一关键词归为一类
+func Normalize(keyword string, maxRunes int) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(keyword)), " ")
+	if maxRunes > 0 && utf8.RuneCountInString(normalized) > maxRunes {
+		normalized = strings.TrimSpace(string([]rune(normalized)[:maxRunes]))
+	}
+	return normalized
+}
+
+// HighlightTerms 返回用于高亮的词（不含排除词）
+func (q Query) HighlightTerms() []string {
+	positive := q.Positive()
+	terms := make([]string, 0, len(positive))
+	for _, t := range positive {
+		terms = append(terms, t.Text)
+	}
+	return terms
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		name        string
+		in          string
+		want        []Term
+		boolean     string
+		fulltextRdy bool
+	}{
+		{
+			name:        "空格分隔的词全部必须出现",
+			in:          "Golang  MySQL",
+			want:        []Term{{Text: "golang"}, {Text: "mysql"}},
+			boolean:     "+golang +mysql",
+			fulltextRdy: true,
+		},
+		{
+			name:        "引号内为短语",
+			in:          `"全文 搜索" 索引`,
+			want:        []Term{{Text: "全文 搜索", Phrase: true}, {Text: "索引"}},
+			boolean:     `+"全文 搜索" +索引`,
+			fulltextRdy: true,
+		},
+		{
+			name:        "前缀匹配和排除词",
+			in:          "go* -java",
+			want:        []Term{{Text: "go", Prefix: true}, {Text: "java", Exclude: true}},
+			boolean:     "+go* -java",
+			fulltextRdy: true,
+		},
+		{
+			name:        "词内的运算符拆成短语",
+			in:          "full-text",
+			want:        []Term{{Text: "full text", Phrase: true}},
+			boolean:     `+"full text"`,
+			fulltextRdy: true,
+		},
+		{
+			name:        "未闭合的引号取到结尾",
+			in:          `"hello world`,
+			want:        []Term{{Text: "hello world", Phrase: true}},
+			boolean:     `+"hello world"`,
+			fulltextRdy: true,
+		},
+		{
+			name:        "单字无法使用全文索引",
+			in:          "搜 索引",
+			want:        []Term{{Text: "搜"}, {Text: "索引"}},
+			boolean:     "+搜 +索引",
+			fulltextRdy: false,
+		},
+		{
+			name:        "只有排除词无法使用全文索引",
+			in:          "-java",
+			want:        []Term{{Text: "java", Exclude: true}},
+			boolean:     "-java",
+			fulltextRdy: false,
+		},
+		{
+			name:        "只有运算符",
+			in:          `+ - ""`,
+			want:        nil,
+			boolean:     "",
+			fulltextRdy: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := ParseQuery(tc.in)
+			if !reflect.DeepEqual(q.Terms, tc.want) {
+				t.Fatalf("ParseQuery(%q).Terms = %+v, want %+v", tc.in, q.Terms, tc.want)
+			}
+			if got := q.BooleanMode(); got != tc.boolean {
+				t.Fatalf("BooleanMode() = %q, want %q", got, tc.boolean)
+			}
+			if got := q.FulltextReady(); got != tc.fulltextRdy {
+				t.Fatalf("FulltextReady() = %v, want %v", got, tc.fulltextRdy)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		in       string
+		maxRunes int
+		want     string
+	}{
+		{in: "  Go   MySQL ", want: "go mysql"},
+		{in: "全文\t搜索", want: "全文 搜索"},
+		{in: "abcdef", maxRunes: 3, want: "abc"},
+		{in: "ab cd", maxRunes: 3, want: "ab"},
+		{in: "全文搜索", maxRunes: 2, want: "全文"},
+	}
+
+	for _, tc := range cases {
+		if got := Normalize(tc.in, tc.maxRunes); got != tc.want {
+			t.Errorf("Normalize(%q, %d) = %q, want %q", tc.in, tc.maxRunes, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package search
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FormatVersion 索引文件格式版本
+// 修改分词规则或数据结构时递增，版本不一致的索引文件会被丢弃，需要重建
+const FormatVersion = 1
+
+const (
+	snapshotFile = "index.gob" // 索引快照
+	logFile      = "index.log" // 快照之后的变更日志，每行一条JSON记录
+	compactEvery = 500         // 日志累积到该条数后合并为新快照
+	opPut        = "put"       // 新增或替换文档
+	opDelete     = "delete"    // 删除文档
+)
+
+// logRecord 变更日志记录
+type logRecord struct {
+	Op  string    `json:"op"`
+	ID  uint      `json:"id"`
+	Doc *Document `json:"doc,omitempty"`
+}
+
+// snapshotData 快照文件内容
+type snapshotData struct {
+	Version int
+	Data    *indexData
+}
+
+// store 索引的磁盘存储
+type store struct {
+	dir     string
+	log     *os.File
+	pending int // 快照之后的日志条数
+}
+
+// openStore 打开索引目录，加载快照并重放日志
+func openStore(dir string) (*store, *indexData, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("创建索引目录失败: %w", err)
+	}
+
+	data, err := loadSnapshot(filepath.Join(dir, snapshotFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logPath := filepath.Join(dir, logFile)
+	var (
+		pending int
+		valid   int64
+	)
+	if data != nil {
+		if pending, valid, err = replayLog(logPath, data); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// 没有可用快照时，日志中的变更也无法还原完整索引，等待重建
+		data = newIndexData()
+		if err := os.Remove(logPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, nil, fmt.Errorf("清理索引日志失败: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开索引日志失败: %w", err)
+	}
+	// 去掉末尾不完整的记录，避免后续追加的记录与其拼接成无法解析的一行
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("截断索引日志失败: %w", err)
+	}
+	return &store{dir: dir, log: f, pending: pending}, data, nil
+}
+
+// append 追加一条变更日志，累积足够多时合并为快照
+func (s *store) append(record logRecord, data *indexData) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("序列化索引日志失败: %w", err)
+	}
+	if _, err := s.log.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入索引日志失败: %w", err)
+	}
+	s.pending++
+	if s.pending >= compactEvery {
+		return s.snapshot(data)
+	}
+	return nil
+}
+
+// snapshot 写入完整快照并清空日志
+// 先写临时文件再重命名，写入过程中崩溃不会损坏已有快照
+func (s *store) snapshot(data *indexData) error {
+	path := filepath.Join(s.dir, snapshotFile)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("创建索引快照失败: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	if err := gob.NewEncoder(w).Encode(snapshotData{Version: FormatVersion, Data: data}); err != nil {
+		f.Close()
+		return fmt.Errorf("写入索引快照失败: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("写入索引快照失败: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("写入索引快照失败: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("写入索引快照失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("替换索引快照失败: %w", err)
+	}
+
+	// 快照已包含日志中的全部变更；即使截断前崩溃，重放日志也只是重复执行幂等操作
+	if err := s.log.Truncate(0); err != nil {
+		return fmt.Errorf("清空索引日志失败: %w", err)
+	}
+	s.pending = 0
+	return nil
+}
+
+// close 有未合并的日志时写入快照，然后关闭日志文件
+func (s *store) close(data *indexData) error {
+	var err error
+	if s.pending > 0 {
+		err = s.snapshot(data)
+	}
+	if cerr := s.log.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("关闭索引日志失败: %w", cerr)
+	}
+	return err
+}
+
+// loadSnapshot 读取快照，文件不存在或格式版本不一致时返回 nil
+func loadSnapshot(path string) (*indexData, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开索引快照失败: %w", err)
+	}
+	defer f.Close()
+
+	var snap snapshotData
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(&snap); err != nil {
+		// 快照损坏时丢弃，由调用方重建索引
+		return nil, nil
+	}
+	if snap.Version != FormatVersion || snap.Data == nil {
+		return nil, nil
+	}
+
+	// gob 不编码空 map，解码后补齐
+	data := snap.Data
+	if data.Docs == nil {
+		data.Docs = make(map[uint]*docEntry)
+	}
+	if data.Postings == nil {
+		data.Postings = make(map[string]map[string]map[uint][]int)
+	}
+	if data.Totals == nil {
+		data.Totals = make(map[string]int)
+	}
+	return data, nil
+}
+
+// replayLog 将日志中的变更应用到索引数据，返回日志条数和完整记录的总字节数
+// 末尾不完整的记录（写入时崩溃）被忽略
+func replayLog(path string, data *indexData) (int, int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("打开索引日志失败: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		count int
+		valid int64
+	)
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return count, valid, nil
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("读取索引日志失败: %w", err)
+		}
+
+		var record logRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return count, valid, nil
+		}
+		data.remove(record.ID)
+		if record.Op == opPut && record.Doc != nil {
+			data.add(*record.Doc)
+		}
+		count++
+		valid += int64(len(line))
+	}
+}
@@ -0,0 +1,68 @@
+package search
+
+import "unicode"
+
+// Token 分词结果
+type Token struct {
+	Text string // 小写后的词
+	Pos  int    // 在字段中的位置（第几个词），用于短语匹配
+}
+
+// Tokenize 分词
+// 拉丁字母和数字按单词切分；中日韩文字按相邻两字切分（bigram），单独出现的一个字作为一个词
+func Tokenize(text string) []Token {
+	var (
+		tokens []Token
+		word   []rune
+		cjk    []rune
+	)
+	emit := func(s string) {
+		tokens = append(tokens, Token{Text: s, Pos: len(tokens)})
+	}
+	flushWord := func() {
+		if len(word) > 0 {
+			emit(string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		switch {
+		case len(cjk) == 1:
+			emit(string(cjk))
+		case len(cjk) > 1:
+			for i := 0; i+1 < len(cjk); i++ {
+				emit(string(cjk[i : i+2]))
+			}
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		r = unicode.ToLower(r)
+		switch {
+		case IsCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+// IsCJK 是否为中日韩文字
+func IsCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// isSingleCJK 是否为单个中日韩文字：bigram 切分下它可能出现在任意一个词的首字或尾字
+func isSingleCJK(s string) bool {
+	runes := []rune(s)
+	return len(runes) == 1 && IsCJK(runes[0])
+}
@@ -0,0 +1,38 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "英文按单词切分并转小写", in: "Hello, Go World", want: []string{"hello", "go", "world"}},
+		{name: "中文按相邻两字切分", in: "全文搜索", want: []string{"全文", "文搜", "搜索"}},
+		{name: "单独的汉字作为一个词", in: "搜", want: []string{"搜"}},
+		{name: "中英文混排", in: "Go语言", want: []string{"go", "语言"}},
+		{name: "标点打断中文", in: "你好，世界", want: []string{"你好", "世界"}},
+		{name: "日文假名按两字切分", in: "カタカナ", want: []string{"カタ", "タカ", "カナ"}},
+		{name: "数字与字母组成一个词", in: "mysql8 v1.23", want: []string{"mysql8", "v1", "23"}},
+		{name: "只有符号", in: "!!! ---", want: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for i, token := range Tokenize(tc.in) {
+				if token.Pos != i {
+					t.Fatalf("第 %d 个词的位置为 %d", i, token.Pos)
+				}
+				got = append(got, token.Text)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Tokenize(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Package slug 生成 URL 友好的标识
+//
+// 汉字转写为不带声调的拼音（每个字一个词），拉丁字母经 NFKD 分解后去掉重音符号，
+// 其他语言的字母和数字原样保留（小写），其余字符统一替换为连字符。
+package slug
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultMaxLength 默认最大长度（字符数）
+const DefaultMaxLength = 80
+
+// fallbackLength 兜底短哈希长度
+const fallbackLength = 8
+
+// Options 生成选项
+type Options struct {
+	MaxLength int // 最大长度（字符数），<=0 时使用 DefaultMaxLength
+}
+
+// pinyinArgs 拼音转换参数：不带声调，多音字取常用读音
+var pinyinArgs = pinyin.NewArgs()
+
+// foldings NFKD 无法分解的拉丁字母
+var foldings = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d",
+	'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+}
+
+// Make 将文本转换为 slug，结果为空时返回短哈希
+func Make(s string, opts Options) string {
+	if result := Clean(s, opts); result != "" {
+		return result
+	}
+	return Fallback(s)
+}
+
+// Clean 将文本转换为 slug，无法转写出任何字符时返回空字符串
+func Clean(s string, opts Options) string {
+	var sb strings.Builder
+	dash := func() {
+		if sb.Len() > 0 && !strings.HasSuffix(sb.String(), "-") {
+			sb.WriteByte('-')
+		}
+	}
+
+	for _, r := range norm.NFKD.String(s) {
+		r = unicode.ToLower(r)
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// 分解后的重音符号直接丢弃：é → e
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			sb.WriteRune(r)
+		case unicode.Is(unicode.Han, r):
+			py := pinyin.SinglePinyin(r, pinyinArgs)
+			if len(py) == 0 {
+				dash()
+				continue
+			}
+			dash()
+			sb.WriteString(py[0])
+			dash()
+		case foldings[r] != "":
+			sb.WriteString(foldings[r])
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			sb.WriteRune(r)
+		default:
+			dash()
+		}
+	}
+
+	return truncate(strings.Trim(sb.String(), "-"), opts.maxLength())
+}
+
+// Fallback 生成短哈希作为兜底标识
+// 混入当前时间，同一标题多次生成的结果不同，避免反复冲突
+func Fallback(s string) string {
+	sum := sha1.Sum([]byte(s + "|" + strconv.FormatInt(time.Now().UnixNano(), 10)))
+	return hex.EncodeToString(sum[:])[:fallbackLength]
+}
+
+// truncate 截断到最大长度，尽量在连字符处断开，避免截断半个单词
+func truncate(s string, maxLength int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLength {
+		return s
+	}
+	cut := string(runes[:maxLength])
+	if i := strings.LastIndexByte(cut, '-'); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, "-")
+}
+
+// maxLength 返回有效的最大长度
+func (o Options) maxLength() int {
+	if o.MaxLength <= 0 {
+		return DefaultMaxLength
+	}
+	return o.MaxLength
+}
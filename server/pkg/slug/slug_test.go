@@ -0,0 +1,84 @@
+package slug
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		opts Options
+		want string
+	}{
+		{name: "英文小写并以连字符分隔", in: "Hello, World!", want: "hello-world"},
+		{name: "合并连续分隔符并去掉首尾连字符", in: "  --Go   1.23 -- ", want: "go-1-23"},
+		{name: "汉字逐字转写为拼音", in: "你好世界", want: "ni-hao-shi-jie"},
+		{name: "中英文混排", in: "Go语言入门", want: "go-yu-yan-ru-men"},
+		{name: "NFKD 去掉重音符号", in: "Café Crème", want: "cafe-creme"},
+		{name: "NFKD 兼容分解全角字符", in: "ＡＢＣ１２３", want: "abc123"},
+		{name: "NFKD 无法分解的拉丁字母", in: "Straße Æon Øre", want: "strasse-aeon-ore"},
+		{name: "其他语言字母原样保留", in: "Привет мир", want: "привет-мир"},
+		{name: "只有符号时返回空", in: "!!! ??? ...", want: ""},
+		{name: "按最大长度在连字符处截断", in: "alpha beta gamma delta", opts: Options{MaxLength: 14}, want: "alpha-beta"},
+		{name: "无法在连字符处截断时直接截断", in: "abcdefghijklmnop", opts: Options{MaxLength: 5}, want: "abcde"},
+		{name: "截断按字符计数", in: "привет", opts: Options{MaxLength: 3}, want: "при"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Clean(tc.in, tc.opts); got != tc.want {
+				t.Fatalf("Clean(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCleanDefaultMaxLength(t *testing.T) {
+	got := Clean(strings.Repeat("word ", 40), Options{})
+	if n := len([]rune(got)); n == 0 || n > DefaultMaxLength {
+		t.Fatalf("默认最大长度应为 %d, got %d (%q)", DefaultMaxLength, n, got)
+	}
+	if strings.HasSuffix(got, "-") {
+		t.Fatalf("截断后不应以连字符结尾: %q", got)
+	}
+}
+
+func TestMake(t *testing.T) {
+	hashPattern := regexp.MustCompile(`^[0-9a-f]{8}$`)
+
+	cases := []struct {
+		name     string
+		in       string
+		want     string
+		fallback bool
+	}{
+		{name: "可转写时与 Clean 相同", in: "Hello 世界", want: "hello-shi-jie"},
+		{name: "只有符号时使用短哈希", in: "!!!", fallback: true},
+		{name: "空字符串使用短哈希", in: "", fallback: true},
+		{name: "表情符号使用短哈希", in: "🎉🎉", fallback: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Make(tc.in, Options{})
+			if tc.fallback {
+				if !hashPattern.MatchString(got) {
+					t.Fatalf("Make(%q) = %q, want %d 位十六进制短哈希", tc.in, got, fallbackLength)
+				}
+				return
+			}
+			if got != tc.want {
+				t.Fatalf("Make(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFallbackDiffersPerCall(t *testing.T) {
+	if a, b := Fallback("同一标题"), Fallback("同一标题"); a == b {
+		t.Fatalf("同一文本多次生成的短哈希应不同, got %q twice", a)
+	}
+}